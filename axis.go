@@ -0,0 +1,71 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "errors"
+
+var errAxis = errors.New("rtree: invalid axis")
+
+// SearchAxis searches for items in the tree whose range along the provided
+// axis, 0 for X and 1 for Y, intersects the range from min to max,
+// regardless of their range on the other axis.
+// For example, SearchAxis(0, a, b, iter) returns all items with an X in
+// [a, b]. This avoids needing to use infinite values for the other axis,
+// which integer coordinate types do not have.
+func (tr *RTreeGN[N, T]) SearchAxis(axis int, min, max N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	if axis != 0 && axis != 1 {
+		panic(errAxis)
+	}
+	if tr.root == nil || min > tr.rect.max[axis] || max < tr.rect.min[axis] {
+		return
+	}
+	tr.root.searchAxis(axis, min, max, tr.guard(iter))
+}
+
+func (n *node[N, T]) searchAxis(axis int, min, max N,
+	iter func(min, max [2]N, data T) bool,
+) bool {
+	mins := n.rects.min[axis][:n.count]
+	maxs := n.rects.max[axis][:n.count]
+	if n.leaf() {
+		items := n.items()
+		for i := range mins {
+			if axis == 0 && n.ordered() && mins[i] > max {
+				break
+			}
+			if mins[i] <= max && maxs[i] >= min {
+				r := n.rects.at(i)
+				if !iter(r.min, r.max, items[i]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := range mins {
+		if axis == 0 && n.ordered() && mins[i] > max {
+			// the remaining children start to the right of the range
+			break
+		}
+		if mins[i] <= max && maxs[i] >= min {
+			if !children[i].searchAxis(axis, min, max, iter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SearchAxis searches for items in the tree whose range along the provided
+// axis, 0 for X and 1 for Y, intersects the range from min to max,
+// regardless of their range on the other axis.
+func (tr *RTreeG[T]) SearchAxis(axis int, min, max float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.SearchAxis(axis, min, max, iter)
+}
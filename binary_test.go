@@ -0,0 +1,64 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type intCodec struct{}
+
+func (intCodec) MarshalItem(data int) ([]byte, error) {
+	if data < 0 {
+		return nil, errors.New("negative")
+	}
+	return []byte(strconv.Itoa(data)), nil
+}
+
+func (intCodec) UnmarshalItem(b []byte) (int, error) {
+	return strconv.Atoi(string(b))
+}
+
+func TestBinary(t *testing.T) {
+	for _, codec := range []ItemCodec[int]{nil, intCodec{}} {
+		var tr1 RTreeG[int]
+		tr1.SetItemCodec(codec)
+		for i := 0; i < 1000; i++ {
+			r := randRect('r')
+			tr1.Insert(r.min, r.max, i)
+		}
+		// through gob
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(&tr1); err != nil {
+			t.Fatal(err)
+		}
+		var tr2 RTreeG[int]
+		tr2.SetItemCodec(codec)
+		if err := gob.NewDecoder(&buf).Decode(&tr2); err != nil {
+			t.Fatal(err)
+		}
+		if tr2.Len() != tr1.Len() {
+			t.Fatalf("expected %d, got %d", tr1.Len(), tr2.Len())
+		}
+		for _, item := range tr1.Items() {
+			if !tr2.Exists(item.Min, item.Max, item.Data) {
+				t.Fatalf("item %d not found", item.Data)
+			}
+		}
+		if err := tr2.UnmarshalBinary([]byte{0}); err != errBinaryVersion {
+			t.Fatalf("expected %v, got %v", errBinaryVersion, err)
+		}
+	}
+	var tr RTreeG[int]
+	tr.SetItemCodec(intCodec{})
+	tr.Insert([2]float64{1, 1}, [2]float64{1, 1}, -1)
+	if _, err := tr.MarshalBinary(); err == nil {
+		t.Fatal("expected error")
+	}
+}
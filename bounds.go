@@ -0,0 +1,73 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// RecalcBounds recomputes the bounding rectangle of every node in the tree,
+// shrinking any that are larger than the items they contain, and returns true
+// if any were changed.
+// The tree operations already keep the bounds exact, but an inflated bounding
+// rectangle still finds all items, so a problem with the bounds may otherwise
+// go unnoticed while making searches slower and Bounds less useful.
+// Only the nodes whose bounds are changed are copied when they're shared with
+// copies of the tree.
+func (tr *RTreeGN[N, T]) RecalcBounds() bool {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.root == nil || !tr.root.loose(&tr.rect) {
+		return false
+	}
+	if writeGuardEnabled {
+		tr.writes.enter()
+		defer tr.writes.exit()
+	}
+	tr.gen++
+	tr.recalcBounds(&tr.root, &tr.rect)
+	tr.fixAggs()
+	return true
+}
+
+// loose returns true if nr or any of the rectangles below the node are not
+// the minimum bounding rectangle of their children.
+func (n *node[N, T]) loose(nr *rect[N]) bool {
+	r := n.rect()
+	if !r.equals(nr) {
+		return true
+	}
+	if !n.leaf() {
+		children := n.children()
+		for i := 0; i < int(n.count); i++ {
+			if r := n.rects.at(i); children[i].loose(&r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (tr *RTreeGN[N, T]) recalcBounds(n **node[N, T], nr *rect[N]) {
+	if !(*n).leaf() {
+		var changed bool
+		for i := 0; i < int((*n).count); i++ {
+			if r := (*n).rects.at(i); (*n).children()[i].loose(&r) {
+				tr.cow(n)
+				tr.recalcBounds(&(*n).children()[i], &r)
+				(*n).rects.set(i, r)
+				changed = true
+			}
+		}
+		if changed && (*n).ordered() {
+			(*n).sort()
+		}
+	}
+	*nr = (*n).rect()
+}
+
+// RecalcBounds recomputes the bounding rectangle of every node in the tree,
+// shrinking any that are larger than the items they contain, and returns true
+// if any were changed.
+func (tr *RTreeG[T]) RecalcBounds() bool {
+	return tr.base.RecalcBounds()
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "context"
+
+// SearchChan searches the tree for items that intersect the provided
+// rectangle and sends them on the returned channel, which is closed once all
+// items have been sent or the context is done.
+//
+// The search runs on a copy-on-write snapshot of the tree, so the results
+// reflect the tree at the time of the call and the tree may be freely
+// modified while the results are being consumed.
+// Taking the snapshot calls Copy, which counts as a write to the tree: the
+// call needs the same exclusive access as Insert, and the next writes to
+// the tree copy the nodes that they modify. A frozen tree, see Freeze, is
+// never written, so it may be searched concurrently.
+// Cancel the context when not all results are read, otherwise the searching
+// goroutine is leaked.
+func (tr *RTreeGN[N, T]) SearchChan(ctx context.Context, min, max [2]N,
+) <-chan Item[N, T] {
+	ch := make(chan Item[N, T])
+	snap := tr.Copy()
+	go func() {
+		defer close(ch)
+		snap.Search(min, max, func(min, max [2]N, data T) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+			select {
+			case ch <- Item[N, T]{min, max, data}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return ch
+}
+
+// SearchChan searches the tree for items that intersect the provided
+// rectangle and sends them on the returned channel. It counts as a write to
+// the tree. See RTreeGN.SearchChan for details.
+func (tr *RTreeG[T]) SearchChan(ctx context.Context, min, max [2]float64,
+) <-chan Item[float64, T] {
+	return tr.base.SearchChan(ctx, min, max)
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClosestPair(t *testing.T) {
+	var tr1, tr2 RTreeG[int]
+	if _, _, _, ok := tr1.ClosestPair(&tr2); ok {
+		t.Fatal("expected false")
+	}
+	rects1 := make([]rect[float64], 2000)
+	for i := range rects1 {
+		rects1[i] = randRect('p')
+		tr1.Insert(rects1[i].min, rects1[i].max, i)
+	}
+	if _, _, _, ok := tr1.ClosestPair(&tr2); ok {
+		t.Fatal("expected false")
+	}
+	for n := 1; n <= 300; n *= 3 {
+		tr2.Clear()
+		rects2 := make([]rect[float64], n)
+		for i := range rects2 {
+			rects2[i] = randRect('p')
+			tr2.Insert(rects2[i].min, rects2[i].max, i)
+		}
+		expect := math.Inf(1)
+		for i := range rects1 {
+			for j := range rects2 {
+				expect = math.Min(expect, rects1[i].boxDist2(&rects2[j]))
+			}
+		}
+		expect = math.Sqrt(expect)
+		a, b, dist, ok := tr1.ClosestPair(&tr2)
+		if !ok || dist != expect {
+			t.Fatalf("expected %v, got %v", expect, dist)
+		}
+		if math.Sqrt(rects1[a].boxDist2(&rects2[b])) != dist {
+			t.Fatalf("pair %d %d is not at %v", a, b, dist)
+		}
+		b, a, dist, ok = tr2.ClosestPair(&tr1)
+		if !ok || dist != expect {
+			t.Fatalf("expected %v, got %v", expect, dist)
+		}
+		if math.Sqrt(rects1[a].boxDist2(&rects2[b])) != dist {
+			t.Fatalf("pair %d %d is not at %v", a, b, dist)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Command rtreebench measures the performance of the rtree package using
+// synthetic or real-world workloads.
+//
+// Usage:
+//
+//	rtreebench [-n 1000000] [-dist uniform|clustered] [-file data.csv]
+//
+// Real-world data, such as Tiger or OSM extracts, can be provided as a CSV
+// file where each line is either "x,y" for a point or "minx,miny,maxx,maxy"
+// for a rectangle.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buivuanh/rtree"
+)
+
+type rect struct {
+	min, max [2]float64
+}
+
+func main() {
+	n := flag.Int("n", 1000000, "number of items for synthetic workloads")
+	dist := flag.String("dist", "uniform", "synthetic distribution: "+
+		"uniform or clustered")
+	file := flag.String("file", "", "CSV file with real-world data, "+
+		"overrides -n and -dist")
+	queries := flag.Int("queries", 100000, "number of searches")
+	size := flag.Float64("size", 0.01, "size of each search as a "+
+		"fraction of the world")
+	seed := flag.Int64("seed", 0, "random seed, zero for the current time")
+	flag.Parse()
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+	var rects []rect
+	var err error
+	switch {
+	case *file != "":
+		rects, err = readFile(*file)
+	case *dist == "uniform":
+		rects = uniform(rng, *n)
+	case *dist == "clustered":
+		rects = clustered(rng, *n)
+	default:
+		err = fmt.Errorf("unknown distribution %q", *dist)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("items: %d, seed: %d\n", len(rects), *seed)
+
+	var tr rtree.RTreeG[int]
+	bench("insert", len(rects), func() {
+		for i, r := range rects {
+			tr.Insert(r.min, r.max, i)
+		}
+	})
+	printStats(tr.Stats())
+
+	targets := make([]rect, *queries)
+	for i := range targets {
+		targets[i] = query(rng, rects, *size)
+	}
+	var found int
+	bench("search", len(targets), func() {
+		for _, q := range targets {
+			tr.Search(q.min, q.max,
+				func(min, max [2]float64, data int) bool {
+					found++
+					return true
+				},
+			)
+		}
+	})
+	fmt.Printf("  %.1f items per search\n", float64(found)/float64(*queries))
+
+	bench("delete", len(rects), func() {
+		for i, r := range rects {
+			tr.Delete(r.min, r.max, i)
+		}
+	})
+
+	items := make([]rtree.Item[float64, int], len(rects))
+	for i, r := range rects {
+		items[i] = rtree.Item[float64, int]{Min: r.min, Max: r.max, Data: i}
+	}
+	bench("load bulk", len(items), func() {
+		tr.LoadBulkParallel(items, runtime.NumCPU())
+	})
+	printStats(tr.Stats())
+}
+
+func bench(name string, n int, fn func()) {
+	start := time.Now()
+	fn()
+	dur := time.Since(start)
+	fmt.Printf("%-10s %d ops in %s, %.0f ops/sec\n", name, n,
+		dur.Round(time.Millisecond), float64(n)/dur.Seconds())
+}
+
+func printStats(s rtree.Stats) {
+	fmt.Printf("  height: %d, leaves: %d (%.1f%% full), "+
+		"branches: %d (%.1f%% full)\n", s.Height, s.Leaves, s.LeafFill*100,
+		s.Branches, s.BranchFill*100)
+}
+
+func point(x, y float64) rect {
+	return rect{[2]float64{x, y}, [2]float64{x, y}}
+}
+
+// uniform returns points that are evenly distributed over the world.
+func uniform(rng *rand.Rand, n int) []rect {
+	rects := make([]rect, n)
+	for i := range rects {
+		rects[i] = point(rng.Float64()*360-180, rng.Float64()*180-90)
+	}
+	return rects
+}
+
+// clustered returns points that are grouped around a few random centers,
+// which is closer to most real-world data.
+func clustered(rng *rand.Rand, n int) []rect {
+	centers := make([][2]float64, 100)
+	for i := range centers {
+		centers[i] = [2]float64{rng.Float64()*360 - 180,
+			rng.Float64()*180 - 90}
+	}
+	rects := make([]rect, n)
+	for i := range rects {
+		c := centers[rng.Intn(len(centers))]
+		x := c[0] + rng.NormFloat64()*2
+		y := c[1] + rng.NormFloat64()*2
+		rects[i] = point(x, y)
+	}
+	return rects
+}
+
+// query returns a search rectangle that is centered on a random item.
+func query(rng *rand.Rand, rects []rect, size float64) rect {
+	var x, y float64
+	if len(rects) > 0 {
+		r := rects[rng.Intn(len(rects))]
+		x, y = r.min[0], r.min[1]
+	}
+	w, h := 360*size/2, 180*size/2
+	return rect{[2]float64{x - w, y - h}, [2]float64{x + w, y + h}}
+}
+
+// readFile reads points and rectangles from a CSV file.
+func readFile(path string) ([]rect, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var rects []rect
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.Split(text, ",")
+		if len(parts) != 2 && len(parts) != 4 {
+			return nil, fmt.Errorf("%s:%d: expected 2 or 4 values", path, line)
+		}
+		var vals [4]float64
+		for i, part := range parts {
+			vals[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+		}
+		if len(parts) == 2 {
+			rects = append(rects, point(vals[0], vals[1]))
+		} else {
+			rects = append(rects, rect{[2]float64{vals[0], vals[1]},
+				[2]float64{vals[2], vals[3]}})
+		}
+	}
+	return rects, s.Err()
+}
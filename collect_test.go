@@ -0,0 +1,81 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSearchAppend(t *testing.T) {
+	var tr RTreeG[int]
+	if got := tr.SearchAppend(nil, [2]float64{-180, -90},
+		[2]float64{180, 90}); len(got) != 0 {
+		t.Fatal("expected no items")
+	}
+	for i := 0; i < 5000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	var dst []int
+	for j := 0; j < 20; j++ {
+		r := randRect('r')
+		r.max[0] += 20
+		r.max[1] += 20
+		dst = tr.SearchAppend(dst[:0], r.min, r.max)
+		got := slices.Clone(dst)
+		slices.Sort(got)
+		if !slices.Equal(got, bulkSearch(&tr, r)) {
+			t.Fatal("mismatch")
+		}
+	}
+	dst = append(dst[:0], -1)
+	dst = tr.SearchAppend(dst, [2]float64{-180, -90}, [2]float64{180, 90})
+	if len(dst) != 5001 || dst[0] != -1 {
+		t.Fatal("expected the items to be appended")
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = tr.SearchAppend(dst[:0], [2]float64{-10, -10},
+			[2]float64{10, 10})
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestNearbyAppend(t *testing.T) {
+	var tr RTreeG[int]
+	if got := tr.NearbyAppend(nil, [2]float64{0, 0}, 10); len(got) != 0 {
+		t.Fatal("expected no items")
+	}
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	p := [2]float64{10, 20}
+	var expect []float64
+	tr.Nearby(BoxDist[float64, int](p, p, nil),
+		func(min, max [2]float64, data int, dist float64) bool {
+			expect = append(expect, dist)
+			return len(expect) < 50
+		},
+	)
+	target := rect[float64]{p, p}
+	var got []float64
+	for _, data := range tr.NearbyAppend(nil, p, 50) {
+		got = append(got, target.boxDist(&rects[data]))
+	}
+	if !slices.Equal(got, expect) {
+		t.Fatal("mismatch")
+	}
+	dst := make([]int, 0, 50)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = tr.NearbyAppend(dst[:0], p, 50)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrentRTree(t *testing.T) {
+	tr := NewConcurrentRTree[float64, int](nil)
+	const n = 2000
+	rects := make([]rect[float64], n)
+	for i := range rects {
+		rects[i] = randRect('r')
+	}
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() {
+				// a snapshot never changes
+				snap := tr.Load()
+				count := snap.Len()
+				var scanned int
+				snap.Scan(func(min, max [2]float64, data int) bool {
+					scanned++
+					return true
+				})
+				if scanned != count {
+					t.Errorf("expected %d, got %d", count, scanned)
+					return
+				}
+			}
+		}()
+	}
+	for i := range rects {
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	snap := tr.Load()
+	tr.Update(func(tr *RTreeGN[float64, int]) {
+		for i := 0; i < n; i += 2 {
+			tr.Delete(rects[i].min, rects[i].max, i)
+		}
+	})
+	for i := 1; i < n; i += 2 {
+		r := randRect('r')
+		if !tr.Replace(rects[i].min, rects[i].max, i, r.min, r.max, i) {
+			t.Fatalf("item %d not found", i)
+		}
+		rects[i] = r
+	}
+	done.Store(true)
+	wg.Wait()
+	if snap.Len() != n {
+		t.Fatalf("expected %d, got %d", n, snap.Len())
+	}
+	if tr.Len() != n/2 {
+		t.Fatalf("expected %d, got %d", n/2, tr.Len())
+	}
+	if !tr.Load().Frozen() {
+		t.Fatal("expected a frozen snapshot")
+	}
+	if err := rSane(&RTreeG[int]{*tr.Load()}); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Delete(rects[0].min, rects[0].max, 0) {
+		t.Fatal("expected false")
+	}
+	for i := 1; i < n; i += 2 {
+		if !tr.Delete(rects[i].min, rects[i].max, i) {
+			t.Fatalf("item %d not found", i)
+		}
+	}
+	// nothing is published when the update panics
+	expectPanic(t, func() {
+		tr.Update(func(tr *RTreeGN[float64, int]) {
+			tr.Insert(rects[0].min, rects[0].max, 0)
+			panic("fail")
+		})
+	})
+	if tr.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, tr.Len())
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func diffItems(tr1, tr2 *RTreeG[int]) (ins, del []int) {
+	tr1.Diff(tr2,
+		func(min, max [2]float64, data int) { ins = append(ins, data) },
+		func(min, max [2]float64, data int) { del = append(del, data) },
+	)
+	sort.Ints(ins)
+	sort.Ints(del)
+	return ins, del
+}
+
+func TestDiff(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	snap := tr.Copy()
+	if ins, del := diffItems(&tr, snap); len(ins) != 0 || len(del) != 0 {
+		t.Fatalf("expected no changes, got %v %v", ins, del)
+	}
+	var expectIns, expectDel []int
+	for _, i := range rand.Perm(len(rects))[:100] {
+		tr.Delete(rects[i].min, rects[i].max, i)
+		expectDel = append(expectDel, i)
+	}
+	for i := len(rects); i < len(rects)+50; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+		expectIns = append(expectIns, i)
+	}
+	sort.Ints(expectDel)
+	check := func(tr1, tr2 *RTreeG[int], expectIns, expectDel []int) {
+		t.Helper()
+		ins, del := diffItems(tr1, tr2)
+		if len(ins) != len(expectIns) || len(del) != len(expectDel) {
+			t.Fatalf("expected %d/%d, got %d/%d", len(expectIns),
+				len(expectDel), len(ins), len(del))
+		}
+		for i := range ins {
+			if ins[i] != expectIns[i] {
+				t.Fatalf("expected %d, got %d", expectIns[i], ins[i])
+			}
+		}
+		for i := range del {
+			if del[i] != expectDel[i] {
+				t.Fatalf("expected %d, got %d", expectDel[i], del[i])
+			}
+		}
+	}
+	check(&tr, snap, expectIns, expectDel)
+	check(snap, &tr, expectDel, expectIns)
+
+	// trees that share nothing
+	var tr2 RTreeG[int]
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		tr2.Insert(min, max, data)
+		return true
+	})
+	check(&tr2, snap, expectIns, expectDel)
+	var empty RTreeG[int]
+	ins, del := diffItems(&empty, &tr2)
+	if len(ins) != 0 || len(del) != tr2.Len() {
+		t.Fatalf("expected %d/%d, got %d/%d", 0, tr2.Len(), len(ins), len(del))
+	}
+}
+
+func TestDiffHashes(t *testing.T) {
+	encode := func(data int) []byte { return []byte{byte(data), byte(data >> 8)} }
+	var items []Item[float64, int]
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		items = append(items, Item[float64, int]{r.min, r.max, i})
+	}
+	// replicas that are built separately share no nodes
+	var tr1, tr2 RTreeG[int]
+	tr1.SetHash(encode)
+	tr2.SetHash(encode)
+	tr1.LoadBulk(items)
+	tr2.LoadBulk(items)
+	diff := func(tr1, tr2 *RTreeG[int]) (ins, del []int) {
+		tr1.DiffHashes(tr2,
+			func(min, max [2]float64, data int) { ins = append(ins, data) },
+			func(min, max [2]float64, data int) { del = append(del, data) },
+		)
+		sort.Ints(ins)
+		sort.Ints(del)
+		return ins, del
+	}
+	if ins, del := diff(&tr1, &tr2); len(ins) != 0 || len(del) != 0 {
+		t.Fatalf("expected no changes, got %v %v", ins, del)
+	}
+	var expectIns, expectDel []int
+	for _, i := range rand.Perm(len(items))[:20] {
+		tr1.Delete(items[i].Min, items[i].Max, items[i].Data)
+		expectDel = append(expectDel, i)
+	}
+	for i := len(items); i < len(items)+10; i++ {
+		r := randRect('r')
+		tr1.Insert(r.min, r.max, i)
+		expectIns = append(expectIns, i)
+	}
+	sort.Ints(expectDel)
+	ins, del := diff(&tr1, &tr2)
+	if !slices.Equal(ins, expectIns) || !slices.Equal(del, expectDel) {
+		t.Fatalf("expected %v %v, got %v %v", expectIns, expectDel, ins, del)
+	}
+	ins, del = diff(&tr2, &tr1)
+	if !slices.Equal(ins, expectDel) || !slices.Equal(del, expectIns) {
+		t.Fatalf("expected %v %v, got %v %v", expectDel, expectIns, ins, del)
+	}
+	// without hashes only the shared nodes are skipped
+	tr2.SetHash(nil)
+	ins, del = diff(&tr1, &tr2)
+	if !slices.Equal(ins, expectIns) || !slices.Equal(del, expectDel) {
+		t.Fatalf("expected %v %v, got %v %v", expectIns, expectDel, ins, del)
+	}
+}
+
+func TestDiffUncomparable(t *testing.T) {
+	tr := New(WithComparator[float64, []int](slices.Equal[[]int]))
+	rects := make([]rect[float64], 1000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, []int{i})
+	}
+	snap := tr.Copy()
+	tr.Delete(rects[10].min, rects[10].max, []int{10})
+	// same rect, other data
+	tr.Insert(rects[20].min, rects[20].max, []int{-20})
+	var ins, del [][]int
+	tr.Diff(snap,
+		func(min, max [2]float64, data []int) { ins = append(ins, data) },
+		func(min, max [2]float64, data []int) { del = append(del, data) },
+	)
+	if len(ins) != 1 || ins[0][0] != -20 || len(del) != 1 || del[0][0] != 10 {
+		t.Fatalf("expected [[-20]] [[10]], got %v %v", ins, del)
+	}
+}
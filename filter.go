@@ -0,0 +1,104 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "sync/atomic"
+
+// Filter returns a new tree with only the items for which pred returns true.
+// The tree itself is not modified.
+//
+// The new tree is built bottom-up. The matching items are packed into full
+// leaves in the order that they are found in the tree, which keeps items
+// that are near each other together, and the leaves are then packed into
+// branches like LoadBulk does. This is much faster than inserting the items
+// into a new tree.
+// The pred function must not modify the tree.
+//
+// The new tree has the options, aggregators, allocator and hooks of the
+// tree, but not its logger or subscriptions, as its items were never
+// inserted into the tree.
+func (tr *RTreeGN[N, T]) Filter(pred func(min, max [2]N, data T) bool,
+) *RTreeGN[N, T] {
+	tr2 := new(RTreeGN[N, T])
+	*tr2 = *tr
+	tr2.writes = writeGuard{}
+	tr2.frozen = false
+	tr2.icow = atomic.AddUint64(&gcow, 1)
+	tr2.gen++
+	tr2.count = 0
+	tr2.root = nil
+	tr2.rect = rect[N]{}
+	tr2.detach()
+	if tr.root == nil {
+		return tr2
+	}
+	leaves := tr.root.filter(tr2, tr.guard(pred), nil)
+	if len(leaves) == 0 {
+		return tr2
+	}
+	for _, leaf := range leaves {
+		tr2.count += int(leaf.count)
+		if leaf.ordered() && !leaf.issorted() {
+			leaf.sort()
+		}
+	}
+	for len(leaves) > 1 {
+		leaves = tr2.buildBulkLevel(leaves, 1)
+	}
+	tr2.root = leaves[0]
+	tr2.rect = tr2.root.rect()
+	tr2.fixAggs()
+	return tr2
+}
+
+// detach removes what a new tree that is derived from another tree, such as
+// by Filter or Partition, must not share with it: the logger and the
+// subscriptions, as the items of the new tree were never inserted into it,
+// and the memory estimate.
+func (tr *RTreeGN[N, T]) detach() {
+	tr.mem = memEstimate{}
+	tr.logger = nil
+	tr.subs = nil
+}
+
+// filter appends the items of the node for which pred returns true to the
+// last of the leaves, which are new leaves of tr, adding another leaf when
+// the last one is full.
+func (n *node[N, T]) filter(tr *RTreeGN[N, T],
+	pred func(min, max [2]N, data T) bool, leaves []*node[N, T],
+) []*node[N, T] {
+	if !n.leaf() {
+		children := n.children()[:n.count]
+		for i := range children {
+			leaves = children[i].filter(tr, pred, leaves)
+		}
+		return leaves
+	}
+	items, seqs := n.items(), n.seqs()
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		if !pred(r.min, r.max, items[i]) {
+			continue
+		}
+		if len(leaves) == 0 || int(leaves[len(leaves)-1].count) == tr.fanout(true) {
+			leaves = append(leaves, tr.newNode(true))
+		}
+		dst := leaves[len(leaves)-1]
+		dst.rects.set(int(dst.count), r)
+		dst.items()[dst.count] = items[i]
+		if seqs != nil && seqs[i] != 0 {
+			dst.allocSeqs()[dst.count] = seqs[i]
+		}
+		dst.count++
+	}
+	return leaves
+}
+
+// Filter returns a new tree with only the items for which pred returns true.
+// See RTreeGN.Filter.
+func (tr *RTreeG[T]) Filter(pred func(min, max [2]float64, data T) bool,
+) *RTreeG[T] {
+	return &RTreeG[T]{*tr.base.Filter(pred)}
+}
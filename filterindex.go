@@ -0,0 +1,178 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "hash/maphash"
+
+// Filter describes a summary of the attributes of items, such as a bloom
+// filter of their tags, which is maintained for every node of a tree once
+// the filter is registered with RegisterFilter. Searches use the summaries
+// to skip the nodes that cannot have any matching items, which allows for
+// combined spatial and attribute searches without a second index.
+type Filter[T, S any] interface {
+	// Summary returns the summary of a single item.
+	Summary(data T) S
+	// Merge returns the combined summary of a and b, which must match
+	// everything that a or b matches.
+	// Merge must be associative and commutative.
+	Merge(a, b S) S
+}
+
+// FilterIndex is a Filter that has been registered with a tree.
+type FilterIndex[N numeric, T, S any] struct {
+	tr *RTreeGN[N, T]
+	ai *aggIndex[N, T, S]
+}
+
+// RegisterFilter adds a filter to the tree.
+// The summary of every node is computed immediately and maintained from then
+// on, as items are inserted and deleted, and nodes are split and merged.
+//
+// Copies of the tree, see Copy, continue to maintain all filters that were
+// registered before the copy was made.
+func RegisterFilter[N numeric, T, S any](tr *RTreeGN[N, T],
+	filter Filter[T, S],
+) *FilterIndex[N, T, S] {
+	ai := &aggIndex[N, T, S]{agg: Aggregator[T, S]{
+		Item:  filter.Summary,
+		Merge: filter.Merge,
+	}}
+	tr.addAggregator(ai, &ai.idx)
+	return &FilterIndex[N, T, S]{tr: tr, ai: ai}
+}
+
+// RegisterFilterG adds a filter to the tree.
+// See RegisterFilter.
+func RegisterFilterG[T, S any](tr *RTreeG[T], filter Filter[T, S],
+) *FilterIndex[float64, T, S] {
+	return RegisterFilter(&tr.base, filter)
+}
+
+// Unregister removes the filter from the tree.
+func (fi *FilterIndex[N, T, S]) Unregister() {
+	fi.tr.removeAggregator(fi.ai.idx)
+}
+
+// In returns the same filter for a copy of the tree that the filter was
+// originally registered with.
+func (fi *FilterIndex[N, T, S]) In(tr *RTreeGN[N, T]) *FilterIndex[N, T, S] {
+	return &FilterIndex[N, T, S]{tr: tr, ai: fi.ai}
+}
+
+// Search searches for items that intersect the provided rectangle and for
+// which match returns true.
+// The mayMatch function is called with the summary of a node, and returns
+// false when none of the items of the node can match, which skips the node.
+// When match is nil, an item matches when mayMatch returns true for the
+// summary of the item itself, which is only exact when the summaries are,
+// unlike bloom filters.
+func (fi *FilterIndex[N, T, S]) Search(min, max [2]N,
+	mayMatch func(summary S) bool, match func(data T) bool,
+	iter func(min, max [2]N, data T) bool,
+) {
+	if match == nil {
+		match = func(data T) bool {
+			return mayMatch(fi.ai.agg.Item(data))
+		}
+	}
+	fi.ai.search(fi.tr, &rect[N]{min, max}, mayMatch, match, iter)
+}
+
+// search searches for the items that intersect the target and for which
+// match returns true, skipping the nodes whose aggregate value fails
+// mayMatch.
+func (ai *aggIndex[N, T, A]) search(tr *RTreeGN[N, T], target *rect[N],
+	mayMatch func(agg A) bool, match func(data T) bool,
+	iter func(min, max [2]N, data T) bool,
+) {
+	if tr.root == nil || !target.intersects(&tr.rect) ||
+		!mayMatch(tr.root.aggs.vals[ai.idx].(A)) {
+		return
+	}
+	ai.nodeSearch(tr.root, target, mayMatch, match, tr.guard(iter))
+}
+
+func (ai *aggIndex[N, T, A]) nodeSearch(n *node[N, T], target *rect[N],
+	mayMatch func(agg A) bool, match func(data T) bool,
+	iter func(min, max [2]N, data T) bool,
+) bool {
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if target.intersects(&r) && match(items[i]) &&
+				!iter(r.min, r.max, items[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		if !target.intersects(&r) ||
+			!mayMatch(children[i].aggs.vals[ai.idx].(A)) {
+			continue
+		}
+		if !ai.nodeSearch(children[i], target, mayMatch, match, iter) {
+			return false
+		}
+	}
+	return true
+}
+
+// TagBloom is a 256-bit bloom filter of tags.
+type TagBloom [4]uint64
+
+// tagSeed is the seed for hashing the tags of a TagBloom, which is the same
+// for the lifetime of the process, as the filters are only kept in memory.
+var tagSeed = maphash.MakeSeed()
+
+// Add adds a tag to the bloom filter.
+func (b *TagBloom) Add(tag string) {
+	h := maphash.String(tagSeed, tag)
+	for i := 0; i < 3; i++ {
+		bit := (h >> (i * 8)) & 255
+		b[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// MayHave returns false when the tag was never added to the bloom filter.
+// It may return true for tags that were not added.
+func (b TagBloom) MayHave(tag string) bool {
+	var t TagBloom
+	t.Add(tag)
+	for i := range b {
+		if b[i]&t[i] != t[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TagFilter is a Filter for the string tags of items, such as the kinds of
+// a point of interest, which keeps a bloom filter of the tags of the items
+// of every node.
+type TagFilter[T any] struct {
+	// Tags returns the tags of an item.
+	Tags func(data T) []string
+}
+
+// Summary returns the bloom filter of the tags of an item.
+func (f TagFilter[T]) Summary(data T) TagBloom {
+	var b TagBloom
+	for _, tag := range f.Tags(data) {
+		b.Add(tag)
+	}
+	return b
+}
+
+// Merge returns the union of two bloom filters.
+func (f TagFilter[T]) Merge(a, b TagBloom) TagBloom {
+	for i := range a {
+		a[i] |= b[i]
+	}
+	return a
+}
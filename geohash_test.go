@@ -0,0 +1,73 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeohashBounds(t *testing.T) {
+	min, max, ok := GeohashBounds("")
+	if !ok || min != [2]float64{-180, -90} || max != [2]float64{180, 90} {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	min, max, _ = GeohashBounds("u")
+	if min != [2]float64{0, 45} || max != [2]float64{45, 90} {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	// ezs42 is the example from the geohash wikipedia page
+	min, max, ok = GeohashBounds("EZS42")
+	if !ok || math.Abs((min[1]+max[1])/2-42.605) > 0.001 ||
+		math.Abs((min[0]+max[0])/2+5.603) > 0.001 ||
+		math.Abs(max[0]-min[0]-0.0439453125) > 1e-9 {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	for _, hash := range []string{"a", "ezs4i", "u!"} {
+		if _, _, ok := GeohashBounds(hash); ok {
+			t.Fatalf("expected invalid geohash %q", hash)
+		}
+	}
+}
+
+func TestQuadkeyBounds(t *testing.T) {
+	min, max, ok := QuadkeyBounds("")
+	if !ok || min[0] != -180 || max[0] != 180 {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	// quadkey 213 is tile 3,5 at zoom 3
+	min, max, _ = QuadkeyBounds("213")
+	emin, emax := TileBounds(3, 3, 5)
+	if min != emin || max != emax {
+		t.Fatalf("expected %v %v, got %v %v", emin, emax, min, max)
+	}
+	for _, key := range []string{"4", "12a"} {
+		if _, _, ok := QuadkeyBounds(key); ok {
+			t.Fatalf("expected invalid quadkey %q", key)
+		}
+	}
+}
+
+func TestSearchGeohash(t *testing.T) {
+	var tr RTreeG[int]
+	tr.Insert([2]float64{10, 50}, [2]float64{10, 50}, 1)
+	tr.Insert([2]float64{-10, 50}, [2]float64{-10, 50}, 2)
+	var found []int
+	iter := func(min, max [2]float64, data int) bool {
+		found = append(found, data)
+		return true
+	}
+	if !tr.SearchGeohash("u", iter) || len(found) != 1 || found[0] != 1 {
+		t.Fatalf("expected %v, got %v", []int{1}, found)
+	}
+	found = nil
+	// quadkey 0 is the north-west quarter
+	if !tr.SearchQuadkey("0", iter) || len(found) != 1 || found[0] != 2 {
+		t.Fatalf("expected %v, got %v", []int{2}, found)
+	}
+	if tr.SearchGeohash("a", iter) || tr.SearchQuadkey("9", iter) {
+		t.Fatal("expected invalid keys")
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/buivuanh/rtree
 
-go 1.18
+go 1.23
 
 require (
 	github.com/tidwall/geoindex v1.7.0
@@ -0,0 +1,48 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestHooks(t *testing.T) {
+	var splits, cows, allocs [2]int
+	hooks := &Hooks{
+		OnSplit:       func(leaf bool) { splits[b2i(leaf)]++ },
+		OnCopyOnWrite: func(leaf bool) { cows[b2i(leaf)]++ },
+		OnNodeAlloc:   func(leaf bool) { allocs[b2i(leaf)]++ },
+	}
+	var tr RTreeG[int]
+	tr.SetHooks(hooks)
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	s := tr.Stats()
+	if allocs != [2]int{s.Branches, s.Leaves} || splits[1] != s.Leaves-1 {
+		t.Fatalf("unexpected counts: splits %v, allocs %v, stats %+v",
+			splits, allocs, s)
+	}
+	if cows != [2]int{} {
+		t.Fatalf("expected no copies, got %v", cows)
+	}
+	tr2 := tr.Copy()
+	tr2.Insert(rects[0].min, rects[0].max, -1)
+	if cows[1] != 1 || cows[0] != s.Height-1 {
+		t.Fatalf("expected %d/%d copies, got %v", 1, s.Height-1, cows)
+	}
+	tr.SetHooks(nil)
+	tr.Insert(rects[0].min, rects[0].max, -1)
+	if cows[1] != 1 {
+		t.Fatalf("expected %d copies, got %v", 1, cows)
+	}
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestImportStream(t *testing.T) {
+	var tr RTreeG[int]
+	N := 10000
+	for i := 0; i < N; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	encode := func(data int) ([]byte, error) {
+		return strconv.AppendInt(nil, int64(data), 10), nil
+	}
+	decode := func(b []byte) (int, error) {
+		return strconv.Atoi(string(b))
+	}
+	for _, chunkItems := range []int{1, 7, 100, 3000, N} {
+		var buf bytes.Buffer
+		if err := tr.Export(&buf, chunkItems, encode); err != nil {
+			t.Fatal(err)
+		}
+		for _, ordered := range []bool{false, true} {
+			tr2 := New(WithOrdering[float64, int](ordered))
+			// start with some items, which the chunks are added to
+			for i := N; i < N+100; i++ {
+				r := randRect('r')
+				tr2.Insert(r.min, r.max, i)
+			}
+			err := tr2.ImportStream(bytes.NewReader(buf.Bytes()), decode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tr2.SanityCheck(); err != nil {
+				t.Fatal(err)
+			}
+			if tr2.Len() != N+100 {
+				t.Fatalf("expected %d, got %d", N+100, tr2.Len())
+			}
+			tr.Scan(func(min, max [2]float64, data int) bool {
+				var found bool
+				tr2.Search(min, max, func(min2, max2 [2]float64,
+					data2 int) bool {
+					found = data2 == data && min2 == min && max2 == max
+					return !found
+				})
+				if !found {
+					t.Fatalf("item %d not found", data)
+				}
+				return true
+			})
+		}
+	}
+
+	// errors
+	var buf bytes.Buffer
+	tr.Export(&buf, 100, encode)
+	var tr2 RTreeG[int]
+	err := tr2.ImportStream(bytes.NewReader(buf.Bytes()[:buf.Len()-1]),
+		decode)
+	if err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+	if tr2.Len() == 0 || tr2.Len()%100 != 0 {
+		t.Fatalf("expected the complete chunks, got %d items", tr2.Len())
+	}
+	errDecode := errors.New("decode")
+	err = tr2.ImportStream(bytes.NewReader(buf.Bytes()),
+		func(b []byte) (int, error) { return 0, errDecode })
+	if err != errDecode {
+		t.Fatalf("expected %v, got %v", errDecode, err)
+	}
+	err = tr2.ImportStream(bytes.NewReader([]byte{0}), decode)
+	if err != errExportVersion {
+		t.Fatalf("expected %v, got %v", errExportVersion, err)
+	}
+}
+
+func TestConcurrentImportStream(t *testing.T) {
+	var tr RTreeG[int]
+	N := 5000
+	for i := 0; i < N; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	var buf bytes.Buffer
+	tr.Export(&buf, 250, func(data int) ([]byte, error) {
+		return strconv.AppendInt(nil, int64(data), 10), nil
+	})
+	ctr := NewConcurrentRTree[float64, int](nil)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		last := 0
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// every snapshot holds complete chunks
+			n := ctr.Len()
+			if n < last || n%250 != 0 {
+				t.Errorf("unexpected %d items after %d", n, last)
+				return
+			}
+			last = n
+		}
+	}()
+	err := ctr.ImportStream(&buf, func(b []byte) (int, error) {
+		return strconv.Atoi(string(b))
+	})
+	close(done)
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctr.Len() != N {
+		t.Fatalf("expected %d, got %d", N, ctr.Len())
+	}
+	if err := ctr.Load().SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "math"
+
+// RTreeIndexed is an R-tree that keeps the data of its items in a separate
+// slab, while the leaves only hold a 4-byte handle for each item.
+// This makes the leaves much smaller for large data types, such as structs,
+// so that more of the tree fits in the CPU caches and searching reads less
+// memory. The data of an item is only read when it's passed to an iterator.
+//
+// The zero value is an empty tree that is ready to use.
+type RTreeIndexed[N numeric, T any] struct {
+	tr   RTreeGN[N, uint32]
+	slab []T      // data of the items, by handle
+	free []uint32 // handles of deleted items, for reuse
+}
+
+// Insert data into the tree.
+func (tr *RTreeIndexed[N, T]) Insert(min, max [2]N, data T) {
+	var h uint32
+	if len(tr.free) > 0 {
+		h = tr.free[len(tr.free)-1]
+		tr.free = tr.free[:len(tr.free)-1]
+		tr.slab[h] = data
+	} else {
+		if uint64(len(tr.slab)) > math.MaxUint32 {
+			panic(ErrCapacity)
+		}
+		h = uint32(len(tr.slab))
+		tr.slab = append(tr.slab, data)
+	}
+	tr.tr.Insert(min, max, h)
+}
+
+// handle returns the handle of the item, or false when the item does not
+// exist.
+func (tr *RTreeIndexed[N, T]) handle(min, max [2]N, data T) (uint32, bool) {
+	var h uint32
+	var found bool
+	tr.tr.Search(min, max, func(min2, max2 [2]N, h2 uint32) bool {
+		if min2 == min && max2 == max && compare(tr.slab[h2], data) {
+			h, found = h2, true
+			return false
+		}
+		return true
+	})
+	return h, found
+}
+
+// delete the item and returns false when the item does not exist.
+func (tr *RTreeIndexed[N, T]) delete(min, max [2]N, data T) bool {
+	h, ok := tr.handle(min, max, data)
+	if !ok {
+		return false
+	}
+	tr.tr.Delete(min, max, h)
+	var empty T
+	tr.slab[h] = empty
+	tr.free = append(tr.free, h)
+	return true
+}
+
+// Delete data from the tree.
+func (tr *RTreeIndexed[N, T]) Delete(min, max [2]N, data T) {
+	tr.delete(min, max, data)
+}
+
+// Replace an item.
+// If the old item does not exist then the new item is not inserted.
+func (tr *RTreeIndexed[N, T]) Replace(
+	oldMin, oldMax [2]N, oldData T,
+	newMin, newMax [2]N, newData T,
+) {
+	if tr.delete(oldMin, oldMax, oldData) {
+		tr.Insert(newMin, newMax, newData)
+	}
+}
+
+// Len returns the number of items in the tree.
+func (tr *RTreeIndexed[N, T]) Len() int {
+	return tr.tr.Len()
+}
+
+// Bounds returns the minimum bounding rect.
+func (tr *RTreeIndexed[N, T]) Bounds() (min, max [2]N) {
+	return tr.tr.Bounds()
+}
+
+// Search for items that intersect the provided rectangle.
+func (tr *RTreeIndexed[N, T]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	tr.tr.Search(min, max, func(min, max [2]N, h uint32) bool {
+		return iter(min, max, tr.slab[h])
+	})
+}
+
+// Scan iterates through all items in the tree.
+func (tr *RTreeIndexed[N, T]) Scan(iter func(min, max [2]N, data T) bool) {
+	tr.tr.Scan(func(min, max [2]N, h uint32) bool {
+		return iter(min, max, tr.slab[h])
+	})
+}
+
+// Nearby performs a kNN-type operation on the tree, like RTreeGN.Nearby.
+func (tr *RTreeIndexed[N, T]) Nearby(
+	dist func(min, max [2]N, data T, item bool) N,
+	iter func(min, max [2]N, data T, dist N) bool,
+) {
+	var empty T
+	tr.tr.Nearby(
+		func(min, max [2]N, h uint32, item bool) N {
+			if !item {
+				return dist(min, max, empty, false)
+			}
+			return dist(min, max, tr.slab[h], true)
+		},
+		func(min, max [2]N, h uint32, dist N) bool {
+			return iter(min, max, tr.slab[h], dist)
+		},
+	)
+}
+
+// Clear will delete all items.
+func (tr *RTreeIndexed[N, T]) Clear() {
+	tr.tr.Clear()
+	tr.slab = nil
+	tr.free = nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+type indexedTestData struct {
+	id   int
+	name [64]byte
+}
+
+func TestRTreeIndexed(t *testing.T) {
+	var tr RTreeIndexed[float64, indexedTestData]
+	var expect RTreeG[indexedTestData]
+	const n = 10000
+	rects := make([]rect[float64], n)
+	for i := range rects {
+		rects[i] = randRect('r')
+		data := indexedTestData{id: i}
+		data.name[0] = byte(i)
+		tr.Insert(rects[i].min, rects[i].max, data)
+		expect.Insert(rects[i].min, rects[i].max, data)
+	}
+	check := func() {
+		t.Helper()
+		if tr.Len() != expect.Len() {
+			t.Fatalf("expected %d, got %d", expect.Len(), tr.Len())
+		}
+		min1, max1 := tr.Bounds()
+		min2, max2 := expect.Bounds()
+		if min1 != min2 || max1 != max2 {
+			t.Fatal("bounds mismatch")
+		}
+		for i := 0; i < 100; i++ {
+			q := randRect('r')
+			var ids1, ids2 []int
+			tr.Search(q.min, q.max, func(min, max [2]float64,
+				data indexedTestData) bool {
+				ids1 = append(ids1, data.id)
+				return true
+			})
+			expect.Search(q.min, q.max, func(min, max [2]float64,
+				data indexedTestData) bool {
+				ids2 = append(ids2, data.id)
+				return true
+			})
+			slices.Sort(ids1)
+			slices.Sort(ids2)
+			if !slices.Equal(ids1, ids2) {
+				t.Fatalf("expected %v, got %v", ids2, ids1)
+			}
+		}
+	}
+	check()
+	for i := 0; i < n; i += 2 {
+		data := indexedTestData{id: i}
+		data.name[0] = byte(i)
+		tr.Delete(rects[i].min, rects[i].max, data)
+		expect.Delete(rects[i].min, rects[i].max, data)
+	}
+	// deleting an item that doesn't exist
+	tr.Delete(rects[1].min, rects[1].max, indexedTestData{id: -1})
+	check()
+	if len(tr.free) != n/2 {
+		t.Fatalf("expected %d free handles, got %d", n/2, len(tr.free))
+	}
+	// the free handles are reused
+	for i := 0; i < n; i += 2 {
+		data := indexedTestData{id: n + i}
+		tr.Insert(rects[i].min, rects[i].max, data)
+		expect.Insert(rects[i].min, rects[i].max, data)
+	}
+	check()
+	if len(tr.slab) != n || len(tr.free) != 0 {
+		t.Fatalf("expected %d items in slab, got %d", n, len(tr.slab))
+	}
+	r := randRect('r')
+	old := indexedTestData{id: 1}
+	old.name[0] = 1
+	tr.Replace(rects[1].min, rects[1].max, old, r.min, r.max,
+		indexedTestData{id: -2})
+	expect.Replace(rects[1].min, rects[1].max, old, r.min, r.max,
+		indexedTestData{id: -2})
+	check()
+
+	p := [2]float64{10, 10}
+	var dists1, dists2 []float64
+	tr.Nearby(BoxDist[float64, indexedTestData](p, p, nil),
+		func(min, max [2]float64, data indexedTestData, dist float64) bool {
+			dists1 = append(dists1, dist)
+			return len(dists1) < 10
+		})
+	expect.Nearby(BoxDist[float64, indexedTestData](p, p, nil),
+		func(min, max [2]float64, data indexedTestData, dist float64) bool {
+			dists2 = append(dists2, dist)
+			return len(dists2) < 10
+		})
+	if len(dists1) != 10 || !slices.Equal(dists1, dists2) {
+		t.Fatalf("expected %v, got %v", dists2, dists1)
+	}
+	var count int
+	tr.Scan(func(min, max [2]float64, data indexedTestData) bool {
+		count++
+		return true
+	})
+	if count != n {
+		t.Fatalf("expected %d, got %d", n, count)
+	}
+	tr.Clear()
+	if tr.Len() != 0 || len(tr.slab) != 0 {
+		t.Fatal("expected empty tree")
+	}
+}
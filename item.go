@@ -0,0 +1,64 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "iter"
+
+// Item is a single item in a tree, along with its rectangle.
+// It's used by the APIs that load, return, or iterate over items, so results
+// can be stored, sorted, and passed around without losing their rectangles.
+type Item[N numeric, T any] struct {
+	Min  [2]N `json:"min"`
+	Max  [2]N `json:"max"`
+	Data T    `json:"data"`
+}
+
+// Rect returns the rectangle of the item.
+func (item Item[N, T]) Rect() (min, max [2]N) {
+	return item.Min, item.Max
+}
+
+// SearchItems appends every item in tree that intersects the provided
+// rectangle to dst and returns the extended slice.
+// Nothing is allocated when dst has enough capacity.
+func (tr *RTreeGN[N, T]) SearchItems(dst []Item[N, T], min, max [2]N,
+) []Item[N, T] {
+	target := rect[N]{min, max}
+	if tr.root == nil || !target.intersects(&tr.rect) {
+		return dst
+	}
+	tr.root.search(target, func(min, max [2]N, data T) bool {
+		dst = append(dst, Item[N, T]{min, max, data})
+		return true
+	})
+	return dst
+}
+
+// SearchIter returns an iterator over all items in tree that intersect the
+// provided rectangle.
+//
+//	for item := range tr.SearchIter(min, max) {
+//		println(item.Data)
+//	}
+func (tr *RTreeGN[N, T]) SearchIter(min, max [2]N) iter.Seq[Item[N, T]] {
+	return func(yield func(Item[N, T]) bool) {
+		tr.Search(min, max, func(min, max [2]N, data T) bool {
+			return yield(Item[N, T]{min, max, data})
+		})
+	}
+}
+
+// SearchItems appends every item in tree that intersects the provided
+// rectangle to dst and returns the extended slice.
+func (tr *RTreeG[T]) SearchItems(dst []Item[float64, T], min, max [2]float64,
+) []Item[float64, T] {
+	return tr.base.SearchItems(dst, min, max)
+}
+
+// SearchIter returns an iterator over all items in tree that intersect the
+// provided rectangle.
+func (tr *RTreeG[T]) SearchIter(min, max [2]float64) iter.Seq[Item[float64, T]] {
+	return tr.base.SearchIter(min, max)
+}
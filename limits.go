@@ -0,0 +1,109 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrCapacity is returned by TryInsert when the tree has reached its maximum
+// number of items or its maximum memory.
+var ErrCapacity = errors.New("rtree: capacity exceeded")
+
+// memEstimate is the last measured memory usage of a tree.
+type memEstimate struct {
+	bytes int64
+	items int
+}
+
+// WithMaxItems sets the maximum number of items in the tree. TryInsert
+// returns ErrCapacity once the tree has that many items.
+// Insert, which can't return an error, is not limited.
+// The default of zero is no limit.
+func WithMaxItems[N numeric, T any](n int) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.maxItems = max(n, 0)
+	}
+}
+
+// WithMaxMemory sets the maximum number of bytes that are used by the tree,
+// as reported by MemoryUsage. TryInsert returns ErrCapacity once the tree
+// uses that much memory.
+// To keep TryInsert fast the tree is only measured again when the number of
+// items has changed by more than about 6%, and the usage in between is
+// extrapolated from the last measure, so the limit is approximate.
+// Insert, which can't return an error, is not limited.
+// The default of zero is no limit.
+func WithMaxMemory[N numeric, T any](bytes int64) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.maxMemory = max(bytes, 0)
+	}
+}
+
+// MemoryUsage returns an estimate of the number of bytes that are used by the
+// tree, not including any memory that the items point to.
+// Nodes that are shared with copies of the tree are counted by each copy.
+// This visits every node in the tree.
+func (tr *RTreeGN[N, T]) MemoryUsage() int64 {
+	size := int64(unsafe.Sizeof(*tr))
+	if tr.root != nil {
+		size += tr.root.memoryUsage()
+	}
+	return size
+}
+
+func (n *node[N, T]) memoryUsage() int64 {
+	var size int64
+	if n.aggs != nil {
+		size += int64(unsafe.Sizeof(nodeAggs{})) +
+			int64(cap(n.aggs.vals))*int64(unsafe.Sizeof(any(nil)))
+	}
+	if n.leaf() {
+		size += int64(unsafe.Sizeof(leafNode[N, T]{}))
+		if n.seqs() != nil {
+			size += int64(unsafe.Sizeof([maxEntries]uint64{}))
+		}
+		return size
+	}
+	size += int64(unsafe.Sizeof(branchNode[N, T]{}))
+	children := n.children()[:n.count]
+	for i := range children {
+		size += children[i].memoryUsage()
+	}
+	return size
+}
+
+// checkCapacity returns ErrCapacity when the tree has reached its maximum
+// number of items or its maximum memory.
+func (tr *RTreeGN[N, T]) checkCapacity() error {
+	if tr.maxItems > 0 && tr.count >= tr.maxItems {
+		return ErrCapacity
+	}
+	if tr.maxMemory > 0 && tr.estimateMemory() >= tr.maxMemory {
+		return ErrCapacity
+	}
+	return nil
+}
+
+// estimateMemory returns the memory usage of the tree. The tree is measured
+// again when it's small or when the number of items has changed by more than
+// 1/16 since the last measure, otherwise the usage is extrapolated from the
+// last measure.
+func (tr *RTreeGN[N, T]) estimateMemory() int64 {
+	m := &tr.mem
+	d := tr.count - m.items
+	if m.items < 64 || d > m.items/16 || d < -m.items/16 {
+		m.bytes, m.items = tr.MemoryUsage(), tr.count
+		return m.bytes
+	}
+	return m.bytes + int64(d)*m.bytes/int64(m.items)
+}
+
+// MemoryUsage returns an estimate of the number of bytes that are used by the
+// tree, not including any memory that the items point to.
+func (tr *RTreeG[T]) MemoryUsage() int64 {
+	return tr.base.MemoryUsage()
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	type entry struct {
+		op       Op
+		min, max [2]float64
+		data     int
+	}
+	var log []entry
+	var tr RTreeG[int]
+	tr.SetLogger(func(op Op, min, max [2]float64, data int) {
+		log = append(log, entry{op, min, max, data})
+	})
+	rects := make([]rect[float64], 1000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for i := 0; i < len(rects); i += 4 {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+	for i := 1; i < len(rects); i += 4 {
+		r := randRect('r')
+		tr.Replace(rects[i].min, rects[i].max, i, r.min, r.max, i)
+		rects[i] = r
+	}
+	tr.ScanDelete(func(min, max [2]float64, data int) bool {
+		return data%4 == 2
+	})
+	h := tr.base.InsertHandle(rects[0].min, rects[0].max, -1)
+	tr.base.DeleteHandle(h)
+	tr.LoadBulk([]Item[float64, int]{{rects[0].min, rects[0].max, 0}})
+	// deleting a missing item is not logged
+	n := len(log)
+	tr.Delete(rects[0].min, rects[0].max, -2)
+	if len(log) != n {
+		t.Fatalf("expected %d, got %d", n, len(log))
+	}
+	if log[len(log)-2].op != OpDelete || log[len(log)-2].data != -1 {
+		t.Fatalf("expected %v of %d, got %v of %d", OpDelete, -1,
+			log[len(log)-2].op, log[len(log)-2].data)
+	}
+
+	// replaying the log builds the same tree
+	var tr2 RTreeG[int]
+	for _, e := range log {
+		switch e.op {
+		case OpInsert:
+			tr2.Insert(e.min, e.max, e.data)
+		case OpDelete:
+			tr2.Delete(e.min, e.max, e.data)
+		case OpClear:
+			tr2.Clear()
+		}
+	}
+	items := func(tr *RTreeG[int]) []int {
+		var res []int
+		tr.Scan(func(min, max [2]float64, data int) bool {
+			res = append(res, data)
+			return true
+		})
+		slices.Sort(res)
+		return res
+	}
+	if !slices.Equal(items(&tr), items(&tr2)) {
+		t.Fatal("mismatch")
+	}
+
+	tr.Clear()
+	if e := log[len(log)-1]; e.op != OpClear || e.op.String() != "clear" {
+		t.Fatalf("expected %v, got %v", OpClear, e.op)
+	}
+	tr.SetLogger(nil)
+	n = len(log)
+	tr.Insert(rects[0].min, rects[0].max, 0)
+	if len(log) != n {
+		t.Fatalf("expected %d, got %d", n, len(log))
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSearchMask(t *testing.T) {
+	var tr RTreeG[int]
+	expectPanic(t, func() {
+		tr.SearchMask([2]float64{}, [2]float64{}, 1, 0, nil)
+	})
+	N := 10000
+	masks := make([]uint64, N)
+	rects := make([]rect[float64], N)
+	for i := 0; i < N; i++ {
+		// a few rare categories
+		masks[i] = 1 << (rand.Intn(8) + 8*rand.Intn(2))
+		if rand.Intn(4) == 0 {
+			masks[i] |= 1 << (rand.Intn(8) + 16)
+		}
+		rects[i] = randRect('m')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	var calls int
+	tr.SetMask(func(data int) uint64 {
+		calls++
+		return masks[data]
+	})
+	for i := 0; i < 200; i++ {
+		q := randRect('r')
+		q.max[0] = q.min[0] + rand.Float64()*100
+		q.max[1] = q.min[1] + rand.Float64()*50
+		include := uint64(rand.Intn(1 << 16))
+		if i%10 == 0 {
+			include = 0
+		}
+		exclude := uint64(rand.Intn(1<<8)) << 16
+		var expect []int
+		for j := 0; j < N; j++ {
+			if rects[j].intersects(&q) &&
+				(include == 0 || masks[j]&include != 0) &&
+				masks[j]&exclude == 0 {
+				expect = append(expect, j)
+			}
+		}
+		var got []int
+		tr.SearchMask(q.min, q.max, include, exclude,
+			func(min, max [2]float64, data int) bool {
+				got = append(got, data)
+				return true
+			})
+		slices.Sort(got)
+		if !slices.Equal(got, expect) {
+			t.Fatalf("expected %v, got %v", expect, got)
+		}
+	}
+
+	// categories that no item has are skipped at the root
+	calls = 0
+	tr.SearchMask([2]float64{-180, -90}, [2]float64{180, 90}, 1<<40, 0,
+		func(min, max [2]float64, data int) bool {
+			t.Fatal("expected no items")
+			return false
+		})
+	if calls != 0 {
+		t.Fatalf("expected 0 calls, got %d", calls)
+	}
+
+	tr.SetMask(nil)
+	expectPanic(t, func() {
+		tr.SearchMask([2]float64{}, [2]float64{}, 1, 0, nil)
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAggregator(t *testing.T) {
+	var tr RTreeG[int]
+	N := 10000
+	rects := make([]rect[float64], N)
+	for i := 0; i < N; i++ {
+		rects[i] = randRect('m')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	maxi := RegisterAggregatorG(&tr, Aggregator[int, int]{
+		Item: func(data int) int { return data },
+		Merge: func(a, b int) int {
+			if a > b {
+				return a
+			}
+			return b
+		},
+	})
+	type span struct{ lo, hi int }
+	spans := RegisterAggregatorG(&tr, Aggregator[int, span]{
+		Item: func(data int) span { return span{data, data} },
+		Merge: func(a, b span) span {
+			return span{min(a.lo, b.lo), max(a.hi, b.hi)}
+		},
+	})
+	check := func(tr *RTreeG[int], maxi *AggregateIndex[float64, int, int]) {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			q := randRect('r')
+			q.max[0] = q.min[0] + rand.Float64()*100
+			q.max[1] = q.min[1] + rand.Float64()*50
+			var expect int
+			var found bool
+			tr.Search(q.min, q.max, func(min, max [2]float64, data int) bool {
+				if !found || data > expect {
+					expect = data
+				}
+				found = true
+				return true
+			})
+			got, ok := maxi.Aggregate(q.min, q.max)
+			if ok != found || got != expect {
+				t.Fatalf("expected %v %v, got %v %v", expect, found, got, ok)
+			}
+		}
+	}
+	check(&tr, maxi)
+	snap := tr.Copy()
+	for _, i := range rand.Perm(N)[:N/2] {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+	check(&tr, maxi)
+	check(snap, maxi.In(&snap.base))
+	s, ok := spans.Aggregate([2]float64{-1000, -1000}, [2]float64{1000, 1000})
+	if !ok || s.lo < 0 || s.hi >= N {
+		t.Fatalf("unexpected span %v", s)
+	}
+	spans.Unregister()
+	for i := 0; i < 100; i++ {
+		r := randRect('m')
+		tr.Insert(r.min, r.max, N+i)
+	}
+	check(&tr, maxi)
+	if v, ok := maxi.Aggregate([2]float64{-1000, -1000},
+		[2]float64{1000, 1000}); !ok || v != N+99 {
+		t.Fatalf("expected %v, got %v", N+99, v)
+	}
+	tr.Clear()
+	if _, ok := maxi.Aggregate([2]float64{-1000, -1000},
+		[2]float64{1000, 1000}); ok {
+		t.Fatal("expected no aggregate for empty tree")
+	}
+}
+
+func TestAggregatorUnused(t *testing.T) {
+	var tr RTreeG[int]
+	N := 10000
+	rects := make([]rect[float64], N)
+	for i := 0; i < N; i++ {
+		rects[i] = randRect('m')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for i := 0; i < N; i += 2 {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+	var check func(n *node[float64, int])
+	check = func(n *node[float64, int]) {
+		if n.aggs != nil {
+			t.Fatal("expected no aggregates")
+		}
+		if !n.leaf() {
+			for _, child := range n.children()[:n.count] {
+				check(child)
+			}
+		}
+	}
+	check(tr.base.root)
+	RegisterAggregatorG(&tr, Aggregator[int, int]{
+		Item:  func(data int) int { return 1 },
+		Merge: func(a, b int) int { return a + b },
+	})
+	if tr.base.root.aggs == nil || tr.base.root.aggs.vals[0] != N/2 {
+		t.Fatal("expected aggregates")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randAntimeridianRect() (min, max [2]float64) {
+	min[0] = rand.Float64()*360 - 180
+	min[1] = rand.Float64()*170 - 90
+	max[0] = min[0] + rand.Float64()*40
+	max[1] = min[1] + rand.Float64()*10
+	if max[0] > 180 {
+		max[0] -= 360
+	}
+	return min, max
+}
+
+func amIntersects(amin, amax, bmin, bmax [2]float64) bool {
+	if amin[1] > bmax[1] || amax[1] < bmin[1] {
+		return false
+	}
+	as, _ := amSplit(amin, amax)
+	bs, _ := amSplit(bmin, bmax)
+	for _, a := range as {
+		for _, b := range bs {
+			if a != (rect[float64]{}) && b != (rect[float64]{}) &&
+				a.intersects(&b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestAntimeridian(t *testing.T) {
+	var tr AntimeridianRTreeG[int]
+	var mins, maxs [][2]float64
+	for i := 0; i < 5000; i++ {
+		min, max := randAntimeridianRect()
+		tr.Insert(min, max, i)
+		mins = append(mins, min)
+		maxs = append(maxs, max)
+	}
+	if tr.Len() != len(mins) {
+		t.Fatalf("expected %d, got %d", len(mins), tr.Len())
+	}
+	var count int
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		if min != mins[data] || max != maxs[data] {
+			t.Fatalf("item %d: wrong rect", data)
+		}
+		count++
+		return true
+	})
+	if count != len(mins) {
+		t.Fatalf("expected %d, got %d", len(mins), count)
+	}
+	for i := 0; i < 200; i++ {
+		qmin, qmax := randAntimeridianRect()
+		if i%2 == 0 {
+			qmin[0], qmax[0] = 170+rand.Float64()*10, -180+rand.Float64()*10
+		}
+		var expect int
+		for j := range mins {
+			if amIntersects(mins[j], maxs[j], qmin, qmax) {
+				expect++
+			}
+		}
+		seen := make(map[int]bool)
+		tr.Search(qmin, qmax, func(min, max [2]float64, data int) bool {
+			if seen[data] {
+				t.Fatalf("duplicate item %d", data)
+			}
+			seen[data] = true
+			if min != mins[data] || max != maxs[data] {
+				t.Fatalf("item %d: wrong rect", data)
+			}
+			return true
+		})
+		if len(seen) != expect {
+			t.Fatalf("expected %d, got %d", expect, len(seen))
+		}
+	}
+	for i := range mins {
+		tr.Delete(mins[i], maxs[i], i)
+	}
+	if tr.Len() != 0 || tr.base.Len() != 0 {
+		t.Fatalf("expected %d, got %d/%d", 0, tr.Len(), tr.base.Len())
+	}
+}
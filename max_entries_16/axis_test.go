@@ -0,0 +1,50 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestSearchAxis(t *testing.T) {
+	var tr RTreeGN[int64, int]
+	rects := make([][2][2]int64, 10000)
+	for i := range rects {
+		r := randRect('r')
+		rects[i] = [2][2]int64{
+			{int64(r.min[0] * 1000), int64(r.min[1] * 1000)},
+			{int64(r.max[0] * 1000), int64(r.max[1] * 1000)},
+		}
+		tr.Insert(rects[i][0], rects[i][1], i)
+	}
+	for axis := 0; axis < 2; axis++ {
+		for j := 0; j < 20; j++ {
+			r := randRect('r')
+			min, max := int64(r.min[axis]*1000), int64(r.max[axis]*1000+5000)
+			var expect int
+			for i := range rects {
+				if rects[i][0][axis] <= max && rects[i][1][axis] >= min {
+					expect++
+				}
+			}
+			var count int
+			tr.SearchAxis(axis, min, max,
+				func(rmin, rmax [2]int64, data int) bool {
+					if rmin[axis] > max || rmax[axis] < min {
+						t.Fatalf("item %d outside of range", data)
+					}
+					count++
+					return true
+				},
+			)
+			if count != expect {
+				t.Fatalf("expected %d, got %d", expect, count)
+			}
+		}
+	}
+	expectPanic(t, func() {
+		tr.SearchAxis(2, 0, 0, func(min, max [2]int64, data int) bool {
+			return true
+		})
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// binaryVersion is the first byte of the binary encoding.
+const binaryVersion = 1
+
+var errBinaryVersion = errors.New("rtree: unsupported binary version")
+
+// ItemCodec encodes and decodes the data of items for MarshalBinary and
+// UnmarshalBinary.
+type ItemCodec[T any] interface {
+	MarshalItem(data T) ([]byte, error)
+	UnmarshalItem(b []byte) (T, error)
+}
+
+// binaryItem is an item whose data is encoded using an ItemCodec.
+type binaryItem[N numeric] struct {
+	Min, Max [2]N
+	Data     []byte
+}
+
+// SetItemCodec sets the codec that is used by MarshalBinary and
+// UnmarshalBinary for encoding the data of each item.
+// Without a codec the data is encoded using encoding/gob, which requires
+// that the data type is supported by gob.
+func (tr *RTreeGN[N, T]) SetItemCodec(codec ItemCodec[T]) {
+	tr.codec = codec
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, which also allows for
+// the tree to be used with encoding/gob.
+func (tr *RTreeGN[N, T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(binaryVersion)
+	enc := gob.NewEncoder(&buf)
+	if tr.codec == nil {
+		if err := enc.Encode(tr.Items()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	items := make([]binaryItem[N], 0, tr.count)
+	var err error
+	tr.Scan(func(min, max [2]N, data T) bool {
+		var b []byte
+		b, err = tr.codec.MarshalItem(data)
+		items = append(items, binaryItem[N]{min, max, b})
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(items); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing all items
+// in the tree with the items that were encoded by MarshalBinary.
+// The tree must use the same ItemCodec as the tree that was encoded.
+func (tr *RTreeGN[N, T]) UnmarshalBinary(data []byte) error {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if len(data) == 0 || data[0] != binaryVersion {
+		return errBinaryVersion
+	}
+	dec := gob.NewDecoder(bytes.NewReader(data[1:]))
+	var items []Item[N, T]
+	if tr.codec == nil {
+		if err := dec.Decode(&items); err != nil {
+			return err
+		}
+	} else {
+		var bitems []binaryItem[N]
+		if err := dec.Decode(&bitems); err != nil {
+			return err
+		}
+		items = make([]Item[N, T], len(bitems))
+		for i := range bitems {
+			data, err := tr.codec.UnmarshalItem(bitems[i].Data)
+			if err != nil {
+				return err
+			}
+			items[i] = Item[N, T]{bitems[i].Min, bitems[i].Max, data}
+		}
+	}
+	tr.Clear()
+	tr.LoadBulk(items)
+	return nil
+}
+
+// SetItemCodec sets the codec that is used by MarshalBinary and
+// UnmarshalBinary for encoding the data of each item.
+func (tr *RTreeG[T]) SetItemCodec(codec ItemCodec[T]) {
+	tr.base.SetItemCodec(codec)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (tr *RTreeG[T]) MarshalBinary() ([]byte, error) {
+	return tr.base.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (tr *RTreeG[T]) UnmarshalBinary(data []byte) error {
+	return tr.base.UnmarshalBinary(data)
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// CellBounds returns the bounding rectangle of a cell of a discrete global
+// grid, such as an S2 cell id or an H3 index, where X is longitude and Y is
+// latitude in degrees, or false if the cell is not valid.
+//
+// S2CellBounds is provided for S2 cells. For H3, which needs the H3 library,
+// the bounds can be calculated from the boundary of the cell that is returned
+// by its CellToBoundary function, taking care of cells that cross the
+// antimeridian.
+type CellBounds func(cell uint64) (min, max [2]float64, ok bool)
+
+// CellRTreeG is an R-tree for items that are keyed by the cells of a
+// discrete global grid, such as S2 or H3. Each item is stored using the
+// bounding rectangle of its cell.
+type CellRTreeG[T any] struct {
+	bounds CellBounds
+	base   RTreeG[cellItem[T]]
+}
+
+type cellItem[T any] struct {
+	cell uint64
+	data T
+}
+
+// NewCellRTreeG returns a new tree that uses the provided function for
+// calculating the bounding rectangle of each cell, such as S2CellBounds.
+func NewCellRTreeG[T any](bounds CellBounds) *CellRTreeG[T] {
+	return &CellRTreeG[T]{bounds: bounds}
+}
+
+// Insert data for a cell into the tree.
+// Returns false if the cell is not valid.
+func (tr *CellRTreeG[T]) Insert(cell uint64, data T) bool {
+	min, max, ok := tr.bounds(cell)
+	if !ok {
+		return false
+	}
+	tr.base.Insert(min, max, cellItem[T]{cell, data})
+	return true
+}
+
+// Delete data for a cell from the tree.
+// Returns false if the item was not found.
+func (tr *CellRTreeG[T]) Delete(cell uint64, data T) bool {
+	min, max, ok := tr.bounds(cell)
+	if !ok {
+		return false
+	}
+	return tr.base.base.delete(min, max, cellItem[T]{cell, data}, 0)
+}
+
+// Len returns the number of items in tree.
+func (tr *CellRTreeG[T]) Len() int {
+	return tr.base.Len()
+}
+
+// Search for items whose cells have a bounding rectangle that intersects the
+// bounding rectangle of the provided cell.
+// Returns false if the cell is not valid.
+func (tr *CellRTreeG[T]) Search(cell uint64,
+	iter func(cell uint64, data T) bool,
+) bool {
+	min, max, ok := tr.bounds(cell)
+	if !ok {
+		return false
+	}
+	tr.SearchRect(min, max, iter)
+	return true
+}
+
+// SearchRect searches for items whose cells have a bounding rectangle that
+// intersects the provided rectangle, where X is longitude and Y is latitude
+// in degrees.
+func (tr *CellRTreeG[T]) SearchRect(min, max [2]float64,
+	iter func(cell uint64, data T) bool,
+) {
+	tr.base.Search(min, max,
+		func(_, _ [2]float64, item cellItem[T]) bool {
+			return iter(item.cell, item.data)
+		},
+	)
+}
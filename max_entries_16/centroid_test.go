@@ -0,0 +1,70 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCentroid(t *testing.T) {
+	var tr RTreeG[int]
+	if _, ok := tr.Centroid(); ok {
+		t.Fatal("expected false")
+	}
+	tr.Insert([2]float64{0, 0}, [2]float64{2, 2}, 1)
+	tr.Insert([2]float64{10, 0}, [2]float64{10, 0}, 2)
+	tr.Insert([2]float64{2, 6}, [2]float64{2, 8}, 3)
+	c, ok := tr.Centroid()
+	if !ok || c != [2]float64{13.0 / 3, 8.0 / 3} {
+		t.Fatalf("got %v", c)
+	}
+	tr.Clear()
+	var sum [2]float64
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+		sum[0] += r.center(0)
+		sum[1] += r.center(1)
+	}
+	c, _ = tr.Centroid()
+	if math.Abs(c[0]-sum[0]/10000) > 1e-9 ||
+		math.Abs(c[1]-sum[1]/10000) > 1e-9 {
+		t.Fatalf("got %v", c)
+	}
+}
+
+func TestBoundsOf(t *testing.T) {
+	var tr RTreeG[int]
+	if _, _, n := tr.BoundsOf([2]float64{-180, -90},
+		[2]float64{180, 90}); n != 0 {
+		t.Fatal("expected no items")
+	}
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	for j := 0; j < 50; j++ {
+		w := randRect('r')
+		w.max[0] += float64(j)
+		w.max[1] += float64(j) / 2
+		var expect rect[float64]
+		var count int
+		tr.Search(w.min, w.max, func(min, max [2]float64, data int) bool {
+			addBounds(&expect, &rect[float64]{min, max}, &count, 1)
+			return true
+		})
+		min, max, n := tr.BoundsOf(w.min, w.max)
+		if n != count || min != expect.min || max != expect.max {
+			t.Fatalf("expected %v %v %d, got %v %v %d", expect.min,
+				expect.max, count, min, max, n)
+		}
+	}
+	min, max, n := tr.BoundsOf([2]float64{-180, -90}, [2]float64{180, 90})
+	bmin, bmax := tr.Bounds()
+	if n != 10000 || min != bmin || max != bmax {
+		t.Fatal("expected all items")
+	}
+}
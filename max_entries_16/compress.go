@@ -0,0 +1,234 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"unsafe"
+)
+
+// qmax is the largest quantized coordinate of a compressed branch rect.
+const qmax = math.MaxUint16
+
+// CompressedTree is an immutable copy of a tree that uses less memory, which
+// is returned by Compress.
+//
+// All nodes are stored in flat arrays without any unused entries, and the
+// rects of the branches are quantized to 16 bits per coordinate, relative to
+// the rect of their parent. The quantized rects always contain the actual
+// rects, so a search may visit a few more nodes than it would in the
+// original tree, but it always returns the same items. The rects of the items
+// are stored as is.
+type CompressedTree[N numeric, T any] struct {
+	rect     rect[N]
+	nodes    []cnode
+	branches []cbranch
+	rects    []rect[N]
+	items    []T
+}
+
+// cnode is a node of a compressed tree. The entries of a leaf are the items
+// and rects at start, and the entries of a branch are the branches at start.
+type cnode struct {
+	start   uint32
+	count   uint16
+	leaf    bool
+	ordered bool
+}
+
+// cbranch is the quantized rect of a child node of a compressed tree.
+type cbranch struct {
+	min, max [2]uint16
+	child    uint32
+}
+
+// Compress returns an immutable copy of the tree that uses less memory, which
+// is meant for huge static datasets.
+// The tree is not modified.
+func (tr *RTreeGN[N, T]) Compress() *CompressedTree[N, T] {
+	ct := &CompressedTree[N, T]{rect: tr.rect}
+	if tr.root == nil {
+		return ct
+	}
+	ct.items = make([]T, 0, tr.count)
+	ct.rects = make([]rect[N], 0, tr.count)
+	ct.compress(tr.root, &tr.rect)
+	return ct
+}
+
+// compress appends the node, whose quantized rect is qr, and all of its
+// children, and returns the index of the node.
+func (ct *CompressedTree[N, T]) compress(n *node[N, T], qr *rect[N]) uint32 {
+	idx := uint32(len(ct.nodes))
+	cn := cnode{count: uint16(n.count), leaf: n.leaf(), ordered: n.ordered()}
+	if cn.leaf {
+		cn.start = uint32(len(ct.items))
+		ct.nodes = append(ct.nodes, cn)
+		for i := 0; i < int(n.count); i++ {
+			ct.rects = append(ct.rects, n.rects.at(i))
+		}
+		ct.items = append(ct.items, n.items()[:n.count]...)
+		return idx
+	}
+	cn.start = uint32(len(ct.branches))
+	ct.nodes = append(ct.nodes, cn)
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		var b cbranch
+		for axis := 0; axis < 2; axis++ {
+			b.min[axis] = quantizeMin(qr.min[axis], qr.max[axis],
+				r.min[axis])
+			b.max[axis] = quantizeMax(qr.min[axis], qr.max[axis],
+				r.max[axis])
+		}
+		ct.branches = append(ct.branches, b)
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		cr := branchRect(&ct.branches[int(cn.start)+i], qr)
+		child := ct.compress(children[i], &cr)
+		ct.branches[int(cn.start)+i].child = child
+	}
+	return idx
+}
+
+// dequantize returns the coordinate for q, which is a quantized coordinate in
+// the range from min to max.
+func dequantize[N numeric](min, max N, q uint16) N {
+	switch q {
+	case 0:
+		return min
+	case qmax:
+		return max
+	}
+	span := float64(max) - float64(min)
+	return N(float64(min) + float64(q)*span/qmax)
+}
+
+// quantizeMin returns the largest quantized coordinate that does not
+// dequantize to more than v.
+func quantizeMin[N numeric](min, max, v N) uint16 {
+	q := quantizeGuess(min, max, v, math.Floor)
+	for q > 0 && dequantize(min, max, q) > v {
+		q--
+	}
+	return q
+}
+
+// quantizeMax returns the smallest quantized coordinate that does not
+// dequantize to less than v.
+func quantizeMax[N numeric](min, max, v N) uint16 {
+	q := quantizeGuess(min, max, v, math.Ceil)
+	for q < qmax && dequantize(min, max, q) < v {
+		q++
+	}
+	return q
+}
+
+func quantizeGuess[N numeric](min, max, v N, round func(float64) float64,
+) uint16 {
+	span := float64(max) - float64(min)
+	if !(span > 0) {
+		return 0
+	}
+	f := round((float64(v) - float64(min)) / span * qmax)
+	if !(f > 0) {
+		return 0
+	}
+	return uint16(math.Min(f, qmax))
+}
+
+// branchRect returns the rect of the branch, where qr is the rect of its
+// parent.
+func branchRect[N numeric](b *cbranch, qr *rect[N]) rect[N] {
+	var r rect[N]
+	for axis := 0; axis < 2; axis++ {
+		r.min[axis] = dequantize(qr.min[axis], qr.max[axis], b.min[axis])
+		r.max[axis] = dequantize(qr.min[axis], qr.max[axis], b.max[axis])
+	}
+	return r
+}
+
+// Len returns the number of items in the tree.
+func (ct *CompressedTree[N, T]) Len() int {
+	return len(ct.items)
+}
+
+// Bounds returns the minimum bounding rect of all items in the tree.
+func (ct *CompressedTree[N, T]) Bounds() (min, max [2]N) {
+	return ct.rect.min, ct.rect.max
+}
+
+// Size returns the approximate number of bytes that are used by the tree,
+// not including any memory that the items point to.
+func (ct *CompressedTree[N, T]) Size() int {
+	var empty T
+	return int(unsafe.Sizeof(*ct)) +
+		cap(ct.nodes)*int(unsafe.Sizeof(cnode{})) +
+		cap(ct.branches)*int(unsafe.Sizeof(cbranch{})) +
+		cap(ct.rects)*int(unsafe.Sizeof(rect[N]{})) +
+		cap(ct.items)*int(unsafe.Sizeof(empty))
+}
+
+// Search for items in the tree that intersect the provided rectangle.
+func (ct *CompressedTree[N, T]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	target := rect[N]{min, max}
+	if len(ct.nodes) == 0 || !target.intersects(&ct.rect) {
+		return
+	}
+	ct.search(0, &ct.rect, &target, iter)
+}
+
+func (ct *CompressedTree[N, T]) search(idx uint32, qr, target *rect[N],
+	iter func(min, max [2]N, data T) bool,
+) bool {
+	cn := ct.nodes[idx]
+	start, end := int(cn.start), int(cn.start)+int(cn.count)
+	if cn.leaf {
+		rects := ct.rects[start:end]
+		for i := range rects {
+			if cn.ordered && rects[i].min[0] > target.max[0] {
+				// the remaining rects are all further to the right
+				break
+			}
+			if rects[i].intersects(target) {
+				if !iter(rects[i].min, rects[i].max, ct.items[start+i]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	branches := ct.branches[start:end]
+	for i := range branches {
+		r := branchRect(&branches[i], qr)
+		if cn.ordered && r.min[0] > target.max[0] {
+			break
+		}
+		if r.intersects(target) {
+			if !ct.search(branches[i].child, &r, target, iter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Scan all items in the tree.
+func (ct *CompressedTree[N, T]) Scan(iter func(min, max [2]N, data T) bool) {
+	for i := range ct.items {
+		if !iter(ct.rects[i].min, ct.rects[i].max, ct.items[i]) {
+			return
+		}
+	}
+}
+
+// Compress returns an immutable copy of the tree that uses less memory.
+// See RTreeGN.Compress.
+func (tr *RTreeG[T]) Compress() *CompressedTree[float64, T] {
+	return tr.base.Compress()
+}
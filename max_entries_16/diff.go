@@ -0,0 +1,205 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Diff compares the tree to another version of it, such as a snapshot that
+// was made using Copy, and calls onInsert for each item that is in this tree
+// but not in the other, and onDelete for each item that is in the other tree
+// but not in this one. Either function may be nil.
+//
+// Subtrees that are shared between both trees, because neither has modified
+// them since the copy was made, are skipped without visiting their items.
+// This makes finding a small number of changes between two large versions
+// very fast. Trees that share no nodes are still compared correctly, but all
+// of their items are visited.
+//
+// The data of items with the same rect are compared like Delete does, using
+// the comparator of this tree, see WithComparator.
+func (tr *RTreeGN[N, T]) Diff(other *RTreeGN[N, T],
+	onInsert, onDelete func(min, max [2]N, data T),
+) {
+	self := func(n *node[N, T]) *node[N, T] { return n }
+	diffNodes(tr.root, other.root, self, self, tr.equal, onInsert, onDelete)
+}
+
+// DiffHashes is like Diff, but skips the subtrees that have the same hash in
+// both trees, rather than only the subtrees that are shared. This allows for
+// comparing trees that share no nodes, such as replicas that were built
+// separately, in time proportional to the size of the difference.
+//
+// Both trees must have the same hash function set using SetHash, otherwise
+// only the shared subtrees are skipped, like Diff. The data of items are
+// compared like Diff does.
+func (tr *RTreeGN[N, T]) DiffHashes(other *RTreeGN[N, T],
+	onInsert, onDelete func(min, max [2]N, data T),
+) {
+	if tr.hash == nil || other.hash == nil {
+		tr.Diff(other, onInsert, onDelete)
+		return
+	}
+	ia, ib := tr.hash.idx, other.hash.idx
+	diffNodes(tr.root, other.root,
+		func(n *node[N, T]) uint64 { return n.aggs.vals[ia].(uint64) },
+		func(n *node[N, T]) uint64 { return n.aggs.vals[ib].(uint64) },
+		tr.equal, onInsert, onDelete)
+}
+
+// diffNodes compares two trees, skipping the subtrees that have the same key.
+func diffNodes[N numeric, T any, K comparable](rootA, rootB *node[N, T],
+	keyA, keyB func(n *node[N, T]) K, eq func(a, b T) bool,
+	onInsert, onDelete func(min, max [2]N, data T),
+) {
+	a, ha := diffFrontier(rootA)
+	b, hb := diffFrontier(rootB)
+	for {
+		switch {
+		case ha > hb:
+			a, ha = diffExpand(a), ha-1
+			continue
+		case hb > ha:
+			b, hb = diffExpand(b), hb-1
+			continue
+		}
+		a, b = diffRemoveShared(a, b, keyA, keyB)
+		if len(a) == 0 && len(b) == 0 {
+			return
+		}
+		if ha == 0 {
+			break
+		}
+		a, ha = diffExpand(a), ha-1
+		b, hb = diffExpand(b), hb-1
+	}
+	// Only the leaves that differ remain. Items that moved to another leaf
+	// cancel each other out. The items of b are grouped by rect, so only the
+	// data of items with the same rect are compared.
+	type entry struct {
+		data  T
+		count int // items of b with the data that are not in a
+	}
+	deleted := make(map[rect[N]][]entry)
+	find := func(r rect[N], data T) (entries []entry, i int) {
+		entries = deleted[r]
+		for i := range entries {
+			if eq(entries[i].data, data) {
+				return entries, i
+			}
+		}
+		return entries, -1
+	}
+	for _, n := range b {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if entries, j := find(r, items[i]); j >= 0 {
+				entries[j].count++
+			} else {
+				deleted[r] = append(entries, entry{items[i], 1})
+			}
+		}
+	}
+	for _, n := range a {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if entries, j := find(r, items[i]); j >= 0 &&
+				entries[j].count > 0 {
+				entries[j].count--
+			} else if onInsert != nil {
+				onInsert(r.min, r.max, items[i])
+			}
+		}
+	}
+	if onDelete == nil {
+		return
+	}
+	for _, n := range b {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if entries, j := find(r, items[i]); entries[j].count > 0 {
+				entries[j].count--
+				onDelete(r.min, r.max, items[i])
+			}
+		}
+	}
+}
+
+// diffFrontier returns the root as the first frontier and its height, where
+// leaves are at height zero.
+func diffFrontier[N numeric, T any](root *node[N, T]) ([]*node[N, T], int) {
+	if root == nil {
+		return nil, 0
+	}
+	var height int
+	for n := root; !n.leaf(); n = n.children()[0] {
+		height++
+	}
+	return []*node[N, T]{root}, height
+}
+
+// diffExpand replaces the nodes with their children.
+func diffExpand[N numeric, T any](nodes []*node[N, T]) []*node[N, T] {
+	var children []*node[N, T]
+	for _, n := range nodes {
+		children = append(children, n.children()[:n.count]...)
+	}
+	return children
+}
+
+// diffRemoveShared removes the nodes that have the same key in both
+// frontiers.
+func diffRemoveShared[N numeric, T any, K comparable](a, b []*node[N, T],
+	keyA, keyB func(n *node[N, T]) K,
+) ([]*node[N, T], []*node[N, T]) {
+	if len(a) == 0 || len(b) == 0 {
+		return a, b
+	}
+	inB := make(map[K]int, len(b))
+	for _, n := range b {
+		inB[keyB(n)]++
+	}
+	shared := make(map[K]int)
+	var a2 []*node[N, T]
+	for _, n := range a {
+		k := keyA(n)
+		if inB[k] > 0 {
+			inB[k]--
+			shared[k]++
+		} else {
+			a2 = append(a2, n)
+		}
+	}
+	if len(shared) == 0 {
+		return a, b
+	}
+	var b2 []*node[N, T]
+	for _, n := range b {
+		k := keyB(n)
+		if shared[k] > 0 {
+			shared[k]--
+		} else {
+			b2 = append(b2, n)
+		}
+	}
+	return a2, b2
+}
+
+// Diff compares the tree to another version of it, such as a snapshot that
+// was made using Copy, and calls onInsert for each item that is only in this
+// tree and onDelete for each item that is only in the other tree.
+func (tr *RTreeG[T]) Diff(other *RTreeG[T],
+	onInsert, onDelete func(min, max [2]float64, data T),
+) {
+	tr.base.Diff(&other.base, onInsert, onDelete)
+}
+
+// DiffHashes is like Diff, but skips the subtrees that have the same hash in
+// both trees. See RTreeGN.DiffHashes.
+func (tr *RTreeG[T]) DiffHashes(other *RTreeG[T],
+	onInsert, onDelete func(min, max [2]float64, data T),
+) {
+	tr.base.DiffHashes(&other.base, onInsert, onDelete)
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	var tr RTreeG[int]
+	N := 5000
+	for i := 0; i < N; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	encode := func(data int) ([]byte, error) {
+		return strconv.AppendInt(nil, int64(data), 10), nil
+	}
+	var buf bytes.Buffer
+	if err := tr.Export(&buf, 100, encode); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(&buf)
+	if v, _ := br.ReadByte(); v != exportVersion {
+		t.Fatalf("expected version %d, got %d", exportVersion, v)
+	}
+	var tr2 RTreeG[int]
+	var chunks int
+	for {
+		size, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			break
+		}
+		rec := make([]byte, size)
+		if _, err := io.ReadFull(br, rec); err != nil {
+			t.Fatal(err)
+		}
+		bbox, rec := readExportRect[float64](rec)
+		count, n := binary.Uvarint(rec)
+		rec = rec[n:]
+		if count == 0 || count > 100 {
+			t.Fatalf("expected 1 to 100 items, got %d", count)
+		}
+		for i := 0; i < int(count); i++ {
+			var ir rect[float64]
+			ir, rec = readExportRect[float64](rec)
+			if !bbox.contains(&ir) {
+				t.Fatal("expected item inside of chunk bbox")
+			}
+			size, n := binary.Uvarint(rec)
+			data, _ := strconv.Atoi(string(rec[n : n+int(size)]))
+			rec = rec[n+int(size):]
+			tr2.Insert(ir.min, ir.max, data)
+		}
+		if len(rec) != 0 {
+			t.Fatal("expected end of chunk")
+		}
+		chunks++
+	}
+	if chunks != (N+99)/100 {
+		t.Fatalf("expected %d chunks, got %d", (N+99)/100, chunks)
+	}
+	if ins, del := diffItems(&tr, &tr2); len(ins) != 0 || len(del) != 0 {
+		t.Fatalf("expected no changes, got %v %v", ins, del)
+	}
+
+	errEncode := errors.New("encode")
+	err := tr.Export(io.Discard, 0, func(data int) ([]byte, error) {
+		return nil, errEncode
+	})
+	if err != errEncode {
+		t.Fatalf("expected %v, got %v", errEncode, err)
+	}
+	var empty RTreeG[int]
+	buf.Reset()
+	if err := empty.Export(&buf, 0, encode); err != nil || buf.Len() != 1 {
+		t.Fatalf("expected only the version, got %d bytes, %v", buf.Len(), err)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// farDist2 returns the squared distance from p to the farthest point of the
+// rectangle.
+func (r *rect[N]) farDist2(p [2]N) float64 {
+	var dist float64
+	for axis := 0; axis < 2; axis++ {
+		d := float64(p[axis]) - float64(r.min[axis])
+		if d2 := float64(r.max[axis]) - float64(p[axis]); d2 > d {
+			d = d2
+		}
+		dist += d * d
+	}
+	return dist
+}
+
+// Farthest searches for the k items that are farthest from the point p.
+// The iter function will return the items from the farthest to the nearest,
+// along with the squared distance from p to the farthest point of the item
+// rectangle, which for point items is simply their squared distance.
+// Nodes are visited in order of the farthest distance that any of their items
+// may be, so only a small part of the tree is visited when k is small.
+func (tr *RTreeGN[N, T]) Farthest(p [2]N, k int,
+	iter func(min, max [2]N, data T, dist float64) bool,
+) {
+	if tr.root == nil || k <= 0 {
+		return
+	}
+	gen := tr.gen
+	var q pqueue[topkElem[N, T]]
+	q.push(-tr.rect.farDist2(p), topkElem[N, T]{rect: tr.rect, node: tr.root})
+	for k > 0 {
+		prio, e, ok := q.pop()
+		if !ok {
+			return
+		}
+		if e.node == nil {
+			if !iter(e.rect.min, e.rect.max, e.data, -prio) {
+				return
+			}
+			if tr.gen != gen {
+				panic(errModified)
+			}
+			k--
+			continue
+		}
+		rects := &e.node.rects
+		if e.node.leaf() {
+			items := e.node.items()[:e.node.count]
+			for i := range items {
+				r := rects.at(i)
+				q.push(-r.farDist2(p), topkElem[N, T]{rect: r, data: items[i]})
+			}
+		} else {
+			children := e.node.children()[:e.node.count]
+			for i := range children {
+				r := rects.at(i)
+				q.push(-r.farDist2(p),
+					topkElem[N, T]{rect: r, node: children[i]})
+			}
+		}
+	}
+}
+
+// Farthest searches for the k items that are farthest from the point p.
+func (tr *RTreeG[T]) Farthest(p [2]float64, k int,
+	iter func(min, max [2]float64, data T, dist float64) bool,
+) {
+	tr.base.Farthest(p, k, iter)
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	odd := tr.Filter(func(min, max [2]float64, data int) bool {
+		return data%2 == 1
+	})
+	if err := rSane(odd); err != nil {
+		t.Fatal(err)
+	}
+	if odd.Len() != len(rects)/2 {
+		t.Fatalf("expected %d, got %d", len(rects)/2, odd.Len())
+	}
+	if tr.Len() != len(rects) {
+		t.Fatalf("expected %d, got %d", len(rects), tr.Len())
+	}
+	var expect RTreeG[int]
+	for i := 1; i < len(rects); i += 2 {
+		expect.Insert(rects[i].min, rects[i].max, i)
+	}
+	for j := 0; j < 50; j++ {
+		r := randRect('r')
+		r.max[0] += 10
+		r.max[1] += 10
+		if !slices.Equal(bulkSearch(odd, r), bulkSearch(&expect, r)) {
+			t.Fatal("mismatch")
+		}
+	}
+	// both trees can be modified independently
+	for i := 1; i < len(rects); i += 2 {
+		odd.Delete(rects[i].min, rects[i].max, i)
+	}
+	if odd.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, odd.Len())
+	}
+	for i := range rects {
+		if !tr.base.Exists(rects[i].min, rects[i].max, i) {
+			t.Fatalf("item %d not found", i)
+		}
+	}
+	none := tr.Filter(func(min, max [2]float64, data int) bool {
+		return false
+	})
+	if none.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, none.Len())
+	}
+	none.Insert([2]float64{1, 1}, [2]float64{2, 2}, 1)
+	if err := rSane(none); err != nil {
+		t.Fatal(err)
+	}
+	expectPanic(t, func() {
+		tr.Filter(func(min, max [2]float64, data int) bool {
+			tr.Delete(min, max, data)
+			return true
+		})
+	})
+}
+
+func TestFilterLogger(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 1000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	var logged int
+	tr.SetLogger(func(op Op, min, max [2]float64, data int) { logged++ })
+	events, cancel := tr.Subscribe([2]float64{-180, -90}, [2]float64{180, 90})
+	defer cancel()
+	tr.base.estimateMemory()
+	odd := tr.Filter(func(min, max [2]float64, data int) bool {
+		return data%2 == 1
+	})
+	if odd.base.mem != (memEstimate{}) {
+		t.Fatal("expected no memory estimate")
+	}
+	odd.Insert([2]float64{1, 1}, [2]float64{1, 1}, 1001)
+	odd.Delete([2]float64{1, 1}, [2]float64{1, 1}, 1001)
+	if logged != 0 {
+		t.Fatalf("expected no logged ops, got %d", logged)
+	}
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event %v", e)
+	default:
+	}
+	tr.Insert([2]float64{1, 1}, [2]float64{1, 1}, 1001)
+	if logged != 1 || len(events) != 1 {
+		t.Fatal("expected the tree to keep logging")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "errors"
+
+// ErrFrozen is panicked when writing to a frozen tree, or returned by the
+// Try functions, such as TryInsert.
+var ErrFrozen = errors.New("rtree: write to frozen tree")
+
+// Freeze makes the tree immutable.
+// Any following write operation, such as Insert or Delete, on this tree will
+// panic. Reads are not affected.
+//
+// A frozen tree never needs to copy its nodes, so calling Copy on it only
+// assigns a new copy-on-write epoch to the new tree. This means that the
+// snapshots of a write-heavy tree can be handed out to readers without the
+// writer and the snapshots both paying for copying shared nodes.
+func (tr *RTreeGN[N, T]) Freeze() {
+	tr.frozen = true
+}
+
+// Frozen returns true if the tree has been frozen.
+func (tr *RTreeGN[N, T]) Frozen() bool {
+	return tr.frozen
+}
+
+// MutableCopy freezes the tree and returns a mutable copy of it.
+// This is like Copy, except that it's explicit that the original tree is now
+// a read-only snapshot and that only the new tree will be copying the shared
+// nodes on write.
+func (tr *RTreeGN[N, T]) MutableCopy() *RTreeGN[N, T] {
+	tr.frozen = true
+	return tr.Copy()
+}
+
+// Freeze makes the tree immutable.
+// Any following write operation on this tree will panic.
+func (tr *RTreeG[T]) Freeze() {
+	tr.base.Freeze()
+}
+
+// Frozen returns true if the tree has been frozen.
+func (tr *RTreeG[T]) Frozen() bool {
+	return tr.base.Frozen()
+}
+
+// MutableCopy freezes the tree and returns a mutable copy of it.
+func (tr *RTreeG[T]) MutableCopy() *RTreeG[T] {
+	return &RTreeG[T]{*tr.base.MutableCopy()}
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func expectPanic(t *testing.T, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	f()
+}
+
+func TestFreeze(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 1000; i++ {
+		r := randRect('m')
+		tr.Insert(r.min, r.max, i)
+	}
+	tr.Freeze()
+	if !tr.Frozen() {
+		t.Fatal("expected frozen")
+	}
+	icow := tr.base.icow
+	expectPanic(t, func() { tr.Insert([2]float64{}, [2]float64{}, 0) })
+	expectPanic(t, func() { tr.Delete([2]float64{}, [2]float64{}, 0) })
+	expectPanic(t, func() { tr.Clear() })
+	tr2 := tr.Copy()
+	if tr.base.icow != icow {
+		t.Fatal("frozen tree was assigned a new epoch")
+	}
+	if tr2.Frozen() {
+		t.Fatal("expected mutable copy")
+	}
+	var rects []rect[float64]
+	var items []int
+	tr2.Scan(func(min, max [2]float64, data int) bool {
+		rects = append(rects, rect[float64]{min, max})
+		items = append(items, data)
+		return true
+	})
+	for i := 0; i < len(items); i += 2 {
+		tr2.Delete(rects[i].min, rects[i].max, items[i])
+	}
+	if tr2.Len() != 500 {
+		t.Fatalf("expected %d, got %d", 500, tr2.Len())
+	}
+	if tr.Len() != 1000 {
+		t.Fatalf("expected %d, got %d", 1000, tr.Len())
+	}
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+
+	var tr3 RTreeG[int]
+	tr3.Insert([2]float64{1, 1}, [2]float64{1, 1}, 1)
+	tr4 := tr3.MutableCopy()
+	if !tr3.Frozen() || tr4.Frozen() {
+		t.Fatal("expected frozen source and mutable copy")
+	}
+	tr4.Insert([2]float64{2, 2}, [2]float64{2, 2}, 2)
+	if tr3.Len() != 1 || tr4.Len() != 2 {
+		t.Fatalf("expected 1 and 2, got %d and %d", tr3.Len(), tr4.Len())
+	}
+}
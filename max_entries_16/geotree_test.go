@@ -0,0 +1,255 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestGeoTree(t *testing.T) {
+	var tr GeoTree[int]
+	var pts []LatLon
+	for i := 0; i < 10000; i++ {
+		p := LatLon{rand.Float64()*180 - 90, rand.Float64()*360 - 180}
+		if err := tr.InsertLatLon(p, i); err != nil {
+			t.Fatal(err)
+		}
+		pts = append(pts, p)
+	}
+	if tr.Len() != len(pts) {
+		t.Fatalf("expected %d, got %d", len(pts), tr.Len())
+	}
+	check := func(center LatLon, meters float64) {
+		t.Helper()
+		var expect int
+		for _, p := range pts {
+			if haversine(center.Lat*radians, center.Lon*radians,
+				p.Lat*radians, p.Lon*radians) <= meters {
+				expect++
+			}
+		}
+		var count int
+		seen := make(map[int]bool)
+		tr.SearchRadiusMeters(center, meters,
+			func(sw, ne LatLon, data int) bool {
+				if sw != pts[data] || ne != pts[data] {
+					t.Fatalf("expected %v, got %v %v", pts[data], sw, ne)
+				}
+				if seen[data] {
+					t.Fatalf("duplicate item %d", data)
+				}
+				seen[data] = true
+				count++
+				return true
+			},
+		)
+		if count != expect {
+			t.Fatalf("%v %v: expected %d, got %d", center, meters, expect,
+				count)
+		}
+	}
+	check(LatLon{33.4, -112.0}, 1000000)
+	check(LatLon{0, 179.5}, 2000000)  // antimeridian
+	check(LatLon{0, -179.5}, 2000000) // antimeridian
+	check(LatLon{88, 10}, 1000000)    // north pole
+	check(LatLon{45, 0}, 30000000)    // whole world
+	for i := 0; i < 50; i++ {
+		check(LatLon{rand.Float64()*180 - 90, rand.Float64()*360 - 180},
+			rand.Float64()*3000000)
+	}
+
+	// a box that spans the antimeridian
+	var expect int
+	for _, p := range pts {
+		if p.Lat >= -10 && p.Lat <= 10 && (p.Lon >= 170 || p.Lon <= -170) {
+			expect++
+		}
+	}
+	var count int
+	tr.SearchBBox(LatLon{-10, 170}, LatLon{10, -170},
+		func(sw, ne LatLon, data int) bool {
+			count++
+			return true
+		})
+	if count != expect {
+		t.Fatalf("expected %d, got %d", expect, count)
+	}
+
+	for i := 0; i < len(pts); i += 2 {
+		tr.DeleteLatLon(pts[i], i)
+	}
+	count = 0
+	tr.Scan(func(sw, ne LatLon, data int) bool {
+		if data%2 == 0 {
+			t.Fatalf("item %d was not deleted", data)
+		}
+		count++
+		return true
+	})
+	if count != len(pts)/2 || tr.Len() != count {
+		t.Fatalf("expected %d, got %d", len(pts)/2, count)
+	}
+}
+
+func TestGeoTreeBBox(t *testing.T) {
+	var tr GeoTree[string]
+	if err := tr.InsertBBox(LatLon{-10, 170}, LatLon{10, -170},
+		"pacific"); err != nil {
+		t.Fatal(err)
+	}
+	var found []string
+	iter := func(sw, ne LatLon, data string) bool {
+		if sw != (LatLon{-10, 170}) || ne != (LatLon{10, -170}) {
+			t.Fatalf("unexpected box %v %v", sw, ne)
+		}
+		found = append(found, data)
+		return true
+	}
+	tr.SearchBBox(LatLon{0, -175}, LatLon{1, -174}, iter)
+	tr.SearchRadiusMeters(LatLon{0, 179.9}, 1, iter)
+	tr.SearchRadiusMeters(LatLon{20, 180}, 1200000, iter)
+	tr.SearchRadiusMeters(LatLon{20, 180}, 1000, iter)
+	tr.SearchBBox(LatLon{0, 0}, LatLon{1, 1}, iter)
+	if len(found) != 3 {
+		t.Fatalf("expected 3 results, got %v", found)
+	}
+	tr2 := tr.Copy()
+	tr.DeleteBBox(LatLon{-10, 170}, LatLon{10, -170}, "pacific")
+	if tr.Len() != 0 || tr2.Len() != 1 {
+		t.Fatalf("expected 0 and 1, got %d and %d", tr.Len(), tr2.Len())
+	}
+	tr2.Clear()
+	if tr2.Len() != 0 {
+		t.Fatalf("expected 0, got %d", tr2.Len())
+	}
+}
+
+func TestGeoTreeInvalid(t *testing.T) {
+	var tr GeoTree[int]
+	for _, p := range []LatLon{
+		{91, 0}, {-91, 0}, {0, 181}, {0, -181}, {math.NaN(), 0},
+	} {
+		if err := tr.InsertLatLon(p, 0); err != ErrInvalidLatLon {
+			t.Fatalf("%v: expected %v, got %v", p, ErrInvalidLatLon, err)
+		}
+	}
+	// south of north
+	if err := tr.InsertBBox(LatLon{10, 0}, LatLon{0, 10}, 0); err == nil {
+		t.Fatal("expected an error")
+	}
+	if tr.Len() != 0 {
+		t.Fatalf("expected 0, got %d", tr.Len())
+	}
+	tr.InsertLatLon(LatLon{0, 0}, 1)
+	tr.SearchBBox(LatLon{-1, -1}, LatLon{1, 200},
+		func(sw, ne LatLon, data int) bool {
+			t.Fatal("expected no items")
+			return true
+		})
+	tr.SearchRadiusMeters(LatLon{100, 0}, 1e9,
+		func(sw, ne LatLon, data int) bool {
+			t.Fatal("expected no items")
+			return true
+		})
+}
+
+func TestGeoTreeNearby(t *testing.T) {
+	var tr GeoTree[int]
+	var pts []LatLon
+	for i := 0; i < 5000; i++ {
+		// more points at high latitudes
+		lat := 90 - math.Sqrt(rand.Float64())*180
+		if rand.Intn(2) == 0 {
+			lat = -lat
+		}
+		p := LatLon{lat, rand.Float64()*360 - 180}
+		tr.InsertLatLon(p, i)
+		pts = append(pts, p)
+	}
+	// two equal boxes that span the antimeridian
+	tr.InsertBBox(LatLon{80, 170}, LatLon{85, -170}, -1)
+	tr.InsertBBox(LatLon{80, 170}, LatLon{85, -170}, -1)
+	check := func(p LatLon) {
+		t.Helper()
+		var last float64
+		var count int
+		var boxes int
+		tr.Nearby(p, func(sw, ne LatLon, data int, meters float64) bool {
+			if meters < last {
+				t.Fatalf("%v: out of order", p)
+			}
+			last = meters
+			if data == -1 {
+				boxes++
+				return true
+			}
+			q := pts[data]
+			expect := haversine(p.Lat*radians, p.Lon*radians,
+				q.Lat*radians, q.Lon*radians)
+			if math.Abs(meters-expect) > 1e-3 {
+				t.Fatalf("expected %v, got %v", expect, meters)
+			}
+			count++
+			return true
+		})
+		if count != len(pts) || boxes != 2 {
+			t.Fatalf("expected %d items and 2 boxes, got %d and %d",
+				len(pts), count, boxes)
+		}
+	}
+	check(LatLon{89.9, 0})   // north pole
+	check(LatLon{-89.9, 90}) // south pole
+	check(LatLon{82, 179.9}) // antimeridian
+	for i := 0; i < 20; i++ {
+		check(LatLon{rand.Float64()*180 - 90, rand.Float64()*360 - 180})
+	}
+	// the nearest items are the same as by brute force
+	p := LatLon{87, 45}
+	var nearest []int
+	tr.Nearby(p, func(sw, ne LatLon, data int, meters float64) bool {
+		if data >= 0 {
+			nearest = append(nearest, data)
+		}
+		return len(nearest) < 10
+	})
+	dists := make([]float64, len(pts))
+	for i, q := range pts {
+		dists[i] = haversine(p.Lat*radians, p.Lon*radians, q.Lat*radians,
+			q.Lon*radians)
+	}
+	for _, i := range nearest {
+		for j := range pts {
+			if dists[j] < dists[i] && !slices.Contains(nearest, j) {
+				t.Fatalf("item %d is nearer than item %d", j, i)
+			}
+		}
+	}
+	tr.Nearby(LatLon{91, 0},
+		func(sw, ne LatLon, data int, meters float64) bool {
+			t.Fatal("expected no items")
+			return true
+		})
+}
+
+func TestGeoTreeNearbyUncomparable(t *testing.T) {
+	var tr GeoTree[[]string]
+	tr.InsertBBox(LatLon{80, 170}, LatLon{85, -170}, []string{"a"})
+	tr.InsertBBox(LatLon{80, 170}, LatLon{85, -170}, []string{"b"})
+	tr.InsertLatLon(LatLon{10, 10}, []string{"c"})
+	var found []string
+	tr.Nearby(LatLon{82, 179},
+		func(sw, ne LatLon, data []string, meters float64) bool {
+			found = append(found, data...)
+			return true
+		},
+	)
+	slices.Sort(found)
+	if !slices.Equal(found, []string{"a", "b", "c"}) {
+		t.Fatalf("expected %v, got %v", []string{"a", "b", "c"}, found)
+	}
+}
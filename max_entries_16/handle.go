@@ -0,0 +1,51 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// ItemHandle is an opaque reference to a single item in a tree, as returned
+// by InsertHandle.
+// The zero value does not refer to any item.
+type ItemHandle[N numeric] struct {
+	rect rect[N]
+	seq  uint64
+}
+
+// Rect returns the rectangle of the item that the handle refers to.
+func (h ItemHandle[N]) Rect() (min, max [2]N) {
+	return h.rect.min, h.rect.max
+}
+
+// InsertHandle inserts data into tree and returns a handle that refers to
+// exactly this item, which can later be used with DeleteHandle.
+func (tr *RTreeGN[N, T]) InsertHandle(min, max [2]N, data T) ItemHandle[N] {
+	tr.seq++
+	tr.insert(min, max, data, tr.seq)
+	return ItemHandle[N]{rect: rect[N]{min, max}, seq: tr.seq}
+}
+
+// DeleteHandle deletes the item that the handle refers to.
+// Unlike Delete, the item is not matched by its data, which means that
+// duplicate items with identical rectangles and data can be deleted
+// individually, and that T does not need to be comparable.
+// Returns false if the item is no longer in the tree.
+func (tr *RTreeGN[N, T]) DeleteHandle(h ItemHandle[N]) bool {
+	if h.seq == 0 {
+		return false
+	}
+	return tr.delete(h.rect.min, h.rect.max, tr.empty, h.seq)
+}
+
+// InsertHandle inserts data into tree and returns a handle that refers to
+// exactly this item.
+func (tr *RTreeG[T]) InsertHandle(min, max [2]float64, data T,
+) ItemHandle[float64] {
+	return tr.base.InsertHandle(min, max, data)
+}
+
+// DeleteHandle deletes the item that the handle refers to.
+// Returns false if the item is no longer in the tree.
+func (tr *RTreeG[T]) DeleteHandle(h ItemHandle[float64]) bool {
+	return tr.base.DeleteHandle(h)
+}
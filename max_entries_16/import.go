@@ -0,0 +1,261 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+var errExportVersion = errors.New("rtree: unsupported export version")
+
+var errExportChunk = errors.New("rtree: invalid export chunk")
+
+// ImportStream adds the items of a stream that was written by Export, where
+// the data of each item is decoded by the decode function.
+// Each chunk is packed into a subtree, like LoadBulk does, which is then
+// added to the tree as a whole. This is much faster than inserting the items
+// one at a time, and the subtrees keep the spatial clustering of the chunks.
+//
+// Use ConcurrentRTree.ImportStream for searching the tree while the stream
+// is still loading.
+//
+// Returns the first error from reading the stream or from decode. The items
+// of the chunks before are added in either case.
+func (tr *RTreeGN[N, T]) ImportStream(r io.Reader,
+	decode func(b []byte) (T, error),
+) error {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	return readExport(r, decode, tr.importChunk)
+}
+
+// ImportStream adds the items of a stream that was written by Export,
+// publishing a new snapshot after each chunk, so that the items that have
+// been loaded so far can be searched while the rest of the stream is still
+// loading. See RTreeGN.ImportStream.
+func (tr *ConcurrentRTree[N, T]) ImportStream(r io.Reader,
+	decode func(b []byte) (T, error),
+) error {
+	return readExport(r, decode, func(items []Item[N, T]) {
+		tr.Update(func(tr *RTreeGN[N, T]) {
+			tr.importChunk(items)
+		})
+	})
+}
+
+// readExport reads the chunks of a stream that was written by Export, and
+// calls fn with the items of each chunk.
+// The items passed to fn are only valid until fn returns.
+func readExport[N numeric, T any](r io.Reader,
+	decode func(b []byte) (T, error), fn func(items []Item[N, T]),
+) error {
+	br := bufio.NewReader(r)
+	version, err := br.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if version != exportVersion {
+		return errExportVersion
+	}
+	var buf []byte
+	var items []Item[N, T]
+	for {
+		buf, err = readRecord(br, buf)
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return err
+		}
+		items, err = decodeExportChunk(buf, items[:0], decode)
+		if err != nil {
+			return err
+		}
+		fn(items)
+	}
+}
+
+// decodeExportChunk appends the items of a chunk that was written by Export.
+func decodeExportChunk[N numeric, T any](rec []byte, items []Item[N, T],
+	decode func(b []byte) (T, error),
+) ([]Item[N, T], error) {
+	if _, rec = readExportRect[N](rec); rec == nil {
+		return items, errExportChunk
+	}
+	count, n := binary.Uvarint(rec)
+	if n <= 0 {
+		return items, errExportChunk
+	}
+	rec = rec[n:]
+	for i := uint64(0); i < count; i++ {
+		var ir rect[N]
+		if ir, rec = readExportRect[N](rec); rec == nil {
+			return items, errExportChunk
+		}
+		size, n := binary.Uvarint(rec)
+		if n <= 0 || uint64(len(rec)-n) < size {
+			return items, errExportChunk
+		}
+		data, err := decode(rec[n : n+int(size)])
+		if err != nil {
+			return items, err
+		}
+		rec = rec[n+int(size):]
+		items = append(items, Item[N, T]{ir.min, ir.max, data})
+	}
+	if len(rec) != 0 {
+		return items, errExportChunk
+	}
+	return items, nil
+}
+
+// readExportRect reads a rectangle that was written by appendExportRect.
+// Returns a nil slice when b is too short.
+func readExportRect[N numeric](b []byte) (rect[N], []byte) {
+	if len(b) < 32 {
+		return rect[N]{}, nil
+	}
+	var vals [4]N
+	for i := range vals {
+		vals[i] = N(math.Float64frombits(binary.LittleEndian.Uint64(b[i*8:])))
+	}
+	return rect[N]{[2]N{vals[0], vals[1]}, [2]N{vals[2], vals[3]}}, b[32:]
+}
+
+// importChunk packs the items into a subtree and adds it to the tree.
+func (tr *RTreeGN[N, T]) importChunk(items []Item[N, T]) {
+	if tr.strict {
+		for i := range items {
+			if !validRect(items[i].Min, items[i].Max) {
+				panic(ErrInvalidRect)
+			}
+		}
+	}
+	if len(items) == 0 {
+		return
+	}
+	if writeGuardEnabled {
+		tr.writes.enter()
+		defer tr.writes.exit()
+	}
+	bitems := make([]bulkItem[N, T], len(items))
+	for i := range items {
+		var seq uint64
+		if tr.ordered {
+			tr.seq++
+			seq = tr.seq
+		}
+		bitems[i] = bulkItem[N, T]{
+			rect: rect[N]{items[i].Min, items[i].Max},
+			data: items[i].Data,
+			seq:  seq,
+		}
+		if tr.logging() {
+			tr.log(OpInsert, items[i].Min, items[i].Max, items[i].Data)
+		}
+	}
+	tr.gen++
+	tr.count += len(items)
+	tr.initPool()
+	tr.graft(tr.buildBulk(bitems, 1, 0))
+	tr.fixAggs()
+}
+
+// graft adds the subtree to the tree, at the level where the nodes have the
+// same height as the subtree.
+func (tr *RTreeGN[N, T]) graft(sub *node[N, T]) {
+	if tr.root == nil {
+		tr.root = sub
+		tr.rect = sub.rect()
+		return
+	}
+	sh, th := sub.height(), tr.root.height()
+	if sh > th {
+		// graft the tree onto the subtree instead
+		tr.root, sub = sub, tr.root
+		tr.rect = tr.root.rect()
+		sh, th = th, sh
+	}
+	sr := sub.rect()
+	if sh == th {
+		tr.root = tr.newBranch(tr.root, sub)
+		tr.rect.expand(&sr)
+		return
+	}
+	tr.cow(&tr.root)
+	if tr.nodeGraft(tr.root, th, sub, &sr) {
+		left := tr.root
+		right := tr.splitNode(tr.rect, left)
+		tr.root = tr.newBranch(left, right)
+		tr.graft(sub)
+		return
+	}
+	tr.rect.expand(&sr)
+}
+
+// newBranch returns a new branch with the two nodes as its children.
+func (tr *RTreeGN[N, T]) newBranch(a, b *node[N, T]) *node[N, T] {
+	n := tr.newNode(false)
+	n.rects.set(0, a.rect())
+	n.rects.set(1, b.rect())
+	n.children()[0] = a
+	n.children()[1] = b
+	n.count = 2
+	if n.ordered() {
+		n.sort()
+	}
+	return n
+}
+
+// nodeGraft adds the subtree, whose rectangle is sr, to n, whose height is
+// height, at the level where the nodes have the same height as the subtree.
+// Returns true when n is full and must be split by the caller.
+func (tr *RTreeGN[N, T]) nodeGraft(n *node[N, T], height int,
+	sub *node[N, T], sr *rect[N],
+) (split bool) {
+	if height == sub.height()+1 {
+		if int(n.count) >= tr.fanout(false) {
+			return true
+		}
+		n.rects.set(int(n.count), *sr)
+		n.children()[n.count] = sub
+		n.count++
+		if n.ordered() {
+			n.orderToLeft(int(n.count) - 1)
+		}
+		return false
+	}
+	index := tr.chooseSubtree(n, sr)
+	children := n.children()
+	tr.cow(&children[index])
+	if tr.nodeGraft(children[index], height-1, sub, sr) {
+		if int(n.count) >= tr.fanout(false) {
+			return true
+		}
+		tr.splitChild(n, index)
+		return tr.nodeGraft(n, height, sub, sr)
+	}
+	n.rects.expand(index, sr)
+	if n.ordered() {
+		n.orderToLeft(index)
+	}
+	return false
+}
+
+// ImportStream adds the items of a stream that was written by Export.
+// See RTreeGN.ImportStream.
+func (tr *RTreeG[T]) ImportStream(r io.Reader,
+	decode func(b []byte) (T, error),
+) error {
+	return tr.base.ImportStream(r, decode)
+}
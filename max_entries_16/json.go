@@ -0,0 +1,55 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "encoding/json"
+
+// Items returns all items in the tree.
+func (tr *RTreeGN[N, T]) Items() []Item[N, T] {
+	items := make([]Item[N, T], 0, tr.count)
+	tr.Scan(func(min, max [2]N, data T) bool {
+		items = append(items, Item[N, T]{min, max, data})
+		return true
+	})
+	return items
+}
+
+// MarshalJSON encodes the tree as a JSON array of items, where each item is
+// an object with "min", "max", and "data" fields.
+func (tr *RTreeGN[N, T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tr.Items())
+}
+
+// UnmarshalJSON decodes a JSON array of items, as written by MarshalJSON,
+// replacing all items in the tree.
+func (tr *RTreeGN[N, T]) UnmarshalJSON(data []byte) error {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	var items []Item[N, T]
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	tr.Clear()
+	tr.LoadBulk(items)
+	return nil
+}
+
+// Items returns all items in the tree.
+func (tr *RTreeG[T]) Items() []Item[float64, T] {
+	return tr.base.Items()
+}
+
+// MarshalJSON encodes the tree as a JSON array of items, where each item is
+// an object with "min", "max", and "data" fields.
+func (tr *RTreeG[T]) MarshalJSON() ([]byte, error) {
+	return tr.base.MarshalJSON()
+}
+
+// UnmarshalJSON decodes a JSON array of items, as written by MarshalJSON,
+// replacing all items in the tree.
+func (tr *RTreeG[T]) UnmarshalJSON(data []byte) error {
+	return tr.base.UnmarshalJSON(data)
+}
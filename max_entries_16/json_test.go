@@ -0,0 +1,56 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	var tr RTreeG[string]
+	tr.Insert([2]float64{1, 2}, [2]float64{3, 4}, "hello")
+	data, err := json.Marshal(&tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `[{"min":[1,2],"max":[3,4],"data":"hello"}]`
+	if string(data) != expect {
+		t.Fatalf("expected %s, got %s", expect, data)
+	}
+
+	var tr1 RTreeGN[int32, int]
+	for i := 0; i < 1000; i++ {
+		r := randRect32('r')
+		tr1.Insert([2]int32{int32(r.min[0]), int32(r.min[1])},
+			[2]int32{int32(r.max[0]), int32(r.max[1])}, i)
+	}
+	data, err = json.Marshal(&tr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tr2 RTreeGN[int32, int]
+	tr2.Insert([2]int32{1, 1}, [2]int32{1, 1}, -1)
+	if err := json.Unmarshal(data, &tr2); err != nil {
+		t.Fatal(err)
+	}
+	if tr2.Len() != tr1.Len() {
+		t.Fatalf("expected %d, got %d", tr1.Len(), tr2.Len())
+	}
+	if err := tr2.SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range tr1.Items() {
+		if !tr2.Exists(item.Min, item.Max, item.Data) {
+			t.Fatalf("item %d not found", item.Data)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{}`), &tr2); err == nil {
+		t.Fatal("expected error")
+	}
+	if tr2.Len() != tr1.Len() {
+		t.Fatalf("expected %d, got %d", tr1.Len(), tr2.Len())
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "maps"
+
+// KeyedRTreeGN is an R-tree with a secondary index from a unique key of each
+// item to the item, such as the ID of a vehicle in a fleet of moving
+// objects. Items can be looked up by their key without searching, and
+// deleted or moved by their key without knowing their current rectangle.
+type KeyedRTreeGN[N numeric, T any, K comparable] struct {
+	key   func(data T) K
+	items map[K]keyedItem[N, T]
+	base  RTreeGN[N, T]
+}
+
+type keyedItem[N numeric, T any] struct {
+	handle ItemHandle[N]
+	data   T
+}
+
+// NewKeyedRTreeGN returns a new tree that indexes the items by the key that
+// is returned by the provided function.
+func NewKeyedRTreeGN[N numeric, T any, K comparable](key func(data T) K,
+) *KeyedRTreeGN[N, T, K] {
+	return &KeyedRTreeGN[N, T, K]{key: key, items: make(map[K]keyedItem[N, T])}
+}
+
+// Insert data into tree.
+// Returns false, without inserting, if an item with the same key is already
+// in the tree.
+func (tr *KeyedRTreeGN[N, T, K]) Insert(min, max [2]N, data T) bool {
+	k := tr.key(data)
+	if _, ok := tr.items[k]; ok {
+		return false
+	}
+	tr.items[k] = keyedItem[N, T]{tr.base.InsertHandle(min, max, data), data}
+	return true
+}
+
+// GetByKey returns the item with the key.
+func (tr *KeyedRTreeGN[N, T, K]) GetByKey(k K,
+) (min, max [2]N, data T, ok bool) {
+	item, ok := tr.items[k]
+	if !ok {
+		return min, max, data, false
+	}
+	min, max = item.handle.Rect()
+	return min, max, item.data, true
+}
+
+// DeleteByKey deletes the item with the key.
+// Returns false if there is no such item.
+func (tr *KeyedRTreeGN[N, T, K]) DeleteByKey(k K) bool {
+	item, ok := tr.items[k]
+	if !ok {
+		return false
+	}
+	tr.base.DeleteHandle(item.handle)
+	delete(tr.items, k)
+	return true
+}
+
+// UpdateByKey moves the item with the key to a new rectangle.
+// Returns false if there is no such item.
+func (tr *KeyedRTreeGN[N, T, K]) UpdateByKey(k K, newMin, newMax [2]N) bool {
+	item, ok := tr.items[k]
+	if !ok {
+		return false
+	}
+	tr.base.DeleteHandle(item.handle)
+	item.handle = tr.base.InsertHandle(newMin, newMax, item.data)
+	tr.items[k] = item
+	return true
+}
+
+// Upsert inserts data into tree, or replaces the item with the same key,
+// which is moved when the rectangle changed.
+// This is the most common operation for tracking moving objects, where the
+// latest position of each object replaces its previous one.
+// Returns true if an item was replaced.
+func (tr *KeyedRTreeGN[N, T, K]) Upsert(min, max [2]N, data T) bool {
+	k := tr.key(data)
+	item, ok := tr.items[k]
+	if ok {
+		tr.base.DeleteHandle(item.handle)
+	}
+	tr.items[k] = keyedItem[N, T]{tr.base.InsertHandle(min, max, data), data}
+	return ok
+}
+
+// Len returns the number of items in tree.
+func (tr *KeyedRTreeGN[N, T, K]) Len() int {
+	return tr.base.Len()
+}
+
+// Bounds returns the minimum bounding rect.
+func (tr *KeyedRTreeGN[N, T, K]) Bounds() (min, max [2]N) {
+	return tr.base.Bounds()
+}
+
+// Search for items in tree that intersect the provided rectangle.
+func (tr *KeyedRTreeGN[N, T, K]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	tr.base.Search(min, max, iter)
+}
+
+// Nearby performs a kNN-type operation on the index, like RTreeGN.Nearby.
+func (tr *KeyedRTreeGN[N, T, K]) Nearby(
+	dist func(min, max [2]N, data T, item bool) N,
+	iter func(min, max [2]N, data T, dist N) bool,
+) {
+	tr.base.Nearby(dist, iter)
+}
+
+// Scan all items in the tree.
+func (tr *KeyedRTreeGN[N, T, K]) Scan(iter func(min, max [2]N, data T) bool) {
+	tr.base.Scan(iter)
+}
+
+// Copy the tree.
+// The tree itself is copied using copy-on-write, but the key index is copied
+// right away.
+func (tr *KeyedRTreeGN[N, T, K]) Copy() *KeyedRTreeGN[N, T, K] {
+	return &KeyedRTreeGN[N, T, K]{
+		key:   tr.key,
+		items: maps.Clone(tr.items),
+		base:  *tr.base.Copy(),
+	}
+}
+
+// Clear will delete all items.
+func (tr *KeyedRTreeGN[N, T, K]) Clear() {
+	clear(tr.items)
+	tr.base.Clear()
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "math"
+
+// lodGrid divides the searched rectangle of SearchLOD into cells, where each
+// cell holds at most one item.
+type lodGrid[N numeric] struct {
+	target rect[N]
+	size   int
+	filled []bool
+}
+
+// cell returns the cell of the center of the rectangle along an axis.
+func (g *lodGrid[N]) cell(r *rect[N], axis int) int {
+	lo := float64(g.target.min[axis])
+	span := float64(g.target.max[axis]) - lo
+	if span <= 0 {
+		return 0
+	}
+	c := (float64(r.min[axis])+float64(r.max[axis]))/2 - lo
+	return max(0, min(g.size-1, int(c/span*float64(g.size))))
+}
+
+// cells returns the range of cells of the centers of the items in the node
+// with the rectangle r.
+func (g *lodGrid[N]) cells(r *rect[N], axis int) (int, int) {
+	lo := rect[N]{r.min, r.min}
+	hi := rect[N]{r.max, r.max}
+	return g.cell(&lo, axis), g.cell(&hi, axis)
+}
+
+// SearchLOD searches for a representative subset of at most maxResults
+// items that intersect the provided rectangle, such as for rendering a dense
+// area of a map.
+// The rectangle is divided into a grid of about maxResults cells, and each
+// cell gets the item with the highest priority whose center is in the cell,
+// so that the results are spread out over the area, while the most important
+// items are always shown. The iter function returns the items from the
+// highest priority to the lowest.
+//
+// When priority is nil, the score function provided to SetScore is used
+// instead, and the maximum score of every node is used to skip the branches
+// that are in cells that already have an item, along with the rest of the
+// tree once enough items are found. Otherwise every item in the rectangle is
+// visited.
+//
+// Panics if priority is nil and no score function has been set.
+func (tr *RTreeGN[N, T]) SearchLOD(min, max [2]N, maxResults int,
+	priority func(data T) float64,
+	iter func(min, max [2]N, data T, priority float64) bool,
+) {
+	idx := -1
+	if priority == nil {
+		if tr.score == nil {
+			panic(errNoScore)
+		}
+		priority = tr.score.agg.Item
+		idx = tr.score.idx
+	}
+	target := rect[N]{min, max}
+	if tr.root == nil || maxResults <= 0 || !target.intersects(&tr.rect) {
+		return
+	}
+	bound := func(n *node[N, T]) float64 {
+		if idx == -1 {
+			return math.Inf(1)
+		}
+		return n.aggs.vals[idx].(float64)
+	}
+	size := int(math.Ceil(math.Sqrt(float64(maxResults))))
+	g := lodGrid[N]{target: target, size: size,
+		filled: make([]bool, size*size)}
+	var q pqueue[topkElem[N, T]]
+	q.push(-bound(tr.root), topkElem[N, T]{rect: tr.rect, node: tr.root})
+	for maxResults > 0 {
+		prio, e, ok := q.pop()
+		if !ok {
+			return
+		}
+		if e.node == nil {
+			i := g.cell(&e.rect, 1)*size + g.cell(&e.rect, 0)
+			if g.filled[i] {
+				continue
+			}
+			g.filled[i] = true
+			if !iter(e.rect.min, e.rect.max, e.data, -prio) {
+				return
+			}
+			maxResults--
+			continue
+		}
+		if idx != -1 {
+			// skip the node when all of its items are in a single cell that
+			// already has an item with a higher priority
+			x0, x1 := g.cells(&e.rect, 0)
+			y0, y1 := g.cells(&e.rect, 1)
+			if x0 == x1 && y0 == y1 && g.filled[y0*size+x0] {
+				continue
+			}
+		}
+		rects := &e.node.rects
+		if e.node.leaf() {
+			items := e.node.items()
+			for i := 0; i < int(e.node.count); i++ {
+				if r := rects.at(i); target.intersects(&r) {
+					q.push(-priority(items[i]),
+						topkElem[N, T]{rect: r, data: items[i]})
+				}
+			}
+		} else {
+			children := e.node.children()
+			for i := 0; i < int(e.node.count); i++ {
+				if r := rects.at(i); target.intersects(&r) {
+					q.push(-bound(children[i]),
+						topkElem[N, T]{rect: r, node: children[i]})
+				}
+			}
+		}
+	}
+}
+
+// SearchLOD searches for a representative subset of at most maxResults
+// items that intersect the provided rectangle. See RTreeGN.SearchLOD.
+func (tr *RTreeG[T]) SearchLOD(min, max [2]float64, maxResults int,
+	priority func(data T) float64,
+	iter func(min, max [2]float64, data T, priority float64) bool,
+) {
+	tr.base.SearchLOD(min, max, maxResults, priority, iter)
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "sync/atomic"
+
+// MultiRTreeGN is an R-tree where items with identical rectangles share a
+// single leaf entry that holds all of their values.
+// This keeps the tree small and the node splits sane for datasets with many
+// duplicate rectangles, such as millions of events at the same coordinates.
+type MultiRTreeGN[N numeric, T any] struct {
+	icow  uint64
+	count int
+	base  RTreeGN[N, *multiSlot[T]]
+}
+
+// multiSlot holds the values for a single rectangle. A slot may only be
+// modified in place by the tree whose icow matches, otherwise it's shared
+// with a copy of the tree.
+type multiSlot[T any] struct {
+	icow   uint64
+	values []T
+}
+
+// newSlot returns a slot that is owned by this tree.
+func (tr *MultiRTreeGN[N, T]) newSlot(values []T) *multiSlot[T] {
+	if tr.icow == 0 {
+		tr.icow = atomic.AddUint64(&gcow, 1)
+	}
+	return &multiSlot[T]{icow: tr.icow, values: values}
+}
+
+func (tr *MultiRTreeGN[N, T]) owns(slot *multiSlot[T]) bool {
+	return tr.icow != 0 && slot.icow == tr.icow
+}
+
+// replace swaps a shared slot for a new one that is owned by this tree.
+func (tr *MultiRTreeGN[N, T]) replace(min, max [2]N, slot *multiSlot[T],
+	values []T,
+) {
+	tr.base.delete(min, max, slot, 0)
+	tr.base.insert(min, max, tr.newSlot(values), 0)
+}
+
+func (tr *MultiRTreeGN[N, T]) slot(min, max [2]N) *multiSlot[T] {
+	slot, _ := tr.base.GetEqual(min, max, nil)
+	return slot
+}
+
+// Insert data into tree.
+func (tr *MultiRTreeGN[N, T]) Insert(min, max [2]N, data T) {
+	slot := tr.slot(min, max)
+	switch {
+	case slot == nil:
+		tr.base.insert(min, max, tr.newSlot([]T{data}), 0)
+	case tr.owns(slot):
+		slot.values = append(slot.values, data)
+		tr.base.gen++
+	default:
+		values := make([]T, len(slot.values), len(slot.values)+1)
+		copy(values, slot.values)
+		tr.replace(min, max, slot, append(values, data))
+	}
+	tr.count++
+}
+
+// Delete data from tree. Returns true if the data was found and deleted.
+func (tr *MultiRTreeGN[N, T]) Delete(min, max [2]N, data T) bool {
+	slot := tr.slot(min, max)
+	if slot == nil {
+		return false
+	}
+	idx := -1
+	for i := range slot.values {
+		if compare(slot.values[i], data) {
+			idx = i
+			break
+		}
+	}
+	switch {
+	case idx == -1:
+		return false
+	case len(slot.values) == 1:
+		tr.base.delete(min, max, slot, 0)
+	case tr.owns(slot):
+		last := len(slot.values) - 1
+		copy(slot.values[idx:], slot.values[idx+1:])
+		var empty T
+		slot.values[last] = empty
+		slot.values = slot.values[:last]
+		tr.base.gen++
+	default:
+		values := make([]T, 0, len(slot.values)-1)
+		values = append(values, slot.values[:idx]...)
+		values = append(values, slot.values[idx+1:]...)
+		tr.replace(min, max, slot, values)
+	}
+	tr.count--
+	return true
+}
+
+// Len returns the number of items in tree.
+func (tr *MultiRTreeGN[N, T]) Len() int {
+	return tr.count
+}
+
+// Rects returns the number of distinct rectangles in the tree.
+func (tr *MultiRTreeGN[N, T]) Rects() int {
+	return tr.base.Len()
+}
+
+// Bounds returns the minimum bounding rect.
+func (tr *MultiRTreeGN[N, T]) Bounds() (min, max [2]N) {
+	return tr.base.Bounds()
+}
+
+// Search for items in tree that intersect the provided rectangle.
+// Items with identical rectangles are returned in the order they were
+// inserted.
+func (tr *MultiRTreeGN[N, T]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	tr.base.Search(min, max, func(min, max [2]N, slot *multiSlot[T]) bool {
+		for _, data := range slot.values {
+			if !iter(min, max, data) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Scan all items in the tree.
+func (tr *MultiRTreeGN[N, T]) Scan(iter func(min, max [2]N, data T) bool) {
+	tr.base.Scan(func(min, max [2]N, slot *multiSlot[T]) bool {
+		for _, data := range slot.values {
+			if !iter(min, max, data) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Copy the tree.
+// This is a copy-on-write operation and is very fast because it only performs
+// a shadowed copy.
+func (tr *MultiRTreeGN[N, T]) Copy() *MultiRTreeGN[N, T] {
+	tr.icow = atomic.AddUint64(&gcow, 1)
+	return &MultiRTreeGN[N, T]{
+		icow:  atomic.AddUint64(&gcow, 1),
+		count: tr.count,
+		base:  *tr.base.Copy(),
+	}
+}
+
+// Clear will delete all items.
+func (tr *MultiRTreeGN[N, T]) Clear() {
+	tr.count = 0
+	tr.base.Clear()
+}
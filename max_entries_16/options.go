@@ -0,0 +1,125 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Option configures a tree that is created by New.
+type Option[N numeric, T any] func(tr *RTreeGN[N, T])
+
+// New returns a new empty tree that is configured by the options.
+// The zero value of RTreeGN is an empty tree with the default options.
+//
+//	tr := rtree.New(
+//		rtree.WithMinFill[float64, string](40),
+//		rtree.WithSplitter(rtree.QuadraticSplitter[float64, string]()),
+//	)
+func New[N numeric, T any](opts ...Option[N, T]) *RTreeGN[N, T] {
+	tr := new(RTreeGN[N, T])
+	for _, opt := range opts {
+		opt(tr)
+	}
+	return tr
+}
+
+// WithOrdering sets whether the rects in each node are kept ordered by their
+// min x, which allows for searches to stop scanning a node early. Turning it
+// off makes inserts and deletes a bit faster, at the cost of searches.
+// The default is true.
+func WithOrdering[N numeric, T any](ordered bool) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.unordered = !ordered
+	}
+}
+
+// WithMinFill sets the minimum fill of a node, as a percentage of the
+// maximum number of entries, from 0 to 50.
+// When a Delete leaves a node with fewer entries, the node is removed and
+// its items are inserted again, which keeps the tree compact for workloads
+// that delete a lot, at the cost of slower deletes.
+// The default of zero only removes nodes that are empty.
+func WithMinFill[N numeric, T any](percent int) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		percent = min(max(percent, 0), 50)
+		tr.minFill = maxEntries * percent / 100
+	}
+}
+
+// WithFanout sets the maximum number of entries of the leaves and of the
+// branches, which can differ, such as full leaves with smaller branches.
+// Smaller branches are faster to scan when searching, but make the tree
+// taller. Each is limited to between 4 and the maximum number of entries of
+// a node, which is the default for both.
+func WithFanout[N numeric, T any](leaf, branch int) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.leafFanout = min(max(leaf, 4), maxEntries)
+		tr.branchFanout = min(max(branch, 4), maxEntries)
+	}
+}
+
+// fanout returns the maximum number of entries of a leaf or a branch.
+func (tr *RTreeGN[N, T]) fanout(leaf bool) int {
+	if leaf && tr.leafFanout > 0 {
+		return tr.leafFanout
+	}
+	if !leaf && tr.branchFanout > 0 {
+		return tr.branchFanout
+	}
+	return maxEntries
+}
+
+// WithDeterministic sets whether the same sequence of operations always
+// builds the same tree, node for node, such as for reproducible snapshots in
+// tests or for content-addressed storage.
+// Bulk loading, see LoadBulk, sorts the items by their centers using an
+// unstable sort, where the order of items with the same center may change
+// with the number of workers of LoadBulkParallel, or between Go versions.
+// With this option the sorts are stable instead, which is somewhat slower.
+// Automatic compaction, see StartAutoCompaction, depends on timing and must
+// not be used with deterministic trees.
+func WithDeterministic[N numeric, T any](deterministic bool) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.deterministic = deterministic
+	}
+}
+
+// WithSplitter sets the splitter, see SetSplitter.
+func WithSplitter[N numeric, T any](s Splitter[N, T]) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.SetSplitter(s)
+	}
+}
+
+// WithChooseSubtree sets the strategy for picking the subtree that a new item
+// is inserted into, see SetChooseSubtree.
+func WithChooseSubtree[N numeric, T any](choose ChooseSubtree) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.SetChooseSubtree(choose)
+	}
+}
+
+// WithPoolAllocator sets the pool for the nodes, see SetAllocator.
+func WithPoolAllocator[N numeric, T any](alloc *PoolAllocator[N, T],
+) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.SetAllocator(alloc)
+	}
+}
+
+// WithComparator sets the function that is used by Delete and Exists to
+// compare the data of items, such as for data that isn't comparable with ==
+// or for matching items by an ID field.
+// The default compares the data using ==.
+func WithComparator[N numeric, T any](eq func(a, b T) bool) Option[N, T] {
+	return func(tr *RTreeGN[N, T]) {
+		tr.eq = eq
+	}
+}
+
+// equal returns true if the data of two items are equal.
+func (tr *RTreeGN[N, T]) equal(a, b T) bool {
+	if tr.eq != nil {
+		return tr.eq(a, b)
+	}
+	return compare(a, b)
+}
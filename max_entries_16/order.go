@@ -0,0 +1,99 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"slices"
+)
+
+// SetInsertionOrder turns on or off tracking the order in which items are
+// inserted, which is needed for ScanByInsertionOrder.
+// When on, every inserted item is given the next sequence number of the
+// tree, which is kept with the item in its leaf, so T doesn't need to store
+// it. Items that were inserted while it was off have no sequence number.
+func (tr *RTreeGN[N, T]) SetInsertionOrder(track bool) {
+	tr.ordered = track
+}
+
+// InsertionOrder returns true if the tree tracks the order in which items
+// are inserted.
+func (tr *RTreeGN[N, T]) InsertionOrder() bool {
+	return tr.ordered
+}
+
+// seqItem is an item along with its sequence number.
+type seqItem[N numeric, T any] struct {
+	seq  uint64
+	item Item[N, T]
+}
+
+// ScanByInsertionOrder scans all items in the tree in the order that they
+// were inserted, see SetInsertionOrder. A replaced item counts as inserted at
+// the time it was replaced.
+// Items that have no sequence number, because they were inserted while the
+// insertion order was not tracked, are returned first.
+//
+// The items are collected before calling iter, so it's safe for iter to
+// modify the tree.
+func (tr *RTreeGN[N, T]) ScanByInsertionOrder(
+	iter func(min, max [2]N, data T) bool,
+) {
+	if tr.root == nil {
+		return
+	}
+	items := make([]seqItem[N, T], 0, tr.count)
+	items = tr.root.appendSeqItems(items)
+	slices.SortStableFunc(items, func(a, b seqItem[N, T]) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	for i := range items {
+		if !iter(items[i].item.Min, items[i].item.Max, items[i].item.Data) {
+			return
+		}
+	}
+}
+
+func (n *node[N, T]) appendSeqItems(items []seqItem[N, T]) []seqItem[N, T] {
+	if n.leaf() {
+		data := n.items()[:n.count]
+		seqs := n.seqs()
+		for i := range data {
+			var seq uint64
+			if seqs != nil {
+				seq = seqs[i]
+			}
+			r := n.rects.at(i)
+			items = append(items, seqItem[N, T]{seq,
+				Item[N, T]{r.min, r.max, data[i]}})
+		}
+		return items
+	}
+	children := n.children()[:n.count]
+	for i := range children {
+		items = children[i].appendSeqItems(items)
+	}
+	return items
+}
+
+// SetInsertionOrder turns on or off tracking the order in which items are
+// inserted, which is needed for ScanByInsertionOrder.
+func (tr *RTreeG[T]) SetInsertionOrder(track bool) {
+	tr.base.SetInsertionOrder(track)
+}
+
+// InsertionOrder returns true if the tree tracks the order in which items
+// are inserted.
+func (tr *RTreeG[T]) InsertionOrder() bool {
+	return tr.base.InsertionOrder()
+}
+
+// ScanByInsertionOrder scans all items in the tree in the order that they
+// were inserted.
+func (tr *RTreeG[T]) ScanByInsertionOrder(
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.ScanByInsertionOrder(iter)
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "sync/atomic"
+
+// SearchParallel is like Search, but splits the traversal of the tree into
+// subtrees that are searched by up to the provided number of goroutines.
+// This is useful for queries with large result sets on machines with many
+// cores.
+//
+// The iter function is called concurrently from multiple goroutines and must
+// be safe for concurrent use. The order of the results is undefined.
+// Returning false stops the search, though other goroutines may still call
+// iter a few more times before they notice.
+// The tree must not be modified until SearchParallel returns.
+func (tr *RTreeGN[N, T]) SearchParallel(min, max [2]N, workers int,
+	iter func(min, max [2]N, data T) bool,
+) {
+	target := rect[N]{min, max}
+	if tr.root == nil || !target.intersects(&tr.rect) {
+		return
+	}
+	if workers <= 1 {
+		tr.Search(min, max, iter)
+		return
+	}
+	// Descend level by level until there are enough subtrees to keep all
+	// workers busy.
+	tasks := []*node[N, T]{tr.root}
+	for len(tasks) < workers*4 && !tasks[0].leaf() {
+		var next []*node[N, T]
+		for _, n := range tasks {
+			children := n.children()
+			for i := 0; i < int(n.count); i++ {
+				if r := n.rects.at(i); target.intersects(&r) {
+					next = append(next, children[i])
+				}
+			}
+		}
+		if len(next) == 0 {
+			return
+		}
+		tasks = next
+	}
+	var stop atomic.Bool
+	parallel(len(tasks), workers, func(i int) {
+		if stop.Load() {
+			return
+		}
+		tasks[i].search(target, func(min, max [2]N, data T) bool {
+			if stop.Load() || !iter(min, max, data) {
+				stop.Store(true)
+				return false
+			}
+			return true
+		})
+	})
+}
+
+// SearchParallel is like Search, but splits the traversal of the tree into
+// subtrees that are searched by up to the provided number of goroutines.
+// The iter function must be safe for concurrent use.
+func (tr *RTreeG[T]) SearchParallel(min, max [2]float64, workers int,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.SearchParallel(min, max, workers, iter)
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Partition splits the items of the tree into two new trees, one with the
+// items that intersect the provided rectangle and one with all other items.
+// The tree itself is not modified.
+//
+// Subtrees that are entirely on one side of the split are shared with the
+// new trees using copy-on-write, like Copy does, so only the nodes along the
+// edge of the rectangle are copied.
+// The new trees don't have the logger or the subscriptions of the tree.
+func (tr *RTreeGN[N, T]) Partition(min, max [2]N,
+) (inside, outside *RTreeGN[N, T]) {
+	inside = tr.Copy()
+	outside = tr.Copy()
+	inside.detach()
+	outside.detach()
+	if tr.root == nil {
+		return inside, outside
+	}
+	target := rect[N]{min, max}
+	in, out, inCount, outCount := partitionNode(&target, tr.root, inside,
+		outside)
+	inside.setPartition(in, inCount)
+	outside.setPartition(out, outCount)
+	return inside, outside
+}
+
+// setPartition makes the node the root of the tree.
+func (tr *RTreeGN[N, T]) setPartition(root *node[N, T], count int) {
+	tr.gen++
+	tr.count = count
+	tr.root = root
+	if root == nil {
+		tr.rect = rect[N]{}
+		return
+	}
+	for !tr.root.leaf() && tr.root.count == 1 {
+		tr.root = tr.root.children()[0]
+	}
+	tr.rect = tr.root.rect()
+	tr.fixAggs()
+}
+
+// partitionNode splits the node into a node with the items that intersect
+// the target and a node with the others, either of which is nil when empty.
+// The returned nodes are the original node when all of its items are on the
+// same side.
+func partitionNode[N numeric, T any](target *rect[N], n *node[N, T],
+	inside, outside *RTreeGN[N, T],
+) (in, out *node[N, T], inCount, outCount int) {
+	if n.leaf() {
+		for i := 0; i < int(n.count); i++ {
+			if r := n.rects.at(i); r.intersects(target) {
+				inCount++
+			}
+		}
+		outCount = int(n.count) - inCount
+		switch {
+		case outCount == 0:
+			return n, nil, inCount, 0
+		case inCount == 0:
+			return nil, n, 0, outCount
+		}
+		in, out = inside.newNode(true), outside.newNode(true)
+		items, seqs := n.items(), n.seqs()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			dst := out
+			if r.intersects(target) {
+				dst = in
+			}
+			dst.rects.set(int(dst.count), r)
+			dst.items()[dst.count] = items[i]
+			if seqs != nil && seqs[i] != 0 {
+				dst.allocSeqs()[dst.count] = seqs[i]
+			}
+			dst.count++
+		}
+		return in, out, inCount, outCount
+	}
+	var ins, outs [maxEntries]*node[N, T]
+	var nin, nout int
+	split := false // some child was split between both sides
+	children := n.children()[:n.count]
+	for i := range children {
+		var cin, cout *node[N, T]
+		var cinCount, coutCount int
+		r := n.rects.at(i)
+		switch {
+		case target.contains(&r):
+			cin, cinCount = children[i], children[i].deepCount()
+		case !target.intersects(&r):
+			cout, coutCount = children[i], children[i].deepCount()
+		default:
+			cin, cout, cinCount, coutCount = partitionNode(target,
+				children[i], inside, outside)
+			split = split || (cin != nil && cout != nil)
+		}
+		if cin != nil {
+			ins[nin] = cin
+			nin++
+			inCount += cinCount
+		}
+		if cout != nil {
+			outs[nout] = cout
+			nout++
+			outCount += coutCount
+		}
+	}
+	switch {
+	case !split && nout == 0:
+		return n, nil, inCount, 0
+	case !split && nin == 0:
+		return nil, n, 0, outCount
+	}
+	return partitionBranch(inside, ins[:nin]),
+		partitionBranch(outside, outs[:nout]), inCount, outCount
+}
+
+// partitionBranch returns a new branch with the children, or nil if there
+// are no children.
+func partitionBranch[N numeric, T any](tr *RTreeGN[N, T],
+	children []*node[N, T],
+) *node[N, T] {
+	if len(children) == 0 {
+		return nil
+	}
+	n := tr.newNode(false)
+	for i, child := range children {
+		n.rects.set(i, child.rect())
+		n.children()[i] = child
+	}
+	n.count = int16(len(children))
+	if n.ordered() && !n.issorted() {
+		n.sort()
+	}
+	return n
+}
+
+// Partition splits the items of the tree into two new trees, one with the
+// items that intersect the provided rectangle and one with all other items.
+func (tr *RTreeG[T]) Partition(min, max [2]float64,
+) (inside, outside *RTreeG[T]) {
+	in, out := tr.base.Partition(min, max)
+	return &RTreeG[T]{*in}, &RTreeG[T]{*out}
+}
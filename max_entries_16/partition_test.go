@@ -0,0 +1,79 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestPartition(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for _, target := range []rect[float64]{
+		{[2]float64{-50, -20}, [2]float64{60, 30}},
+		{[2]float64{-180, -90}, [2]float64{180, 90}},
+		{[2]float64{1000, 1000}, [2]float64{1001, 1001}},
+	} {
+		in, out := tr.Partition(target.min, target.max)
+		for _, part := range []*RTreeG[int]{in, out} {
+			if err := part.SanityCheck(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var nin int
+		for i := range rects {
+			inside := rects[i].intersects(&target)
+			if inside {
+				nin++
+			}
+			if in.Exists(rects[i].min, rects[i].max, i) != inside ||
+				out.Exists(rects[i].min, rects[i].max, i) == inside {
+				t.Fatalf("item %d in the wrong partition", i)
+			}
+		}
+		if in.Len() != nin || out.Len() != len(rects)-nin {
+			t.Fatalf("expected %d/%d, got %d/%d", nin, len(rects)-nin,
+				in.Len(), out.Len())
+		}
+		// the partitions are independent of the original tree
+		in.Clear()
+		out.ScanDelete(func(min, max [2]float64, data int) bool {
+			return true
+		})
+		if tr.Len() != len(rects) {
+			t.Fatalf("expected %d, got %d", len(rects), tr.Len())
+		}
+		if err := tr.SanityCheck(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPartitionLogger(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 1000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	var logged int
+	tr.SetLogger(func(op Op, min, max [2]float64, data int) { logged++ })
+	events, cancel := tr.Subscribe([2]float64{-180, -90}, [2]float64{180, 90})
+	defer cancel()
+	tr.base.estimateMemory()
+	inside, outside := tr.Partition([2]float64{0, 0}, [2]float64{90, 45})
+	for _, part := range []*RTreeG[int]{inside, outside} {
+		if part.base.mem != (memEstimate{}) {
+			t.Fatal("expected no memory estimate")
+		}
+		part.Insert([2]float64{1, 1}, [2]float64{1, 1}, 1001)
+		part.Delete([2]float64{1, 1}, [2]float64{1, 1}, 1001)
+	}
+	if logged != 0 || len(events) != 0 {
+		t.Fatalf("expected no logged ops or events, got %d/%d", logged,
+			len(events))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSearchProfiled(t *testing.T) {
+	var tr RTreeG[int]
+	p := tr.SearchProfiled([2]float64{-180, -90}, [2]float64{180, 90},
+		func(min, max [2]float64, data int) bool { return true })
+	if len(p.Nodes) != 0 || p.Rects != 0 || p.Leaves != 0 || p.Items != 0 {
+		t.Fatal("expected an empty profile")
+	}
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	height := tr.Stats().Height
+	for j := 0; j < 50; j++ {
+		r := randRect('r')
+		r.max[0] += 10
+		r.max[1] += 10
+		var got []int
+		p := tr.SearchProfiled(r.min, r.max,
+			func(min, max [2]float64, data int) bool {
+				got = append(got, data)
+				return true
+			})
+		slices.Sort(got)
+		if !slices.Equal(got, bulkSearch(&tr, r)) {
+			t.Fatal("mismatch")
+		}
+		if p.Items != len(got) {
+			t.Fatalf("expected %d items, got %d", len(got), p.Items)
+		}
+		if min, max := tr.Bounds(); !r.intersects(&rect[float64]{min, max}) {
+			// the root is not visited
+			if len(p.Nodes) != 0 {
+				t.Fatalf("invalid nodes %v", p.Nodes)
+			}
+			continue
+		}
+		if len(p.Nodes) == 0 || len(p.Nodes) > height || p.Nodes[0] != 1 {
+			t.Fatalf("invalid nodes %v", p.Nodes)
+		}
+		if len(p.Nodes) == height && p.Leaves != p.Nodes[height-1] {
+			t.Fatalf("expected %d leaves, got %d", p.Nodes[height-1],
+				p.Leaves)
+		}
+		var nodes int
+		for _, n := range p.Nodes {
+			nodes += n
+		}
+		if p.Rects < nodes-1 || p.Rects > nodes*maxEntries {
+			t.Fatalf("invalid rects %d for %d nodes", p.Rects, nodes)
+		}
+	}
+	p = tr.SearchProfiled([2]float64{-180, -90}, [2]float64{180, 90},
+		func(min, max [2]float64, data int) bool { return false })
+	if p.Items != 1 || p.Leaves != 1 || len(p.Nodes) != height {
+		t.Fatalf("expected to stop at the first item, got %+v", p)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Query is a search with combined predicates, which is built by chaining its
+// methods and performed by Run, such as:
+//
+//	tr.Query().Intersects(a, b).Within(c, d).Filter(f).Limit(10).Run(iter)
+//
+// All predicates are checked in a single traversal of the tree. The
+// rectangles of Intersects and Within are used to skip the nodes that cannot
+// have any matching items.
+type Query[N numeric, T any] struct {
+	tr         *RTreeGN[N, T]
+	intersects []rect[N]
+	within     rect[N] // intersection of the rects of Within
+	hasWithin  bool
+	filters    []func(min, max [2]N, data T) bool
+	limit      int
+	ordered    bool
+	point      rect[N]
+}
+
+// Query returns a new query of the tree, which matches every item until
+// predicates are added.
+func (tr *RTreeGN[N, T]) Query() *Query[N, T] {
+	return &Query[N, T]{tr: tr}
+}
+
+// Query returns a new query of the tree. See RTreeGN.Query.
+func (tr *RTreeG[T]) Query() *Query[float64, T] {
+	return tr.base.Query()
+}
+
+// Intersects limits the query to items that intersect the rectangle.
+func (q *Query[N, T]) Intersects(min, max [2]N) *Query[N, T] {
+	q.intersects = append(q.intersects, rect[N]{min, max})
+	return q
+}
+
+// Within limits the query to items that are inside of the rectangle.
+func (q *Query[N, T]) Within(min, max [2]N) *Query[N, T] {
+	r := rect[N]{min, max}
+	if !q.hasWithin {
+		q.within, q.hasWithin = r, true
+		return q
+	}
+	q.within.min[0] = fmax(q.within.min[0], r.min[0])
+	q.within.min[1] = fmax(q.within.min[1], r.min[1])
+	q.within.max[0] = fmin(q.within.max[0], r.max[0])
+	q.within.max[1] = fmin(q.within.max[1], r.max[1])
+	return q
+}
+
+// Filter limits the query to items for which pred returns true.
+// The pred function is only called for items that match the rectangles of
+// the query.
+func (q *Query[N, T]) Filter(pred func(min, max [2]N, data T) bool,
+) *Query[N, T] {
+	q.filters = append(q.filters, pred)
+	return q
+}
+
+// Limit limits the query to the first n matching items. A limit of zero or
+// less means no limit.
+func (q *Query[N, T]) Limit(n int) *Query[N, T] {
+	q.limit = n
+	return q
+}
+
+// OrderByDistance returns the matching items in order of their distance to
+// the point, nearest first, like Nearby with BoxDist.
+func (q *Query[N, T]) OrderByDistance(p [2]N) *Query[N, T] {
+	q.ordered = true
+	q.point = rect[N]{p, p}
+	return q
+}
+
+// mayMatch returns false when no item inside of the rectangle of a node can
+// match the rectangles of the query.
+func (q *Query[N, T]) mayMatch(r *rect[N]) bool {
+	if q.hasWithin && !q.within.intersects(r) {
+		return false
+	}
+	for i := range q.intersects {
+		if !q.intersects[i].intersects(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// match returns true when the item matches all predicates of the query.
+func (q *Query[N, T]) match(r *rect[N], data T) bool {
+	if !q.mayMatch(r) || q.hasWithin && !q.within.contains(r) {
+		return false
+	}
+	for _, pred := range q.filters {
+		if !pred(r.min, r.max, data) {
+			return false
+		}
+	}
+	return true
+}
+
+// Run performs the query, calling iter for each matching item. Return false
+// from iter to stop the query.
+func (q *Query[N, T]) Run(iter func(min, max [2]N, data T) bool) {
+	tr := q.tr
+	if tr.root == nil || !q.mayMatch(&tr.rect) {
+		return
+	}
+	if q.hasWithin && (q.within.min[0] > q.within.max[0] ||
+		q.within.min[1] > q.within.max[1]) {
+		// the rects of Within don't overlap
+		return
+	}
+	count := 0
+	iter = tr.guard(iter)
+	emit := func(min, max [2]N, data T) bool {
+		count++
+		return iter(min, max, data) && (q.limit <= 0 || count < q.limit)
+	}
+	if q.ordered {
+		q.runOrdered(emit)
+	} else {
+		q.run(tr.root, emit)
+	}
+}
+
+func (q *Query[N, T]) run(n *node[N, T],
+	iter func(min, max [2]N, data T) bool,
+) bool {
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if q.match(&r, items[i]) && !iter(r.min, r.max, items[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		if q.mayMatch(&r) && !q.run(children[i], iter) {
+			return false
+		}
+	}
+	return true
+}
+
+// runOrdered visits the nodes and items in order of their distance to the
+// point of the query.
+func (q *Query[N, T]) runOrdered(iter func(min, max [2]N, data T) bool) {
+	var pq pqueue[topkElem[N, T]]
+	pq.push(0, topkElem[N, T]{rect: q.tr.rect, node: q.tr.root})
+	for {
+		_, e, ok := pq.pop()
+		if !ok {
+			return
+		}
+		if e.node == nil {
+			if !iter(e.rect.min, e.rect.max, e.data) {
+				return
+			}
+			continue
+		}
+		rects := &e.node.rects
+		if e.node.leaf() {
+			items := e.node.items()
+			for i := 0; i < int(e.node.count); i++ {
+				if r := rects.at(i); q.match(&r, items[i]) {
+					pq.push(float64(q.point.boxDist(&r)),
+						topkElem[N, T]{rect: r, data: items[i]})
+				}
+			}
+		} else {
+			children := e.node.children()
+			for i := 0; i < int(e.node.count); i++ {
+				if r := rects.at(i); q.mayMatch(&r) {
+					pq.push(float64(q.point.boxDist(&r)),
+						topkElem[N, T]{rect: r, node: children[i]})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	var tr RTreeG[int]
+	tr.Query().Run(func(min, max [2]float64, data int) bool {
+		t.Fatal("expected no items")
+		return false
+	})
+	N := 10000
+	rects := make([]rect[float64], N)
+	for i := 0; i < N; i++ {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	randQuery := func() rect[float64] {
+		q := randRect('r')
+		q.max[0] = q.min[0] + rand.Float64()*100
+		q.max[1] = q.min[1] + rand.Float64()*50
+		return q
+	}
+	for i := 0; i < 200; i++ {
+		a, c := randQuery(), randQuery()
+		// overlapping rects, so that some items match
+		c.min = a.min
+		odd := func(min, max [2]float64, data int) bool { return data%2 == 1 }
+		limit := rand.Intn(20)
+		p := randRect('p').min
+		var expect []int
+		for j := 0; j < N; j++ {
+			if rects[j].intersects(&a) && c.contains(&rects[j]) && j%2 == 1 {
+				expect = append(expect, j)
+			}
+		}
+		var got []int
+		q := tr.Query().Intersects(a.min, a.max).Within(c.min, c.max).
+			Filter(odd)
+		if i%2 == 0 {
+			q.Run(func(min, max [2]float64, data int) bool {
+				got = append(got, data)
+				return true
+			})
+			slices.Sort(got)
+			if !slices.Equal(got, expect) {
+				t.Fatalf("expected %v, got %v", expect, got)
+			}
+			continue
+		}
+		slices.SortStableFunc(expect, func(x, y int) int {
+			dx := rect[float64]{p, p}
+			return cmp.Compare(dx.boxDist(&rects[x]), dx.boxDist(&rects[y]))
+		})
+		if limit > 0 && limit < len(expect) {
+			expect = expect[:limit]
+		}
+		var dists []float64
+		q.Limit(limit).OrderByDistance(p).Run(
+			func(min, max [2]float64, data int) bool {
+				pr := rect[float64]{p, p}
+				r := rect[float64]{min, max}
+				dists = append(dists, pr.boxDist(&r))
+				got = append(got, data)
+				return true
+			})
+		if len(got) != len(expect) {
+			t.Fatalf("expected %d items, got %d", len(expect), len(got))
+		}
+		if !slices.IsSorted(dists) {
+			t.Fatalf("expected sorted distances, got %v", dists)
+		}
+		for j := range got {
+			pr := rect[float64]{p, p}
+			if pr.boxDist(&rects[got[j]]) != pr.boxDist(&rects[expect[j]]) {
+				t.Fatalf("expected %v, got %v", expect, got)
+			}
+		}
+	}
+
+	// rects of Within that don't overlap
+	tr.Query().Within([2]float64{-10, -10}, [2]float64{0, 0}).
+		Within([2]float64{1, 1}, [2]float64{10, 10}).
+		Run(func(min, max [2]float64, data int) bool {
+			t.Fatal("expected no items")
+			return false
+		})
+}
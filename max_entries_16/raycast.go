@@ -0,0 +1,66 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "math"
+
+// RaycastNearest returns the first item that is hit by the ray starting at
+// origin and heading in the direction dir, along with the distance from the
+// origin to where the ray enters the item rectangle.
+// Items that contain the origin are hit at a distance of zero.
+// Nodes are visited in order of where the ray enters them, and the search
+// stops as soon as no remaining node can be entered before the nearest hit.
+// Returns false if the ray does not hit any item.
+func (tr *RTreeGN[N, T]) RaycastNearest(origin, dir [2]N,
+) (data T, dist float64, ok bool) {
+	if tr.root == nil {
+		return data, 0, false
+	}
+	o := [2]float64{float64(origin[0]), float64(origin[1])}
+	d := [2]float64{float64(dir[0]), float64(dir[1])}
+	t, _, hit := clipLine(o, d, &tr.rect, 0, math.Inf(1))
+	if !hit {
+		return data, 0, false
+	}
+	best := math.Inf(1)
+	var q pqueue[*node[N, T]]
+	q.push(t, tr.root)
+	for {
+		t, n, more := q.pop()
+		if !more || !(t < best) {
+			break
+		}
+		if n.leaf() {
+			items := n.items()
+			for i := 0; i < int(n.count); i++ {
+				r := n.rects.at(i)
+				t, _, hit := clipLine(o, d, &r, 0, best)
+				if hit && (!ok || t < best) {
+					data, best, ok = items[i], t, true
+				}
+			}
+			continue
+		}
+		children := n.children()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if t, _, hit := clipLine(o, d, &r, 0, best); hit {
+				q.push(t, children[i])
+			}
+		}
+	}
+	if !ok {
+		return data, 0, false
+	}
+	return data, best * math.Hypot(d[0], d[1]), true
+}
+
+// RaycastNearest returns the first item that is hit by the ray starting at
+// origin and heading in the direction dir, along with the distance from the
+// origin to where the ray enters the item rectangle.
+func (tr *RTreeG[T]) RaycastNearest(origin, dir [2]float64,
+) (data T, dist float64, ok bool) {
+	return tr.base.RaycastNearest(origin, dir)
+}
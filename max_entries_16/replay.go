@@ -0,0 +1,125 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// errLogRecord is returned by Replay for a record that is too large.
+var errLogRecord = errors.New("rtree: invalid log record")
+
+// maxLogRecord is the maximum size of a record that Replay reads.
+const maxLogRecord = 1 << 30
+
+// WriteLogRecord writes a record to an operation log, such as an operation
+// that was encoded by the function passed to SetLogger. The record is
+// prefixed with its length, so that Replay can read it back.
+func WriteLogRecord(w io.Writer, rec []byte) error {
+	var hdr [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(hdr[:], uint64(len(rec)))
+	if _, err := w.Write(hdr[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(rec)
+	return err
+}
+
+// readRecord reads the next record that was written by WriteLogRecord into
+// buf. Returns io.EOF when there are no more records, and
+// io.ErrUnexpectedEOF when the record is incomplete.
+func readRecord(br *bufio.Reader, buf []byte) ([]byte, error) {
+	size, err := binary.ReadUvarint(br)
+	if err != nil {
+		return buf, err
+	}
+	if size > maxLogRecord {
+		return buf, errLogRecord
+	}
+	if uint64(cap(buf)) < size {
+		buf = make([]byte, size)
+	}
+	buf = buf[:size]
+	if _, err := io.ReadFull(br, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return buf, err
+	}
+	return buf, nil
+}
+
+// Replay applies the operations of a log that was written by
+// WriteLogRecord, in order, such as for restoring a tree from a write-ahead
+// log after a restart. Each record is decoded into an operation by the
+// decode function.
+//
+// Runs of inserts are applied together. A run that is at least as large as
+// the tree is loaded using LoadBulk, which is much faster than inserting the
+// items one at a time, such as for the start of a log of a tree that was
+// empty.
+//
+// Returns io.ErrUnexpectedEOF when the last record is incomplete, such as
+// after a crash while writing it, or the first error that is returned by
+// decode. The operations of the records before are applied in either case.
+func (tr *RTreeGN[N, T]) Replay(r io.Reader,
+	decode func(rec []byte) (op Op, min, max [2]N, data T, err error),
+) error {
+	br := bufio.NewReader(r)
+	var inserts []Item[N, T]
+	var buf []byte
+	var err error
+	for {
+		buf, err = readRecord(br, buf)
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			break
+		}
+		op, min, max, data, derr := decode(buf)
+		if derr != nil {
+			err = derr
+			break
+		}
+		if op == OpInsert {
+			inserts = append(inserts, Item[N, T]{min, max, data})
+			continue
+		}
+		tr.replayInserts(inserts)
+		inserts = inserts[:0]
+		switch op {
+		case OpDelete:
+			tr.Delete(min, max, data)
+		case OpClear:
+			tr.Clear()
+		}
+	}
+	tr.replayInserts(inserts)
+	return err
+}
+
+// replayInserts inserts the items, using LoadBulk when there are at least as
+// many items as there already are in the tree.
+func (tr *RTreeGN[N, T]) replayInserts(items []Item[N, T]) {
+	if len(items) > 0 && len(items) >= tr.count {
+		tr.LoadBulk(items)
+		return
+	}
+	for i := range items {
+		tr.Insert(items[i].Min, items[i].Max, items[i].Data)
+	}
+}
+
+// Replay applies the operations of a log that was written by
+// WriteLogRecord, in order. See RTreeGN.Replay.
+func (tr *RTreeG[T]) Replay(r io.Reader,
+	decode func(rec []byte) (op Op, min, max [2]float64, data T, err error),
+) error {
+	return tr.base.Replay(r, decode)
+}
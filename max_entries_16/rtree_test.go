@@ -7,9 +7,11 @@ package rtree
 import (
 	"errors"
 	"fmt"
+	"math/bits"
 	"math/rand"
 	"os"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -677,10 +679,11 @@ func rSaneNode[T comparable](tr *RTreeG[T], r rect[float64], n *node[float64, T]
 	}
 
 	for i := 0; i < int(n.count); i++ {
-		if err := rSaneRect(n.rects[i]); err != nil {
+		ir := n.rects.at(i)
+		if err := rSaneRect(ir); err != nil {
 			return err
 		}
-		if !r.contains(&n.rects[i]) {
+		if !r.contains(&ir) {
 			return errors.New("not contains item")
 		}
 		if !n.leaf() {
@@ -690,7 +693,7 @@ func rSaneNode[T comparable](tr *RTreeG[T], r rect[float64], n *node[float64, T]
 		}
 	}
 	var def T
-	for i := int(n.count); i < len(n.rects); i++ {
+	for i := int(n.count); i < maxEntries; i++ {
 		if n.leaf() {
 			if n.items()[i] != def {
 				return errors.New("not empty item")
@@ -703,22 +706,22 @@ func rSaneNode[T comparable](tr *RTreeG[T], r rect[float64], n *node[float64, T]
 	}
 	if !n.leaf() {
 		for i := 0; i < int(n.count); i++ {
-			err := rSaneNode(tr, n.rects[i], n.children()[i], height-1, false)
+			err := rSaneNode(tr, n.rects.at(i), n.children()[i], height-1, false)
 			if err != nil {
 				return err
 			}
 		}
-		if orderBranches {
+		if n.ordered() {
 			for i := 1; i < int(n.count); i++ {
-				if !(n.rects[i-1].min[0] < n.rects[i].min[0]) {
+				if !(n.rects.min[0][i-1] < n.rects.min[0][i]) {
 					return errors.New("branch rects are not in order")
 				}
 			}
 		}
 	} else {
-		if orderLeaves {
+		if n.ordered() {
 			for i := 1; i < int(n.count); i++ {
-				if !(n.rects[i-1].min[0] < n.rects[i].min[0]) {
+				if !(n.rects.min[0][i-1] < n.rects.min[0][i]) {
 					return errors.New("leaf rects are not in order")
 				}
 			}
@@ -739,11 +742,11 @@ func nodeSvg[T any](n *node[float64, T], r rect[float64], height int) []byte {
 		strokes[height%len(strokes)])...)
 	if !n.leaf() {
 		for i := 0; i < int(n.count); i++ {
-			out = append(out, nodeSvg(n.children()[i], n.rects[i], height+1)...)
+			out = append(out, nodeSvg(n.children()[i], n.rects.at(i), height+1)...)
 		}
 	} else {
 		for i := 0; i < int(n.count); i++ {
-			r := n.rects[i]
+			r := n.rects.at(i)
 			out = append(out, fmt.Sprintf(
 				"<rect x=\"%.0f\" y=\"%.0f\" width=\"%.0f\" height=\"%.0f\" "+
 					"stroke=\"%s\" fill-opacity=\"0\" stroke-opacity=\"1\"/>\n",
@@ -957,3 +960,387 @@ func TestNearby(t *testing.T) {
 	})
 
 }
+
+func TestNearbyReuse(t *testing.T) {
+	var tr RTreeG[int]
+	var q NNQueue[float64, int]
+	dist := BoxDist[float64, int]([2]float64{10, 10}, [2]float64{10, 10}, nil)
+	tr.NearbyReuse(&q, dist,
+		func(min, max [2]float64, data int, dist float64) bool {
+			t.Fatal("expected no items")
+			return false
+		},
+	)
+	for i := 0; i < 5000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	var expect, got []string
+	tr.Nearby(dist, func(min, max [2]float64, data int, dist float64) bool {
+		expect = append(expect, fmt.Sprint(data, dist))
+		return true
+	})
+	tr.NearbyReuse(&q, dist,
+		func(min, max [2]float64, data int, dist float64) bool {
+			got = append(got, fmt.Sprint(data, dist))
+			return true
+		},
+	)
+	if fmt.Sprint(got) != fmt.Sprint(expect) {
+		t.Fatal("mismatch")
+	}
+	if len(q.q) != 0 {
+		t.Fatalf("expected an empty queue, got %d", len(q.q))
+	}
+	var count int
+	iter := func(min, max [2]float64, data int, dist float64) bool {
+		count++
+		return count < 50
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		count = 0
+		tr.NearbyReuse(&q, dist, iter)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func TestNearestIter(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 1000; i++ {
+		r := randRect('p')
+		tr.Insert(r.min, r.max, i)
+	}
+	var all []float64
+	tr.Nearby(
+		BoxDist[float64, int]([2]float64{10, 10}, [2]float64{10, 10}, nil),
+		func(min, max [2]float64, data int, dist float64) bool {
+			all = append(all, dist)
+			return true
+		},
+	)
+	var dists []float64
+	for _, dist := range tr.NearestIter([2]float64{10, 10}) {
+		dists = append(dists, dist)
+	}
+	if fmt.Sprint(dists) != fmt.Sprint(all) {
+		t.Fatalf("expected %v, got %v", all, dists)
+	}
+	var count int
+	for range tr.NearestIter([2]float64{10, 10}) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+}
+
+func TestSearchShape(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			p := [2]float64{float64(i), float64(j)}
+			tr.Insert(p, p, i*10+j)
+		}
+	}
+	// only accept points below the diagonal of the bbox
+	var count int
+	tr.SearchShape([2]float64{0, 0}, [2]float64{9, 9},
+		func(min, max [2]float64, data int) bool {
+			return min[1] < min[0]
+		},
+		func(min, max [2]float64, data int) bool {
+			if !(min[1] < min[0]) {
+				t.Fatalf("unexpected item %v", min)
+			}
+			count++
+			return true
+		},
+	)
+	if count != 45 {
+		t.Fatalf("expected %d, got %d", 45, count)
+	}
+}
+
+func TestIntOverflow(t *testing.T) {
+	big := rect[int32]{[2]int32{-2e9, -2e9}, [2]int32{2e9, 2e9}}
+	if big.area() != 16e18 {
+		t.Fatalf("expected %v, got %v", 16e18, big.area())
+	}
+	small := rect[int32]{[2]int32{0, 0}, [2]int32{10, 10}}
+	if small.unionedArea(&big) != big.area() {
+		t.Fatalf("expected %v, got %v", big.area(), small.unionedArea(&big))
+	}
+	var tr RTreeGN[int32, int]
+	rects := make([]rect[int32], 10000)
+	for i := range rects {
+		x := int32(rand.Int63n(4e9) - 2e9)
+		y := int32(rand.Int63n(4e9) - 2e9)
+		w := int32(rand.Int63n(1e8))
+		h := int32(rand.Int63n(1e8))
+		rects[i] = rect[int32]{[2]int32{x, y},
+			[2]int32{x + w/2, y + h/2}}
+		if rects[i].max[0] < x || rects[i].max[1] < y {
+			rects[i].max = rects[i].min
+		}
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	if err := tr.SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range rects {
+		if !tr.Exists(rects[i].min, rects[i].max, i) {
+			t.Fatalf("item %d not found", i)
+		}
+	}
+}
+
+func TestSearchStackResume(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	target := rect[float64]{[2]float64{-20, -20}, [2]float64{20, 20}}
+	var expect []int
+	tr.Search(target.min, target.max,
+		func(min, max [2]float64, data int) bool {
+			expect = append(expect, data)
+			return true
+		},
+	)
+	// stop after every item and resume from the stack
+	var got []int
+	stack := []searchFrame[float64, int]{{n: tr.base.root}}
+	for {
+		var ok bool
+		stack, ok = searchStack(stack, target,
+			func(min, max [2]float64, data int) bool {
+				got = append(got, data)
+				return false
+			},
+		)
+		if ok {
+			break
+		}
+	}
+	if !slices.Equal(got, expect) {
+		t.Fatalf("expected %d items, got %d", len(expect), len(got))
+	}
+}
+
+func TestDeleteAllocs(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	var i int
+	allocs := testing.AllocsPerRun(1000, func() {
+		tr.Delete(rects[i].min, rects[i].max, i)
+		i++
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+func BenchmarkDelete(b *testing.B) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], b.N)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+}
+
+// benchScanTree returns a tree of random rects and windows for searching it.
+func benchScanTree() (*RTreeG[int], []rect[float64]) {
+	var tr RTreeG[int]
+	for i := 0; i < 200000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	windows := make([]rect[float64], 1024)
+	for i := range windows {
+		windows[i] = randRect('r')
+		windows[i].max[0] += 0.5
+		windows[i].max[1] += 0.5
+	}
+	return &tr, windows
+}
+
+// leafScanPlain counts the rects of the leaf that intersect the target, like
+// the loop in search does.
+func leafScanPlain[N numeric, T any](n *node[N, T], target *rect[N],
+) (count int) {
+	c := int(n.count)
+	minx, miny := n.rects.min[0][:c], n.rects.min[1][:c]
+	maxx, maxy := n.rects.max[0][:c], n.rects.max[1][:c]
+	for i := range minx {
+		if !(minx[i] > target.max[0] || maxx[i] < target.min[0] ||
+			miny[i] > target.max[1] || maxy[i] < target.min[1]) {
+			count++
+		}
+	}
+	return count
+}
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// leafScanPacked counts the rects of the leaf that intersect the target by
+// packing the tests of four rects per iteration into a bitmask.
+func leafScanPacked[N numeric, T any](n *node[N, T], target *rect[N],
+) (count int) {
+	c := int(n.count)
+	minx, miny := n.rects.min[0][:c], n.rects.min[1][:c]
+	maxx, maxy := n.rects.max[0][:c], n.rects.max[1][:c]
+	i := 0
+	for ; i+4 <= c; i += 4 {
+		var miss uint64
+		for k := 0; k < 4; k++ {
+			miss |= (b2u(minx[i+k] > target.max[0]) |
+				b2u(maxx[i+k] < target.min[0]) |
+				b2u(miny[i+k] > target.max[1]) |
+				b2u(maxy[i+k] < target.min[1])) << k
+		}
+		if miss != 0xF {
+			count += 4 - bits.OnesCount64(miss)
+		}
+	}
+	for ; i < c; i++ {
+		if !(minx[i] > target.max[0] || maxx[i] < target.min[0] ||
+			miny[i] > target.max[1] || maxy[i] < target.min[1]) {
+			count++
+		}
+	}
+	return count
+}
+
+// chooseByRect is chooseLeastEnlargement as it was before calculating four
+// rects per iteration.
+func chooseByRect[N numeric, T any](n *node[N, T], ir *rect[N]) int {
+	j := -1
+	var jenlargement, jarea float64
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		area := r.area()
+		enlargement := r.unionedArea(ir) - area
+		if j == -1 || enlargement < jenlargement ||
+			(!(enlargement > jenlargement) && area < jarea) {
+			j, jenlargement, jarea = i, enlargement, area
+		}
+	}
+	return j
+}
+
+func TestChooseLeastEnlargement(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	leaves := benchLeaves(&tr)
+	for i := 0; i < 10000; i++ {
+		n := leaves[i%len(leaves)]
+		r := randRect('r')
+		if i%2 == 0 {
+			// inside of the node, where many rects need no enlargement
+			r = n.rects.at(0)
+		}
+		if n.chooseLeastEnlargement(&r) != chooseByRect(n, &r) {
+			t.Fatal("mismatch")
+		}
+	}
+}
+
+// benchLeaves returns the leaves of the tree.
+func benchLeaves(tr *RTreeG[int]) (leaves []*node[float64, int]) {
+	var walk func(n *node[float64, int])
+	walk = func(n *node[float64, int]) {
+		if n.leaf() {
+			leaves = append(leaves, n)
+			return
+		}
+		for _, child := range n.children()[:n.count] {
+			walk(child)
+		}
+	}
+	walk(tr.base.root)
+	return leaves
+}
+
+// BenchmarkLeafScan compares the plain loop that search uses for testing the
+// rects of a node with a version that packs the tests of four rects per
+// iteration into a bitmask. The plain loop is faster, as its branches are
+// well predicted, so search keeps it.
+func BenchmarkLeafScan(b *testing.B) {
+	tr, windows := benchScanTree()
+	leaves := benchLeaves(tr)
+	for _, bench := range []struct {
+		name string
+		scan func(n *node[float64, int], target *rect[float64]) int
+	}{
+		{"plain", leafScanPlain[float64, int]},
+		{"packed", leafScanPacked[float64, int]},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			var count int
+			for i := 0; i < b.N; i++ {
+				count += bench.scan(leaves[i%len(leaves)],
+					&windows[i%len(windows)])
+			}
+		})
+	}
+}
+
+// BenchmarkChooseLeastEnlargement compares chooseLeastEnlargement, which
+// calculates four rects per iteration, with the loop over single rects that
+// it replaced.
+func BenchmarkChooseLeastEnlargement(b *testing.B) {
+	tr, windows := benchScanTree()
+	// the fullest branch above the leaves
+	var n *node[float64, int]
+	var walk func(b *node[float64, int])
+	walk = func(b *node[float64, int]) {
+		if b.children()[0].leaf() {
+			if n == nil || b.count > n.count {
+				n = b
+			}
+			return
+		}
+		for _, child := range b.children()[:b.count] {
+			walk(child)
+		}
+	}
+	walk(tr.base.root)
+	for _, bench := range []struct {
+		name   string
+		choose func(n *node[float64, int], ir *rect[float64]) int
+	}{
+		{"rects", chooseByRect[float64, int]},
+		{"unrolled", (*node[float64, int]).chooseLeastEnlargement},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			var index int
+			for i := 0; i < b.N; i++ {
+				index += bench.choose(n, &windows[i%len(windows)])
+			}
+		})
+	}
+}
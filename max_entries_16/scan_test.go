@@ -0,0 +1,141 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestModifiedDuringIteration(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 1000; i++ {
+		r := randRect('p')
+		tr.Insert(r.min, r.max, i)
+	}
+	expectPanic(t, func() {
+		tr.Scan(func(min, max [2]float64, data int) bool {
+			tr.Delete(min, max, data)
+			return true
+		})
+	})
+	expectPanic(t, func() {
+		tr.Search([2]float64{-180, -90}, [2]float64{180, 90},
+			func(min, max [2]float64, data int) bool {
+				tr.Insert(min, max, data)
+				return true
+			},
+		)
+	})
+	expectPanic(t, func() {
+		tr.Nearby(
+			BoxDist[float64, int]([2]float64{0, 0}, [2]float64{0, 0}, nil),
+			func(min, max [2]float64, data int, dist float64) bool {
+				tr.Insert(min, max, data)
+				return true
+			},
+		)
+	})
+	// modifying and then stopping is allowed
+	n := tr.Len()
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		tr.Delete(min, max, data)
+		return false
+	})
+	if tr.Len() != n-1 {
+		t.Fatalf("expected %d, got %d", n-1, tr.Len())
+	}
+}
+
+func TestScanDelete(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 10000; i++ {
+		r := randRect('m')
+		tr.Insert(r.min, r.max, i)
+	}
+	n := tr.ScanDelete(func(min, max [2]float64, data int) bool {
+		return data%3 == 0
+	})
+	if n != 3334 {
+		t.Fatalf("expected %d, got %d", 3334, n)
+	}
+	if tr.Len() != 10000-n {
+		t.Fatalf("expected %d, got %d", 10000-n, tr.Len())
+	}
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		if data%3 == 0 {
+			t.Fatalf("item %d not deleted", data)
+		}
+		return true
+	})
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanMut(t *testing.T) {
+	var tr RTreeG[int]
+	N := 10000
+	for i := 0; i < N; i++ {
+		r := randRect('m')
+		tr.Insert(r.min, r.max, i)
+	}
+	snap := tr.Copy()
+	// delete a few then stop
+	var seen int
+	tr.ScanMut(func(min, max [2]float64, data int) (bool, bool) {
+		seen++
+		return seen%2 == 0, seen < 100
+	})
+	if tr.Len() != N-50 {
+		t.Fatalf("expected %d, got %d", N-50, tr.Len())
+	}
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+	// delete most of the items
+	tr.ScanMut(func(min, max [2]float64, data int) (bool, bool) {
+		return data%100 != 0, true
+	})
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		if data%100 != 0 {
+			t.Fatalf("item %d not deleted", data)
+		}
+		return true
+	})
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+	bmin, bmax := tr.Bounds()
+	r := tr.base.root.rect()
+	if bmin != r.min || bmax != r.max {
+		t.Fatalf("expected bounds %v, got %v %v", r, bmin, bmax)
+	}
+	// delete all
+	tr.ScanMut(func(min, max [2]float64, data int) (bool, bool) {
+		return true, true
+	})
+	if tr.Len() != 0 || tr.base.root != nil {
+		t.Fatalf("expected empty tree, got %d", tr.Len())
+	}
+	// the snapshot is untouched
+	if snap.Len() != N {
+		t.Fatalf("expected %d, got %d", N, snap.Len())
+	}
+	var count int
+	snap.Scan(func(min, max [2]float64, data int) bool {
+		count++
+		return true
+	})
+	if count != N {
+		t.Fatalf("expected %d, got %d", N, count)
+	}
+	if err := rSane(snap); err != nil {
+		t.Fatal(err)
+	}
+	expectPanic(t, func() {
+		snap.ScanMut(func(min, max [2]float64, data int) (bool, bool) {
+			snap.Insert(min, max, data)
+			return false, true
+		})
+	})
+}
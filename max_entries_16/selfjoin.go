@@ -0,0 +1,109 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// SelfJoin finds all pairs of items in the tree whose rectangles intersect,
+// such as for the broad phase of collision detection.
+// Each pair is returned once, and an item is never paired with itself.
+// The tree is walked once, descending into two nodes at the same time only
+// where their rectangles overlap, which is much faster than performing a
+// Search for every item.
+func (tr *RTreeGN[N, T]) SelfJoin(
+	iter func(aMin, aMax [2]N, a T, bMin, bMax [2]N, b T) bool,
+) {
+	if tr.root == nil {
+		return
+	}
+	gen := tr.gen
+	tr.root.selfJoin(func(aMin, aMax [2]N, a T, bMin, bMax [2]N, b T) bool {
+		if !iter(aMin, aMax, a, bMin, bMax, b) {
+			return false
+		}
+		if tr.gen != gen {
+			panic(errModified)
+		}
+		return true
+	})
+}
+
+func (n *node[N, T]) selfJoin(
+	iter func(aMin, aMax [2]N, a T, bMin, bMax [2]N, b T) bool,
+) bool {
+	count := int(n.count)
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < count; i++ {
+			ri := n.rects.at(i)
+			for j := i + 1; j < count; j++ {
+				if rj := n.rects.at(j); ri.intersects(&rj) {
+					if !iter(ri.min, ri.max, items[i],
+						rj.min, rj.max, items[j]) {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < count; i++ {
+		if !children[i].selfJoin(iter) {
+			return false
+		}
+		ri := n.rects.at(i)
+		for j := i + 1; j < count; j++ {
+			if rj := n.rects.at(j); ri.intersects(&rj) {
+				if !joinNodes(children[i], children[j], iter) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// joinNodes finds all pairs of intersecting items between two different
+// nodes at the same height.
+func joinNodes[N numeric, T any](a, b *node[N, T],
+	iter func(aMin, aMax [2]N, a T, bMin, bMax [2]N, b T) bool,
+) bool {
+	if a.leaf() {
+		aitems := a.items()
+		bitems := b.items()
+		for i := 0; i < int(a.count); i++ {
+			ar := a.rects.at(i)
+			for j := 0; j < int(b.count); j++ {
+				if br := b.rects.at(j); ar.intersects(&br) {
+					if !iter(ar.min, ar.max, aitems[i],
+						br.min, br.max, bitems[j]) {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	}
+	achildren := a.children()
+	bchildren := b.children()
+	for i := 0; i < int(a.count); i++ {
+		ar := a.rects.at(i)
+		for j := 0; j < int(b.count); j++ {
+			if br := b.rects.at(j); ar.intersects(&br) {
+				if !joinNodes(achildren[i], bchildren[j], iter) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// SelfJoin finds all pairs of items in the tree whose rectangles intersect,
+// such as for the broad phase of collision detection.
+func (tr *RTreeG[T]) SelfJoin(
+	iter func(aMin, aMax [2]float64, a T, bMin, bMax [2]float64, b T) bool,
+) {
+	tr.base.SelfJoin(iter)
+}
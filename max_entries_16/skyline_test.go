@@ -0,0 +1,61 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSkyline(t *testing.T) {
+	var tr RTreeG[int]
+	tr.Skyline(func(min, max [2]float64, data int) bool {
+		t.Fatal("expected no items")
+		return true
+	})
+	// use whole numbers to get plenty of ties
+	pts := make([][2]float64, 5000)
+	for i := range pts {
+		pts[i] = [2]float64{float64(rand.Intn(1000)), float64(rand.Intn(1000))}
+		tr.Insert(pts[i], pts[i], i)
+	}
+	found := make(map[int]bool)
+	var last float64
+	tr.Skyline(func(min, max [2]float64, data int) bool {
+		if min[0]+min[1] < last {
+			t.Fatal("out of order")
+		}
+		last = min[0] + min[1]
+		found[data] = true
+		return true
+	})
+	var expect int
+	for i := range pts {
+		var dom bool
+		for j := range pts {
+			if dominates(pts[j], pts[i]) {
+				dom = true
+				break
+			}
+		}
+		if !dom {
+			expect++
+			if !found[i] {
+				t.Fatalf("item %d not found", i)
+			}
+		}
+	}
+	if len(found) != expect {
+		t.Fatalf("expected %d, got %d", expect, len(found))
+	}
+	var count int
+	tr.Skyline(func(min, max [2]float64, data int) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Fatalf("expected 1, got %d", count)
+	}
+}
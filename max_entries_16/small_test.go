@@ -0,0 +1,108 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRTreeSmall(t *testing.T) {
+	for _, threshold := range []int{defaultSmallThreshold, 10} {
+		small := NewRTreeSmall[float64, int](threshold)
+		if threshold == defaultSmallThreshold {
+			small = new(RTreeSmall[float64, int])
+		}
+		var expect RTreeG[int]
+		check := func() {
+			t.Helper()
+			if small.Len() != expect.Len() {
+				t.Fatalf("expected %d, got %d", expect.Len(), small.Len())
+			}
+			min1, max1 := small.Bounds()
+			min2, max2 := expect.Bounds()
+			if min1 != min2 || max1 != max2 {
+				t.Fatal("bounds mismatch")
+			}
+			for i := 0; i < 50; i++ {
+				q := randRect('r')
+				var ids1, ids2 []int
+				small.Search(q.min, q.max, func(min, max [2]float64,
+					data int) bool {
+					ids1 = append(ids1, data)
+					return true
+				})
+				expect.Search(q.min, q.max, func(min, max [2]float64,
+					data int) bool {
+					ids2 = append(ids2, data)
+					return true
+				})
+				slices.Sort(ids1)
+				slices.Sort(ids2)
+				if !slices.Equal(ids1, ids2) {
+					t.Fatalf("expected %v, got %v", ids2, ids1)
+				}
+			}
+			p := [2]float64{10, 10}
+			var dists1, dists2 []float64
+			small.Nearby(BoxDist[float64, int](p, p, nil),
+				func(min, max [2]float64, data int, dist float64) bool {
+					dists1 = append(dists1, dist)
+					return true
+				})
+			expect.Nearby(BoxDist[float64, int](p, p, nil),
+				func(min, max [2]float64, data int, dist float64) bool {
+					dists2 = append(dists2, dist)
+					return true
+				})
+			if !slices.Equal(dists1, dists2) {
+				t.Fatalf("expected %v, got %v", dists2, dists1)
+			}
+		}
+		check()
+		rects := make([]rect[float64], 300)
+		for i := range rects {
+			rects[i] = randRect('r')
+			small.Insert(rects[i].min, rects[i].max, i)
+			expect.Insert(rects[i].min, rects[i].max, i)
+			if (small.tr == nil) != (i < threshold) {
+				t.Fatalf("unexpected tree with %d items", i+1)
+			}
+			if i == 5 || i == threshold-1 || i == threshold {
+				check()
+			}
+		}
+		if small.tr == nil || small.items != nil {
+			t.Fatal("expected a tree")
+		}
+		check()
+		for i := 0; i < len(rects); i += 2 {
+			small.Delete(rects[i].min, rects[i].max, i)
+			expect.Delete(rects[i].min, rects[i].max, i)
+		}
+		check()
+		small.Clear()
+		expect = RTreeG[int]{}
+		for i := 0; i < 8; i++ {
+			small.Insert(rects[i].min, rects[i].max, i)
+			expect.Insert(rects[i].min, rects[i].max, i)
+		}
+		for i := 0; i < 8; i += 2 {
+			r := randRect('r')
+			small.Replace(rects[i].min, rects[i].max, i, r.min, r.max, i)
+			expect.Replace(rects[i].min, rects[i].max, i, r.min, r.max, i)
+		}
+		small.Delete(rects[1].min, rects[1].max, -1)
+		check()
+		var count int
+		small.Scan(func(min, max [2]float64, data int) bool {
+			count++
+			return true
+		})
+		if count != 8 {
+			t.Fatalf("expected 8, got %d", count)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSpatioTemporalTree(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	randItem := func(i int) TimedItem[int] {
+		r := randRect('r')
+		from := start.Add(time.Duration(rand.Intn(24*60)) * time.Minute)
+		to := from.Add(time.Duration(rand.Intn(60)) * time.Minute)
+		return TimedItem[int]{r.min, r.max, from, to, i}
+	}
+	for _, bulk := range []bool{false, true} {
+		var tr SpatioTemporalTree[int]
+		if _, _, ok := tr.TimeBounds(); ok {
+			t.Fatal("expected no time bounds")
+		}
+		tr.SearchWindow([2]float64{-180, -90}, [2]float64{180, 90}, start,
+			start, func(min, max [2]float64, from, to time.Time,
+				data int) bool {
+				t.Fatal("expected no items")
+				return false
+			})
+		items := make([]TimedItem[int], 5000)
+		for i := range items {
+			items[i] = randItem(i)
+		}
+		if bulk {
+			tr.LoadBulk(items[:2000])
+			tr.LoadBulk(items[2000:])
+		} else {
+			for _, item := range items {
+				tr.Insert(item.Min, item.Max, item.To, item.From, item.Data)
+			}
+		}
+		if err := tr.base.SanityCheck(); err != nil {
+			t.Fatal(err)
+		}
+		check := func() {
+			t.Helper()
+			if tr.Len() != len(items) {
+				t.Fatalf("expected %d, got %d", len(items), tr.Len())
+			}
+			for i := 0; i < 100; i++ {
+				q := randRect('r')
+				from := start.Add(time.Duration(rand.Intn(24*60)) * time.Minute)
+				to := from.Add(time.Duration(rand.Intn(120)) * time.Minute)
+				var got, expect []int
+				tr.SearchWindow(q.min, q.max, from, to,
+					func(min, max [2]float64, from2, to2 time.Time,
+						data int) bool {
+						if to2.Before(from) || from2.After(to) {
+							t.Fatalf("item %d is outside of the window", data)
+						}
+						got = append(got, data)
+						return true
+					})
+				for _, item := range items {
+					ir := rect[float64]{item.Min, item.Max}
+					if ir.intersects(&q) && !item.To.Before(from) &&
+						!item.From.After(to) {
+						expect = append(expect, item.Data)
+					}
+				}
+				slices.Sort(got)
+				slices.Sort(expect)
+				if !slices.Equal(got, expect) {
+					t.Fatalf("expected %d items, got %d", len(expect),
+						len(got))
+				}
+			}
+		}
+		check()
+		efrom, eto := items[0].From, items[0].To
+		for _, item := range items {
+			if item.From.Before(efrom) {
+				efrom = item.From
+			}
+			if item.To.After(eto) {
+				eto = item.To
+			}
+		}
+		if from, to, ok := tr.TimeBounds(); !ok || !from.Equal(efrom) ||
+			!to.Equal(eto) {
+			t.Fatalf("expected %v %v, got %v %v", efrom, eto, from, to)
+		}
+		for i := 0; i < len(items); i += 2 {
+			item := items[i]
+			tr.Delete(item.Min, item.Max, item.From, item.To, item.Data)
+		}
+		for i := 0; i < len(items)/2; i++ {
+			items[i] = items[i*2+1]
+		}
+		items = items[:len(items)/2]
+		check()
+	}
+}
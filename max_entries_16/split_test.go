@@ -0,0 +1,112 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+// halfSplitter moves the second half of the entries to the new node.
+type halfSplitter struct {
+	leaves, branches int
+}
+
+func (s *halfSplitter) Split(leaf bool, min, max [2]float64,
+	entries []Item[float64, int], right []bool,
+) {
+	if leaf {
+		s.leaves++
+	} else {
+		s.branches++
+	}
+	for i := range entries {
+		right[i] = i >= len(entries)/2
+	}
+}
+
+type badSplitter struct{}
+
+func (badSplitter) Split(leaf bool, min, max [2]float64,
+	entries []Item[float64, int], right []bool,
+) {
+	right[0] = true
+}
+
+func testSplitter(t *testing.T, s Splitter[float64, int]) {
+	t.Helper()
+	var tr, expect RTreeG[int]
+	tr.SetSplitter(s)
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+		expect.Insert(rects[i].min, rects[i].max, i)
+	}
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+	check := func() {
+		t.Helper()
+		for j := 0; j < 50; j++ {
+			r := randRect('r')
+			r.max[0] += 10
+			r.max[1] += 10
+			if !slices.Equal(bulkSearch(&tr, r), bulkSearch(&expect, r)) {
+				t.Fatal("mismatch")
+			}
+		}
+	}
+	check()
+	for i := 0; i < len(rects); i += 2 {
+		tr.Delete(rects[i].min, rects[i].max, i)
+		expect.Delete(rects[i].min, rects[i].max, i)
+	}
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+	check()
+}
+
+func TestSetSplitter(t *testing.T) {
+	var s halfSplitter
+	testSplitter(t, &s)
+	if s.leaves == 0 || s.branches == 0 {
+		t.Fatal("expected the splitter to be used for leaves and branches")
+	}
+	testSplitter(t, EdgeSnapSplitter[float64, int]())
+	var tr RTreeG[int]
+	tr.SetSplitter(badSplitter{})
+	expectPanic(t, func() {
+		for i := 0; i <= maxEntries; i++ {
+			r := randRect('r')
+			tr.Insert(r.min, r.max, i)
+		}
+	})
+}
+
+func TestEdgeSnapSplitter(t *testing.T) {
+	s := EdgeSnapSplitter[float64, int]()
+	// all entries are closer to the left edge
+	entries := make([]Item[float64, int], 6)
+	for i := range entries {
+		entries[i].Min = [2]float64{float64(i), 0}
+		entries[i].Max = [2]float64{float64(i), 0}
+	}
+	right := make([]bool, len(entries))
+	s.Split(true, [2]float64{0, 0}, [2]float64{100, 1}, entries, right)
+	if !slices.Equal(right, []bool{true, true, false, false, false, false}) {
+		t.Fatalf("unexpected split %v", right)
+	}
+	// all entries are closer to the right edge
+	for i := range entries {
+		entries[i].Min = [2]float64{0, float64(90 + i)}
+		entries[i].Max = [2]float64{1, float64(90 + i)}
+	}
+	s.Split(true, [2]float64{0, 0}, [2]float64{1, 100}, entries, right)
+	if !slices.Equal(right, []bool{false, false, true, true, true, true}) {
+		t.Fatalf("unexpected split %v", right)
+	}
+}
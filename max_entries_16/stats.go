@@ -0,0 +1,61 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Stats describes the structure of a tree.
+type Stats struct {
+	Height     int     // number of levels, zero for an empty tree
+	Items      int     // number of items
+	Leaves     int     // number of leaf nodes
+	Branches   int     // number of branch nodes
+	MaxEntries int     // maximum number of entries per node
+	LeafFill   float64 // average fill of the leaf nodes, from 0 to 1
+	BranchFill float64 // average fill of the branch nodes, from 0 to 1
+}
+
+// Stats returns statistics about the structure of the tree, which are useful
+// for measuring the quality of the tree, such as how well nodes are filled.
+// This visits every node in the tree.
+func (tr *RTreeGN[N, T]) Stats() Stats {
+	s := Stats{Items: tr.count, MaxEntries: maxEntries}
+	if tr.root == nil {
+		return s
+	}
+	var leafEntries, branchEntries int
+	tr.root.stats(&s, 1, &leafEntries, &branchEntries)
+	if s.Leaves > 0 {
+		s.LeafFill = float64(leafEntries) /
+			float64(s.Leaves*tr.fanout(true))
+	}
+	if s.Branches > 0 {
+		s.BranchFill = float64(branchEntries) /
+			float64(s.Branches*tr.fanout(false))
+	}
+	return s
+}
+
+func (n *node[N, T]) stats(s *Stats, depth int, leafEntries,
+	branchEntries *int,
+) {
+	if depth > s.Height {
+		s.Height = depth
+	}
+	if n.leaf() {
+		s.Leaves++
+		*leafEntries += int(n.count)
+		return
+	}
+	s.Branches++
+	*branchEntries += int(n.count)
+	children := n.children()[:n.count]
+	for i := range children {
+		children[i].stats(s, depth+1, leafEntries, branchEntries)
+	}
+}
+
+// Stats returns statistics about the structure of the tree.
+func (tr *RTreeG[T]) Stats() Stats {
+	return tr.base.Stats()
+}
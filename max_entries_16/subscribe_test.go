@@ -0,0 +1,62 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestSubscribe(t *testing.T) {
+	var tr RTreeG[int]
+	events, cancel := tr.Subscribe([2]float64{0, 0}, [2]float64{10, 10})
+	all, cancelAll := tr.Subscribe([2]float64{-180, -90},
+		[2]float64{180, 90})
+	defer cancelAll()
+	in := [2]float64{5, 5}
+	out := [2]float64{50, 50}
+	tr.Insert(in, in, 1)
+	tr.Insert(out, out, 2)
+	tr.Replace(in, in, 1, out, out, 1)
+	tr.Delete(out, out, 2)
+	tr.Delete(out, out, 3) // not found
+	tr.Clear()
+	expect := []ChangeEvent[float64, int]{
+		{Op: OpInsert, Min: in, Max: in, Data: 1},
+		{Op: OpDelete, Min: in, Max: in, Data: 1},
+		{Op: OpClear},
+	}
+	for _, e := range expect {
+		if got := <-events; got != e {
+			t.Fatalf("expected %v, got %v", e, got)
+		}
+	}
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event %v", e)
+	default:
+	}
+	if len(all) != 6 {
+		t.Fatalf("expected %d, got %d", 6, len(all))
+	}
+	cancel()
+	cancel()
+	if _, ok := <-events; ok {
+		t.Fatal("expected a closed channel")
+	}
+	tr.Insert(in, in, 1)
+
+	// events are dropped while the channel is full
+	for len(all) > 0 {
+		<-all
+	}
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		tr.Insert(in, in, i)
+	}
+	for i := 0; i < subscriptionBuffer; i++ {
+		<-all
+	}
+	tr.Insert(in, in, -1)
+	if e := <-all; e.Data != -1 || e.Missed != 10 {
+		t.Fatalf("expected %d missed, got %d", 10, e.Missed)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"time"
+)
+
+// TimedPoint is a point of a trajectory, such as a GPS fix.
+type TimedPoint struct {
+	Point [2]float64
+	Time  time.Time
+}
+
+// TrajectorySegment is a part of a trajectory, with the bounding rect of its
+// points and the time of its first and last point.
+type TrajectorySegment struct {
+	Min, Max [2]float64
+	From, To time.Time
+}
+
+// InsertTrajectory splits a trajectory into segments and inserts each
+// segment as an item, where track is the data of the items.
+// A segment holds consecutive points whose times are at most maxSegmentSpan
+// apart, and it shares its last point with the next segment, so that the
+// whole path is covered. Two points that are further apart in time than
+// maxSegmentSpan still form a segment.
+//
+// Shorter segments have smaller rects and time ranges, which makes searches
+// more selective, but the tree has more items.
+//
+// The points are sorted by time when they are not already.
+func (tr *SpatioTemporalTree[T]) InsertTrajectory(points []TimedPoint,
+	maxSegmentSpan time.Duration, track T,
+) {
+	byTime := func(a, b TimedPoint) int {
+		return a.Time.Compare(b.Time)
+	}
+	if !slices.IsSortedFunc(points, byTime) {
+		points = slices.Clone(points)
+		slices.SortStableFunc(points, byTime)
+	}
+	for i := 0; i < len(points); {
+		seg := TrajectorySegment{points[i].Point, points[i].Point,
+			points[i].Time, points[i].Time}
+		j := i + 1
+		for ; j < len(points); j++ {
+			if j > i+1 && points[j].Time.Sub(points[i].Time) > maxSegmentSpan {
+				break
+			}
+			p := points[j].Point
+			seg.Min = [2]float64{min(seg.Min[0], p[0]), min(seg.Min[1], p[1])}
+			seg.Max = [2]float64{max(seg.Max[0], p[0]), max(seg.Max[1], p[1])}
+			seg.To = points[j].Time
+		}
+		tr.Insert(seg.Min, seg.Max, seg.From, seg.To, track)
+		if j == len(points) {
+			break
+		}
+		i = j - 1
+	}
+}
+
+// SearchTrajectory searches for the segments of trajectories that intersect
+// the provided rectangle and overlap the time window, like SearchWindow, and
+// calls iter once for each track with its matching segments, in order of
+// time. The tracks must be comparable.
+func (tr *SpatioTemporalTree[T]) SearchTrajectory(min, max [2]float64,
+	from, to time.Time,
+	iter func(track T, segments []TrajectorySegment) bool,
+) {
+	type group struct {
+		track    T
+		segments []TrajectorySegment
+	}
+	var groups []group
+	index := make(map[any]int)
+	tr.SearchWindow(min, max, from, to,
+		func(min, max [2]float64, from, to time.Time, track T) bool {
+			i, ok := index[track]
+			if !ok {
+				i = len(groups)
+				index[track] = i
+				groups = append(groups, group{track: track})
+			}
+			groups[i].segments = append(groups[i].segments,
+				TrajectorySegment{min, max, from, to})
+			return true
+		})
+	for _, g := range groups {
+		slices.SortFunc(g.segments, func(a, b TrajectorySegment) int {
+			return a.From.Compare(b.From)
+		})
+		if !iter(g.track, g.segments) {
+			return
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStrict(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	invalid := [][2][2]float64{
+		{{nan, 0}, {1, 1}},
+		{{0, 0}, {1, nan}},
+		{{0, -inf}, {1, 1}},
+		{{2, 0}, {1, 1}},
+		{{0, 2}, {1, 1}},
+	}
+	var tr RTreeG[int]
+	for _, r := range invalid {
+		if err := tr.TryInsert(r[0], r[1], 1); err != ErrInvalidRect {
+			t.Fatalf("expected %v, got %v", ErrInvalidRect, err)
+		}
+	}
+	if err := tr.TryInsert([2]float64{1, 1}, [2]float64{1, 1}, 1); err != nil {
+		t.Fatal(err)
+	}
+	// not strict by default
+	tr.Insert(invalid[0][0], invalid[0][1], 2)
+	if tr.Len() != 2 {
+		t.Fatalf("expected %d, got %d", 2, tr.Len())
+	}
+	tr.SetStrict(true)
+	if !tr.Strict() {
+		t.Fatal("expected strict")
+	}
+	for _, r := range invalid {
+		expectPanic(t, func() { tr.Insert(r[0], r[1], 3) })
+		expectPanic(t, func() {
+			tr.LoadBulk([]Item[float64, int]{{r[0], r[1], 3}})
+		})
+	}
+	if tr.Len() != 2 {
+		t.Fatalf("expected %d, got %d", 2, tr.Len())
+	}
+
+	var tr2 RTreeGN[uint8, int]
+	if err := tr2.TryInsert([2]uint8{0, 0}, [2]uint8{255, 255}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr2.TryInsert([2]uint8{1, 0}, [2]uint8{0, 0}, 1); err == nil {
+		t.Fatal("expected error")
+	}
+}
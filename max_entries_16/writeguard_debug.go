@@ -0,0 +1,39 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build rtreedebug
+
+package rtree
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var errConcurrentWrite = errors.New("rtree: concurrent write detected; " +
+	"a tree and its copies may be used from different goroutines, but each " +
+	"tree must only be written by one goroutine at a time, and Copy counts " +
+	"as a write to the source tree")
+
+// writeGuardEnabled is true with the rtreedebug tag.
+const writeGuardEnabled = true
+
+// writeGuard detects concurrent writes to a tree by marking the tree as busy
+// for the duration of every write, and panicking when it's already busy.
+// A write that overlaps with another write to the same tree, including a Copy
+// of the tree, would otherwise silently corrupt the nodes that are shared
+// between the tree and its copies.
+type writeGuard struct {
+	busy int32
+}
+
+func (g *writeGuard) enter() {
+	if !atomic.CompareAndSwapInt32(&g.busy, 0, 1) {
+		panic(errConcurrentWrite)
+	}
+}
+
+func (g *writeGuard) exit() {
+	atomic.StoreInt32(&g.busy, 0)
+}
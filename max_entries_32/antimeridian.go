@@ -0,0 +1,187 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// AntimeridianRTreeG is an R-tree for geographic data where the X axis is
+// cyclic over [-180, 180].
+// A rectangle whose min X is greater than its max X is one that spans the
+// antimeridian, such as [170 -10] [-170 10], which may be used for both
+// inserting items and searching.
+type AntimeridianRTreeG[T any] struct {
+	count int
+	ids   uint64 // last id of a crossing item
+	base  RTreeGN[float64, amItem[T]]
+}
+
+const (
+	amWhole = iota // the item does not cross the antimeridian
+	amEast         // east part of a crossing item, [min 180]
+	amWest         // west part of a crossing item, [-180 max]
+)
+
+type amItem[T any] struct {
+	data  T
+	part  int8
+	other float64 // X of the other part, for crossing items
+	id    uint64  // same for both parts of a crossing item
+}
+
+// amSplit splits a rectangle that crosses the antimeridian into the east and
+// west parts.
+func amSplit(min, max [2]float64) (rects [2]rect[float64], n int) {
+	if min[0] > max[0] {
+		rects[0] = rect[float64]{min, [2]float64{180, max[1]}}
+		rects[1] = rect[float64]{[2]float64{-180, min[1]}, max}
+		return rects, 2
+	}
+	rects[0] = rect[float64]{min, max}
+	return rects, 1
+}
+
+// Insert data into tree.
+func (tr *AntimeridianRTreeG[T]) Insert(min, max [2]float64, data T) {
+	rects, n := amSplit(min, max)
+	if n == 1 {
+		tr.base.Insert(min, max, amItem[T]{data: data})
+	} else {
+		tr.ids++
+		tr.base.Insert(rects[0].min, rects[0].max,
+			amItem[T]{data: data, part: amEast, other: max[0], id: tr.ids})
+		tr.base.Insert(rects[1].min, rects[1].max,
+			amItem[T]{data: data, part: amWest, other: min[0], id: tr.ids})
+	}
+	tr.count++
+}
+
+// Delete data from tree.
+func (tr *AntimeridianRTreeG[T]) Delete(min, max [2]float64, data T) {
+	rects, n := amSplit(min, max)
+	if n == 1 {
+		if tr.base.delete(min, max, amItem[T]{data: data}, 0) {
+			tr.count--
+		}
+	} else {
+		east, ok := tr.findEast(&rects[0], max[0], data)
+		if ok && tr.base.delete(rects[0].min, rects[0].max, east, 0) {
+			west := east
+			west.part, west.other = amWest, min[0]
+			tr.base.delete(rects[1].min, rects[1].max, west, 0)
+			tr.count--
+		}
+	}
+}
+
+// findEast returns the east part of the crossing item with the data, whose
+// east part has the rect r and whose west part ends at other.
+func (tr *AntimeridianRTreeG[T]) findEast(r *rect[float64], other float64,
+	data T,
+) (east amItem[T], ok bool) {
+	tr.base.Search(r.min, r.max,
+		func(min, max [2]float64, item amItem[T]) bool {
+			if item.part == amEast && item.other == other &&
+				min == r.min && max == r.max && compare(item.data, data) {
+				east, ok = item, true
+				return false
+			}
+			return true
+		},
+	)
+	return east, ok
+}
+
+// Len returns the number of items in tree.
+func (tr *AntimeridianRTreeG[T]) Len() int {
+	return tr.count
+}
+
+// Bounds returns the minimum bounding rect.
+func (tr *AntimeridianRTreeG[T]) Bounds() (min, max [2]float64) {
+	return tr.base.Bounds()
+}
+
+// original returns the rectangle that the item was inserted with.
+func (item *amItem[T]) original(min, max [2]float64) ([2]float64, [2]float64) {
+	switch item.part {
+	case amEast:
+		max[0] = item.other
+	case amWest:
+		min[0] = item.other
+	}
+	return min, max
+}
+
+// hits returns true if the item intersects the X axis of the target rect.
+// When eastOnly is true, only the east part of a crossing item is checked.
+func (item *amItem[T]) hits(min, max [2]float64, target *rect[float64],
+	eastOnly bool,
+) bool {
+	xhit := func(a, b float64) bool {
+		return !(a > target.max[0] || b < target.min[0])
+	}
+	if item.part == amWhole {
+		return xhit(min[0], max[0])
+	}
+	min, max = item.original(min, max)
+	return xhit(min[0], 180) || (!eastOnly && xhit(-180, max[0]))
+}
+
+// Search for items in tree that intersect the provided rectangle.
+// When the rectangle spans the antimeridian both sides are searched.
+// Each item is returned only once using the rectangle that it was inserted
+// with, even when it also spans the antimeridian.
+func (tr *AntimeridianRTreeG[T]) Search(min, max [2]float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	targets, n := amSplit(min, max)
+	for i := 0; i < n; i++ {
+		ok := true
+		tr.base.Search(targets[i].min, targets[i].max,
+			func(min, max [2]float64, item amItem[T]) bool {
+				if i > 0 && item.hits(min, max, &targets[0], false) {
+					// already returned while searching the first target
+					return true
+				}
+				if item.part == amWest &&
+					item.hits(min, max, &targets[i], true) {
+					// already returned by the east part
+					return true
+				}
+				min, max = item.original(min, max)
+				ok = iter(min, max, item.data)
+				return ok
+			},
+		)
+		if !ok {
+			return
+		}
+	}
+}
+
+// Scan all items in the tree.
+func (tr *AntimeridianRTreeG[T]) Scan(
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.Scan(func(min, max [2]float64, item amItem[T]) bool {
+		if item.part == amWest {
+			return true
+		}
+		min, max = item.original(min, max)
+		return iter(min, max, item.data)
+	})
+}
+
+// Copy the tree.
+// This is a copy-on-write operation and is very fast because it only performs
+// a shadowed copy.
+func (tr *AntimeridianRTreeG[T]) Copy() *AntimeridianRTreeG[T] {
+	return &AntimeridianRTreeG[T]{count: tr.count, ids: tr.ids,
+		base: *tr.base.Copy()}
+}
+
+// Clear will delete all items.
+func (tr *AntimeridianRTreeG[T]) Clear() {
+	tr.count = 0
+	tr.base.Clear()
+}
@@ -0,0 +1,329 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sync"
+)
+
+// bulkItem is an item that is being bulk loaded
+type bulkItem[N numeric, T any] struct {
+	rect rect[N]
+	data T
+	seq  uint64
+}
+
+// LoadBulk loads the items into the tree.
+// This is much faster than inserting each item one at a time and the
+// resulting tree is usually better for searching, because the items are
+// packed into nodes using the Sort-Tile-Recursive (STR) algorithm.
+// Any items that are already in the tree are kept and packed along with the
+// new items.
+func (tr *RTreeGN[N, T]) LoadBulk(items []Item[N, T]) {
+	tr.LoadBulkParallel(items, 1)
+}
+
+// LoadBulkParallel is like LoadBulk, but uses up to the provided number of
+// goroutines for sorting the items and building the nodes, such as
+// runtime.NumCPU().
+func (tr *RTreeGN[N, T]) LoadBulkParallel(items []Item[N, T], workers int) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.strict {
+		for i := range items {
+			if !validRect(items[i].Min, items[i].Max) {
+				panic(ErrInvalidRect)
+			}
+		}
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if writeGuardEnabled {
+		tr.writes.enter()
+		defer tr.writes.exit()
+	}
+	bitems := make([]bulkItem[N, T], 0, tr.count+len(items))
+	if tr.root != nil {
+		bitems = tr.root.appendBulkItems(bitems)
+		tr.release(tr.root)
+	}
+	for i := range items {
+		var seq uint64
+		if tr.ordered {
+			tr.seq++
+			seq = tr.seq
+		}
+		bitems = append(bitems, bulkItem[N, T]{
+			rect: rect[N]{items[i].Min, items[i].Max},
+			data: items[i].Data,
+			seq:  seq,
+		})
+		if tr.logging() {
+			tr.log(OpInsert, items[i].Min, items[i].Max, items[i].Data)
+		}
+	}
+	tr.gen++
+	tr.count = len(bitems)
+	if len(bitems) == 0 {
+		tr.root = nil
+		tr.rect = rect[N]{}
+		return
+	}
+	tr.initPool()
+	tr.root = tr.buildBulk(bitems, workers, 0)
+	tr.rect = tr.root.rect()
+	tr.fixAggs()
+}
+
+func (n *node[N, T]) appendBulkItems(bitems []bulkItem[N, T],
+) []bulkItem[N, T] {
+	if n.leaf() {
+		items := n.items()
+		seqs := n.seqs()
+		for i := 0; i < int(n.count); i++ {
+			bi := bulkItem[N, T]{rect: n.rects.at(i), data: items[i]}
+			if seqs != nil {
+				bi.seq = seqs[i]
+			}
+			bitems = append(bitems, bi)
+		}
+		return bitems
+	}
+	children := n.children()[:n.count]
+	for i := range children {
+		bitems = children[i].appendBulkItems(bitems)
+	}
+	return bitems
+}
+
+// center returns the center of a rect along an axis, used for sorting.
+func (r *rect[N]) center(axis int) float64 {
+	return float64(r.min[axis])/2 + float64(r.max[axis])/2
+}
+
+// strSlabs orders the elements using the Sort-Tile-Recursive algorithm so
+// that each consecutive run of fanout elements make up a node, and returns
+// the vertical slabs, which can be built independently.
+// When stable is true, elements with the same center keep their order, so
+// that the result does not depend on the number of workers.
+func strSlabs[N numeric, E any](es []E, rectOf func(e E) rect[N],
+	fanout, workers int, stable bool,
+) [][]E {
+	nnodes := (len(es) + fanout - 1) / fanout
+	nslabs := int(math.Ceil(math.Sqrt(float64(nnodes))))
+	slabSize := ((nnodes + nslabs - 1) / nslabs) * fanout
+	psort(es, func(a, b E) int {
+		ra, rb := rectOf(a), rectOf(b)
+		return cmp.Compare(ra.center(0), rb.center(0))
+	}, workers, stable)
+	var slabs [][]E
+	for i := 0; i < len(es); i += slabSize {
+		end := i + slabSize
+		if end > len(es) {
+			end = len(es)
+		}
+		slabs = append(slabs, es[i:end])
+	}
+	parallel(len(slabs), workers, func(i int) {
+		slab := slabs[i]
+		cmpY := func(a, b E) int {
+			ra, rb := rectOf(a), rectOf(b)
+			return cmp.Compare(ra.center(1), rb.center(1))
+		}
+		if stable {
+			slices.SortStableFunc(slab, cmpY)
+		} else {
+			slices.SortFunc(slab, cmpY)
+		}
+	})
+	return slabs
+}
+
+// buildBulk builds the tree from the items and returns the root.
+// When height is greater than zero the root is padded with single child
+// branches until the tree has at least that many levels.
+func (tr *RTreeGN[N, T]) buildBulk(bitems []bulkItem[N, T], workers int,
+	height int,
+) *node[N, T] {
+	fanout := tr.fanout(true)
+	slabs := strSlabs(bitems, func(bi bulkItem[N, T]) rect[N] {
+		return bi.rect
+	}, fanout, workers, tr.deterministic)
+	nodes := make([][]*node[N, T], len(slabs))
+	parallel(len(slabs), workers, func(i int) {
+		slab := slabs[i]
+		for j := 0; j < len(slab); j += fanout {
+			n := tr.newNode(true)
+			items := n.items()
+			var seqs []uint64
+			for k := j; k < len(slab) && k < j+fanout; k++ {
+				n.rects.set(int(n.count), slab[k].rect)
+				items[n.count] = slab[k].data
+				if slab[k].seq != 0 {
+					if seqs == nil {
+						seqs = n.allocSeqs()
+					}
+					seqs[n.count] = slab[k].seq
+				}
+				n.count++
+			}
+			if n.ordered() && !n.issorted() {
+				n.sort()
+			}
+			nodes[i] = append(nodes[i], n)
+		}
+	})
+	var level []*node[N, T]
+	for i := range nodes {
+		level = append(level, nodes[i]...)
+	}
+	for levels := 1; len(level) > 1 || levels < height; levels++ {
+		level = tr.buildBulkLevel(level, workers)
+	}
+	return level[0]
+}
+
+// buildBulkLevel packs the nodes into a new level of branches.
+func (tr *RTreeGN[N, T]) buildBulkLevel(level []*node[N, T], workers int,
+) []*node[N, T] {
+	type entry struct {
+		rect rect[N]
+		node *node[N, T]
+	}
+	entries := make([]entry, len(level))
+	for i := range level {
+		entries[i] = entry{level[i].rect(), level[i]}
+	}
+	fanout := tr.fanout(false)
+	slabs := strSlabs(entries, func(e entry) rect[N] {
+		return e.rect
+	}, fanout, workers, tr.deterministic)
+	var next []*node[N, T]
+	for _, slab := range slabs {
+		for j := 0; j < len(slab); j += fanout {
+			n := tr.newNode(false)
+			children := n.children()
+			for k := j; k < len(slab) && k < j+fanout; k++ {
+				n.rects.set(int(n.count), slab[k].rect)
+				children[n.count] = slab[k].node
+				n.count++
+			}
+			if n.ordered() && !n.issorted() {
+				n.sort()
+			}
+			next = append(next, n)
+		}
+	}
+	return next
+}
+
+// parallel calls fn for each index from 0 to n, using up to the provided
+// number of goroutines.
+func parallel(n, workers int, fn func(i int)) {
+	if workers <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var next int
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				i := next
+				next++
+				mu.Unlock()
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// psort is a sort that uses up to the provided number of goroutines
+// by sorting chunks in parallel and then merging them.
+// When stable is true, equal elements keep their order.
+func psort[E any](es []E, cmp func(a, b E) int, workers int, stable bool) {
+	const minChunk = 4096
+	sort := slices.SortFunc[[]E]
+	if stable {
+		sort = slices.SortStableFunc[[]E]
+	}
+	if workers <= 1 || len(es) < minChunk*2 {
+		sort(es, cmp)
+		return
+	}
+	nchunks := workers
+	if len(es)/nchunks < minChunk {
+		nchunks = len(es) / minChunk
+	}
+	bounds := make([]int, nchunks+1)
+	for i := range bounds {
+		bounds[i] = len(es) * i / nchunks
+	}
+	parallel(nchunks, workers, func(i int) {
+		chunk := es[bounds[i]:bounds[i+1]]
+		sort(chunk, cmp)
+	})
+	// merge pairs of runs until there is only one run left
+	buf := make([]E, len(es))
+	src, dst := es, buf
+	for len(bounds) > 2 {
+		var next []int
+		npairs := (len(bounds) - 1 + 1) / 2
+		parallel(npairs, workers, func(p int) {
+			lo := bounds[p*2]
+			mid := bounds[min(p*2+1, len(bounds)-1)]
+			hi := bounds[min(p*2+2, len(bounds)-1)]
+			i, j, k := lo, mid, lo
+			for i < mid && j < hi {
+				if cmp(src[j], src[i]) < 0 {
+					dst[k] = src[j]
+					j++
+				} else {
+					dst[k] = src[i]
+					i++
+				}
+				k++
+			}
+			k += copy(dst[k:], src[i:mid])
+			copy(dst[k:], src[j:hi])
+		})
+		for p := 0; p < npairs; p++ {
+			next = append(next, bounds[p*2])
+		}
+		next = append(next, len(es))
+		bounds = next
+		src, dst = dst, src
+	}
+	if &src[0] != &es[0] {
+		copy(es, src)
+	}
+}
+
+// LoadBulk loads the items into the tree.
+// This is much faster than inserting each item one at a time.
+func (tr *RTreeG[T]) LoadBulk(items []Item[float64, T]) {
+	tr.base.LoadBulk(items)
+}
+
+// LoadBulkParallel is like LoadBulk, but uses up to the provided number of
+// goroutines.
+func (tr *RTreeG[T]) LoadBulkParallel(items []Item[float64, T], workers int) {
+	tr.base.LoadBulkParallel(items, workers)
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"sort"
+	"testing"
+)
+
+func bulkSearch(tr *RTreeG[int], r rect[float64]) []int {
+	var res []int
+	tr.Search(r.min, r.max, func(min, max [2]float64, data int) bool {
+		res = append(res, data)
+		return true
+	})
+	sort.Ints(res)
+	return res
+}
+
+func TestLoadBulk(t *testing.T) {
+	for _, n := range []int{0, 1, maxEntries, maxEntries + 1, 1000, 20000} {
+		rects := make([]rect[float64], n)
+		items := make([]Item[float64, int], n)
+		for i := range items {
+			rects[i] = randRect('r')
+			items[i] = Item[float64, int]{rects[i].min, rects[i].max, i}
+		}
+		var tr1, tr2 RTreeG[int]
+		tr1.LoadBulk(items)
+		tr2.LoadBulkParallel(items, 4)
+		for _, tr := range []*RTreeG[int]{&tr1, &tr2} {
+			if tr.Len() != n {
+				t.Fatalf("expected %d, got %d", n, tr.Len())
+			}
+			if err := rSane(tr); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i := 0; i < 100; i++ {
+			q := randRect('r')
+			q.max[0] += 20
+			q.max[1] += 20
+			var expect []int
+			for j := range rects {
+				if rects[j].intersects(&q) {
+					expect = append(expect, j)
+				}
+			}
+			for _, tr := range []*RTreeG[int]{&tr1, &tr2} {
+				res := bulkSearch(tr, q)
+				if len(res) != len(expect) {
+					t.Fatalf("expected %d, got %d", len(expect), len(res))
+				}
+				for j := range res {
+					if res[j] != expect[j] {
+						t.Fatalf("expected %d, got %d", expect[j], res[j])
+					}
+				}
+			}
+		}
+		// the tree must still work normally
+		for i := 0; i < n; i += 2 {
+			tr2.Delete(rects[i].min, rects[i].max, i)
+		}
+		if tr2.Len() != n/2 {
+			t.Fatalf("expected %d, got %d", n/2, tr2.Len())
+		}
+		if err := rSane(&tr2); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadBulkExisting(t *testing.T) {
+	var tr RTreeG[int]
+	var items []Item[float64, int]
+	for i := 0; i < 500; i++ {
+		r := randRect('r')
+		if i < 200 {
+			tr.Insert(r.min, r.max, i)
+		} else {
+			items = append(items, Item[float64, int]{r.min, r.max, i})
+		}
+	}
+	h := tr.InsertHandle([2]float64{1, 1}, [2]float64{1, 1}, 500)
+	tr.LoadBulk(items)
+	if tr.Len() != 501 {
+		t.Fatalf("expected %d, got %d", 501, tr.Len())
+	}
+	seen := make(map[int]bool)
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		seen[data] = true
+		return true
+	})
+	if len(seen) != 501 {
+		t.Fatalf("expected %d, got %d", 501, len(seen))
+	}
+	if !tr.DeleteHandle(h) {
+		t.Fatal("expected handle to be kept")
+	}
+	tr.Freeze()
+	expectPanic(t, func() { tr.LoadBulk(items) })
+}
+
+func BenchmarkLoadBulk(b *testing.B) {
+	items := make([]Item[float64, int], 100000)
+	for i := range items {
+		r := randRect('r')
+		items[i] = Item[float64, int]{r.min, r.max, i}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var tr RTreeG[int]
+		tr.LoadBulkParallel(items, 4)
+	}
+}
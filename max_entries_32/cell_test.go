@@ -0,0 +1,124 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// s2Face returns the cell id of a whole cube face.
+func s2Face(face uint64) uint64 {
+	return face<<s2PosBits | 1<<(s2PosBits-1)
+}
+
+// s2Child returns a child, from 0 to 3, of a cell.
+func s2Child(id uint64, k int) uint64 {
+	lsb := id & -id
+	return id - lsb + uint64(2*k+1)*(lsb>>2)
+}
+
+func approx(a, b [2]float64) bool {
+	return math.Abs(a[0]-b[0]) < 1e-9 && math.Abs(a[1]-b[1]) < 1e-9
+}
+
+func TestS2CellBounds(t *testing.T) {
+	min, max, ok := S2CellBounds(s2Face(0))
+	if !ok || !approx(min, [2]float64{-45, -45}) ||
+		!approx(max, [2]float64{45, 45}) {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	min, max, _ = S2CellBounds(s2Face(2))
+	corner := math.Atan(1/math.Sqrt2) * degrees
+	if !approx(min, [2]float64{-180, corner}) ||
+		!approx(max, [2]float64{180, 90}) {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	min, max, _ = S2CellBounds(s2Face(5))
+	if min[1] != -90 || max[1] > -corner+1e-9 {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	// the cell with the token 89c25 covers New York City
+	min, max, _ = S2CellBounds(0x89c2500000000000)
+	nyc := rect[float64]{[2]float64{-74.006, 40.7128}, [2]float64{-74.006, 40.7128}}
+	if r := (rect[float64]{min, max}); !r.contains(&nyc) {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	for _, id := range []uint64{0, 6 << s2PosBits, s2Face(0) >> 1} {
+		if _, _, ok := S2CellBounds(id); ok {
+			t.Fatalf("expected invalid cell %x", id)
+		}
+	}
+	// children are inside of their parent
+	for i := 0; i < 1000; i++ {
+		id := s2Face(uint64(rand.Intn(6)))
+		level := rand.Intn(s2MaxLevel)
+		for l := 0; l < level; l++ {
+			id = s2Child(id, rand.Intn(4))
+		}
+		if l, _ := s2Level(id); l != level {
+			t.Fatalf("expected level %d, got %d", level, l)
+		}
+		min, max, ok := S2CellBounds(id)
+		if !ok {
+			t.Fatalf("expected valid cell %x", id)
+		}
+		parent := rect[float64]{min, max}
+		for k := 0; k < 4; k++ {
+			cmin, cmax, _ := S2CellBounds(s2Child(id, k))
+			child := rect[float64]{
+				[2]float64{cmin[0] + 1e-9, cmin[1] + 1e-9},
+				[2]float64{cmax[0] - 1e-9, cmax[1] - 1e-9},
+			}
+			if child.min[0] > child.max[0] {
+				child.min[0], child.max[0] = cmin[0], cmax[0]
+			}
+			if child.min[1] > child.max[1] {
+				child.min[1], child.max[1] = cmin[1], cmax[1]
+			}
+			if !parent.contains(&child) {
+				t.Fatalf("child %v outside of parent %v", child, parent)
+			}
+		}
+	}
+}
+
+func TestCellRTree(t *testing.T) {
+	tr := NewCellRTreeG[int](S2CellBounds)
+	face0 := s2Face(0)
+	var cells []uint64
+	for k := 0; k < 4; k++ {
+		for k2 := 0; k2 < 4; k2++ {
+			cells = append(cells, s2Child(s2Child(face0, k), k2))
+		}
+	}
+	for i, cell := range cells {
+		if !tr.Insert(cell, i) {
+			t.Fatalf("cell %x not inserted", cell)
+		}
+	}
+	tr.Insert(s2Face(1), -1)
+	if tr.Insert(0, -2) {
+		t.Fatal("expected invalid cell")
+	}
+	var found int
+	tr.Search(s2Child(face0, 0), func(cell uint64, data int) bool {
+		if data < 0 {
+			t.Fatalf("unexpected cell %x", cell)
+		}
+		found++
+		return true
+	})
+	if found < 4 || found == len(cells) {
+		t.Fatalf("unexpected number of cells %d", found)
+	}
+	if !tr.Delete(cells[0], 0) || tr.Delete(cells[0], 0) {
+		t.Fatal("unexpected delete result")
+	}
+	if tr.Len() != len(cells) {
+		t.Fatalf("expected %d, got %d", len(cells), tr.Len())
+	}
+}
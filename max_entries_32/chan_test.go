@@ -0,0 +1,78 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"context"
+	"sort"
+	"testing"
+)
+
+func TestSearchChan(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	q := rect[float64]{[2]float64{-90, -45}, [2]float64{90, 45}}
+	expect := bulkSearch(&tr, q)
+	var res []int
+	for item := range tr.SearchChan(context.Background(), q.min, q.max) {
+		res = append(res, item.Data)
+		if item.Min != rects[item.Data].min ||
+			item.Max != rects[item.Data].max {
+			t.Fatal("rect mismatch")
+		}
+		// modifying the tree does not affect the results
+		tr.Delete(rects[item.Data].min, rects[item.Data].max, item.Data)
+	}
+	sort.Ints(res)
+	if len(res) != len(expect) {
+		t.Fatalf("expected %d, got %d", len(expect), len(res))
+	}
+	for i := range res {
+		if res[i] != expect[i] {
+			t.Fatalf("expected %d, got %d", expect[i], res[i])
+		}
+	}
+	if tr.Len() != len(rects)-len(expect) {
+		t.Fatalf("expected %d, got %d", len(rects)-len(expect), tr.Len())
+	}
+
+	// cancel
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := tr.SearchChan(ctx, [2]float64{-180, -90}, [2]float64{180, 90})
+	<-ch
+	cancel()
+	var n int
+	for range ch {
+		n++
+	}
+	if n > 1 {
+		t.Fatalf("expected at most 1 more item, got %d", n)
+	}
+}
+
+func TestSearchChanFrozen(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 1000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	tr.Freeze()
+	icow := tr.base.icow
+	var count int
+	for range tr.SearchChan(context.Background(), [2]float64{-180, -90},
+		[2]float64{180, 90}) {
+		count++
+	}
+	if count != 1000 {
+		t.Fatalf("expected %d, got %d", 1000, count)
+	}
+	if tr.base.icow != icow {
+		t.Fatal("frozen tree was written")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// SearchCircle searches for items in tree that intersect the circle at center
+// with the provided radius.
+// Nodes are pruned by their box distance to the center point, so only the
+// items that actually touch the circle are sent to iter; unlike searching with
+// the enclosing square, which also includes the corners.
+// Distances are squared as float64, which cannot overflow for integer types.
+func (tr *RTreeGN[N, T]) SearchCircle(center [2]N, radius N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	if tr.root == nil {
+		return
+	}
+	target := rect[N]{center, center}
+	r2 := float64(radius) * float64(radius)
+	if target.boxDist2(&tr.rect) > r2 {
+		return
+	}
+	tr.root.searchCircle(&target, r2, tr.guard(iter))
+}
+
+func (n *node[N, T]) searchCircle(target *rect[N], r2 float64,
+	iter func(min, max [2]N, data T) bool,
+) bool {
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			if r := n.rects.at(i); !(target.boxDist2(&r) > r2) {
+				if !iter(r.min, r.max, items[i]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		if r := n.rects.at(i); !(target.boxDist2(&r) > r2) {
+			if !children[i].searchCircle(target, r2, iter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SearchCircle searches for items in tree that intersect the circle at center
+// with the provided radius.
+func (tr *RTreeG[T]) SearchCircle(center [2]float64, radius float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.SearchCircle(center, radius, iter)
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSearchCircle(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 10000; i++ {
+		r := randRect('m')
+		tr.Insert(r.min, r.max, i)
+	}
+	center := [2]float64{10, 20}
+	radius := 30.0
+	var count int
+	tr.SearchCircle(center, radius, func(min, max [2]float64, data int) bool {
+		r := rect[float64]{min, max}
+		c := rect[float64]{center, center}
+		if math.Sqrt(c.boxDist(&r)) > radius {
+			t.Fatalf("item %d outside of circle", data)
+		}
+		count++
+		return true
+	})
+	var expect int
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		r := rect[float64]{min, max}
+		c := rect[float64]{center, center}
+		if math.Sqrt(c.boxDist(&r)) <= radius {
+			expect++
+		}
+		return true
+	})
+	if count != expect {
+		t.Fatalf("expected %d, got %d", expect, count)
+	}
+	// fewer items than the enclosing square
+	var square int
+	tr.Search(
+		[2]float64{center[0] - radius, center[1] - radius},
+		[2]float64{center[0] + radius, center[1] + radius},
+		func(min, max [2]float64, data int) bool {
+			square++
+			return true
+		},
+	)
+	if count > square {
+		t.Fatalf("expected at most %d, got %d", square, count)
+	}
+}
+
+func TestSearchCircleInt32(t *testing.T) {
+	var tr RTreeGN[int32, int]
+	for i := 0; i < 1000; i++ {
+		p := [2]int32{int32(i * 100), int32(i * 100)}
+		tr.Insert(p, p, i)
+	}
+	// the squared radius doesn't fit in an int32
+	center := [2]int32{0, 0}
+	radius := int32(50000)
+	var count int
+	tr.SearchCircle(center, radius, func(min, max [2]int32, data int) bool {
+		count++
+		return true
+	})
+	// the points up to 50000/sqrt(2), which is 353.55 points apart, from
+	// the center on both axes
+	expect := 354
+	if count != expect {
+		t.Fatalf("expected %d, got %d", expect, count)
+	}
+}
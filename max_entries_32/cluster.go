@@ -0,0 +1,190 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Cluster is a group of items in a cell of the grid of Clusters.
+type Cluster[N numeric] struct {
+	// Min and Max are the bounding rectangle of the items.
+	Min, Max [2]N
+	// Count is the number of items.
+	Count int
+	// Centroid is the average of the centers of the items.
+	Centroid [2]float64
+}
+
+// clusterStats is the number of items of a node and the sum of their
+// centers.
+type clusterStats struct {
+	count int
+	sum   [2]float64
+}
+
+// clusterAgg is the aggregator that maintains the clusterStats of every
+// node.
+type clusterAgg[N numeric, T any] struct {
+	idx int
+}
+
+func (ca *clusterAgg[N, T]) fix(n *node[N, T]) {
+	var s clusterStats
+	if n.leaf() {
+		s.count = int(n.count)
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			s.sum[0] += r.center(0)
+			s.sum[1] += r.center(1)
+		}
+	} else {
+		children := n.children()[:n.count]
+		for i := range children {
+			cs := children[i].aggs.vals[ca.idx].(clusterStats)
+			s.count += cs.count
+			s.sum[0] += cs.sum[0]
+			s.sum[1] += cs.sum[1]
+		}
+	}
+	n.aggs.vals[ca.idx] = s
+}
+
+// SetClusters sets whether the number of items and the sum of their centers
+// are maintained for every node, like SetWeight, so that Clusters can add
+// the items of a node to a cluster without visiting them.
+func (tr *RTreeGN[N, T]) SetClusters(enabled bool) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.cluster != nil {
+		tr.removeAggregator(tr.cluster.idx)
+		tr.cluster = nil
+	}
+	if enabled {
+		ca := &clusterAgg[N, T]{}
+		tr.addAggregator(ca, &ca.idx)
+		tr.cluster = ca
+	}
+}
+
+// clusterCell returns the cell of the grid of Clusters along an axis.
+func clusterCell[N numeric](v float64, cellSize N) int64 {
+	return int64(math.Floor(v / float64(cellSize)))
+}
+
+// Clusters groups the items that intersect the provided rectangle by the
+// cells of a grid, such as for showing the number of points in each part of
+// a map when there are too many to draw. The cells are squares of cellSize,
+// aligned to zero, so that the clusters don't move when the map is panned.
+// An item is in the cell of the center of its rectangle.
+//
+// Nodes that are inside of the rectangle and whose items are all in a single
+// cell are added to the cluster of the cell as a whole. With SetClusters,
+// the items of such nodes are never visited, which makes clustering large
+// areas much faster.
+//
+// The clusters are returned in order of their cells, by y and then by x.
+func (tr *RTreeGN[N, T]) Clusters(min, max [2]N, cellSize N) []Cluster[N] {
+	target := rect[N]{min, max}
+	if tr.root == nil || !(cellSize > 0) || !target.intersects(&tr.rect) {
+		return nil
+	}
+	type cell struct {
+		key     [2]int64
+		cluster Cluster[N]
+		sum     [2]float64
+	}
+	var cells []cell
+	index := make(map[[2]int64]int)
+	add := func(key [2]int64, r *rect[N], count int, sum [2]float64) {
+		i, ok := index[key]
+		if !ok {
+			i = len(cells)
+			index[key] = i
+			cells = append(cells, cell{key: key,
+				cluster: Cluster[N]{Min: r.min, Max: r.max}})
+		}
+		c := &cells[i]
+		cr := rect[N]{c.cluster.Min, c.cluster.Max}
+		cr.expand(r)
+		c.cluster.Min, c.cluster.Max = cr.min, cr.max
+		c.cluster.Count += count
+		c.sum[0] += sum[0]
+		c.sum[1] += sum[1]
+	}
+	var search func(n *node[N, T])
+	search = func(n *node[N, T]) {
+		if n.leaf() {
+			for i := 0; i < int(n.count); i++ {
+				r := n.rects.at(i)
+				if !target.intersects(&r) {
+					continue
+				}
+				center := [2]float64{r.center(0), r.center(1)}
+				key := [2]int64{clusterCell(center[0], cellSize),
+					clusterCell(center[1], cellSize)}
+				add(key, &r, 1, center)
+			}
+			return
+		}
+		children := n.children()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if !target.intersects(&r) {
+				continue
+			}
+			if target.contains(&r) {
+				key := [2]int64{clusterCell(float64(r.min[0]), cellSize),
+					clusterCell(float64(r.min[1]), cellSize)}
+				if key == [2]int64{clusterCell(float64(r.max[0]), cellSize),
+					clusterCell(float64(r.max[1]), cellSize)} {
+					var s clusterStats
+					if tr.cluster != nil {
+						s = children[i].aggs.vals[tr.cluster.idx].(clusterStats)
+					} else {
+						s.count = children[i].deepCount()
+						children[i].sumCenters(&s.sum)
+					}
+					add(key, &r, s.count, s.sum)
+					continue
+				}
+			}
+			search(children[i])
+		}
+	}
+	search(tr.root)
+	slices.SortFunc(cells, func(a, b cell) int {
+		if c := cmp.Compare(a.key[1], b.key[1]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.key[0], b.key[0])
+	})
+	clusters := make([]Cluster[N], len(cells))
+	for i, c := range cells {
+		clusters[i] = c.cluster
+		clusters[i].Centroid = [2]float64{
+			c.sum[0] / float64(c.cluster.Count),
+			c.sum[1] / float64(c.cluster.Count),
+		}
+	}
+	return clusters
+}
+
+// SetClusters sets whether the number of items and the sum of their centers
+// are maintained for every node.
+func (tr *RTreeG[T]) SetClusters(enabled bool) {
+	tr.base.SetClusters(enabled)
+}
+
+// Clusters groups the items that intersect the provided rectangle by the
+// cells of a grid. See RTreeGN.Clusters.
+func (tr *RTreeG[T]) Clusters(min, max [2]float64,
+	cellSize float64,
+) []Cluster[float64] {
+	return tr.base.Clusters(min, max, cellSize)
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestClusters(t *testing.T) {
+	var tr RTreeG[int]
+	if clusters := tr.Clusters([2]float64{-180, -90}, [2]float64{180, 90},
+		10); clusters != nil {
+		t.Fatalf("expected nil, got %v", clusters)
+	}
+	N := 10000
+	items := make([]Item[float64, int], N)
+	for i := 0; i < N; i++ {
+		r := randRect('m')
+		items[i] = Item[float64, int]{r.min, r.max, i}
+		tr.Insert(r.min, r.max, i)
+	}
+	check := func() {
+		t.Helper()
+		for i := 0; i < 50; i++ {
+			q := randRect('r')
+			q.max[0] = q.min[0] + rand.Float64()*200
+			q.max[1] = q.min[1] + rand.Float64()*100
+			cellSize := rand.Float64()*30 + 1
+			expect := make(map[[2]int64]Cluster[float64])
+			for _, item := range items {
+				ir := rect[float64]{item.Min, item.Max}
+				if !ir.intersects(&q) {
+					continue
+				}
+				key := [2]int64{clusterCell(ir.center(0), cellSize),
+					clusterCell(ir.center(1), cellSize)}
+				c, ok := expect[key]
+				if !ok {
+					c = Cluster[float64]{Min: ir.min, Max: ir.max}
+				}
+				cr := rect[float64]{c.Min, c.Max}
+				cr.expand(&ir)
+				c.Min, c.Max = cr.min, cr.max
+				c.Count++
+				c.Centroid[0] += ir.center(0)
+				c.Centroid[1] += ir.center(1)
+				expect[key] = c
+			}
+			clusters := tr.Clusters(q.min, q.max, cellSize)
+			if len(clusters) != len(expect) {
+				t.Fatalf("expected %d clusters, got %d", len(expect),
+					len(clusters))
+			}
+			for j, c := range clusters {
+				key := [2]int64{clusterCell(c.Centroid[0], cellSize),
+					clusterCell(c.Centroid[1], cellSize)}
+				e := expect[key]
+				if c.Min != e.Min || c.Max != e.Max || c.Count != e.Count ||
+					math.Abs(c.Centroid[0]-e.Centroid[0]/float64(e.Count)) > 1e-9 ||
+					math.Abs(c.Centroid[1]-e.Centroid[1]/float64(e.Count)) > 1e-9 {
+					t.Fatalf("expected %v, got %v", e, c)
+				}
+				if j > 0 {
+					prev := clusters[j-1]
+					pkey := [2]int64{clusterCell(prev.Centroid[0], cellSize),
+						clusterCell(prev.Centroid[1], cellSize)}
+					if pkey[1] > key[1] || pkey[1] == key[1] && pkey[0] >= key[0] {
+						t.Fatalf("clusters are out of order")
+					}
+				}
+			}
+		}
+	}
+	check()
+	tr.SetClusters(true)
+	check()
+	for i := 0; i < N; i += 2 {
+		tr.Delete(items[i].Min, items[i].Max, items[i].Data)
+	}
+	for i := 0; i < N/2; i++ {
+		items[i] = items[i*2+1]
+	}
+	items = items[:N/2]
+	check()
+	tr.SetClusters(false)
+	check()
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCompact(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 20000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for i := range rects {
+		if i%5 != 0 {
+			tr.Delete(rects[i].min, rects[i].max, i)
+		}
+	}
+	before := tr.Stats()
+	if n := tr.Compact(0.5); n == 0 {
+		t.Fatal("expected compaction")
+	}
+	after := tr.Stats()
+	if after.LeafFill <= before.LeafFill || after.Leaves >= before.Leaves {
+		t.Fatalf("expected better fill, got %+v then %+v", before, after)
+	}
+	if err := tr.SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Len() != len(rects)/5 {
+		t.Fatalf("expected %d, got %d", len(rects)/5, tr.Len())
+	}
+	for i := 0; i < len(rects); i += 5 {
+		if !tr.Exists(rects[i].min, rects[i].max, i) {
+			t.Fatalf("item %d not found", i)
+		}
+	}
+	// compacting again does nothing
+	if n := tr.Compact(0.5); n != 0 {
+		t.Fatalf("expected %d, got %d", 0, n)
+	}
+}
+
+func TestAutoCompaction(t *testing.T) {
+	var mu sync.Mutex
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 20000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	stop := tr.StartAutoCompaction(0.6, time.Millisecond, &mu)
+	exists := make([]bool, len(rects))
+	for i := range exists {
+		exists[i] = true
+	}
+	start := time.Now()
+	for time.Since(start) < time.Millisecond*200 {
+		mu.Lock()
+		for j := 0; j < 100; j++ {
+			i := rand.Intn(len(rects))
+			if exists[i] {
+				tr.Delete(rects[i].min, rects[i].max, i)
+				exists[i] = false
+			} else if rand.Intn(4) == 0 {
+				tr.Insert(rects[i].min, rects[i].max, i)
+				exists[i] = true
+			}
+		}
+		mu.Unlock()
+		time.Sleep(time.Microsecond * 100)
+	}
+	stop()
+	stop()
+	if err := tr.SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	for i := range rects {
+		if exists[i] {
+			count++
+			if !tr.Exists(rects[i].min, rects[i].max, i) {
+				t.Fatalf("item %d not found", i)
+			}
+		}
+	}
+	if tr.Len() != count {
+		t.Fatalf("expected %d, got %d", count, tr.Len())
+	}
+}
+
+func TestAutoCompactionUnchanged(t *testing.T) {
+	var mu sync.Mutex
+	var tr RTreeG[int]
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	icow := tr.base.icow
+	// nothing can be compacted below a threshold of zero
+	stop := tr.StartAutoCompaction(0, time.Millisecond, &mu)
+	defer stop()
+	for start := time.Now(); ; time.Sleep(time.Millisecond) {
+		mu.Lock()
+		snapped := tr.base.icow != icow
+		icow = tr.base.icow
+		mu.Unlock()
+		if snapped {
+			break
+		}
+		if time.Since(start) > time.Second*10 {
+			t.Fatal("no snapshot was taken")
+		}
+	}
+	time.Sleep(time.Millisecond * 20)
+	mu.Lock()
+	defer mu.Unlock()
+	if tr.base.icow != icow {
+		t.Fatal("unchanged tree was copied")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "maps"
+
+// DedupRTreeGN is an R-tree where a single logical item may be inserted
+// under multiple rectangles, such as the parts of a multipolygon or of a
+// shape that was split at the antimeridian, and is returned only once by
+// each search.
+// Items are identified by the key that the key function returns for their
+// data, so all parts of an item must have data with the same key.
+type DedupRTreeGN[N numeric, T any, K comparable] struct {
+	key   func(data T) K
+	parts map[K]int // number of parts for each key
+	base  RTreeGN[N, T]
+}
+
+// NewDedupRTreeGN returns a new tree that identifies items by the key that
+// is returned by the provided function.
+func NewDedupRTreeGN[N numeric, T any, K comparable](key func(data T) K,
+) *DedupRTreeGN[N, T, K] {
+	return &DedupRTreeGN[N, T, K]{key: key, parts: make(map[K]int)}
+}
+
+// Insert a part of an item into tree.
+func (tr *DedupRTreeGN[N, T, K]) Insert(min, max [2]N, data T) {
+	tr.base.Insert(min, max, data)
+	tr.parts[tr.key(data)]++
+}
+
+// Delete a part of an item from tree.
+func (tr *DedupRTreeGN[N, T, K]) Delete(min, max [2]N, data T) {
+	n := tr.base.Len()
+	tr.base.Delete(min, max, data)
+	if tr.base.Len() == n {
+		return
+	}
+	k := tr.key(data)
+	if tr.parts[k] == 1 {
+		delete(tr.parts, k)
+	} else {
+		tr.parts[k]--
+	}
+}
+
+// Len returns the number of distinct items in tree.
+func (tr *DedupRTreeGN[N, T, K]) Len() int {
+	return len(tr.parts)
+}
+
+// Parts returns the number of parts, which is the number of rectangles in
+// tree.
+func (tr *DedupRTreeGN[N, T, K]) Parts() int {
+	return tr.base.Len()
+}
+
+// Bounds returns the minimum bounding rect.
+func (tr *DedupRTreeGN[N, T, K]) Bounds() (min, max [2]N) {
+	return tr.base.Bounds()
+}
+
+// dedup returns a function that returns true the first time that the key
+// of the data is seen. Keys of items that have only one part are not
+// remembered.
+func (tr *DedupRTreeGN[N, T, K]) dedup() func(data T) bool {
+	var seen map[K]struct{}
+	return func(data T) bool {
+		k := tr.key(data)
+		if tr.parts[k] < 2 {
+			return true
+		}
+		if _, ok := seen[k]; ok {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[K]struct{})
+		}
+		seen[k] = struct{}{}
+		return true
+	}
+}
+
+// Search for items in tree that intersect the provided rectangle.
+// Each item is returned only once, with the rectangle of the first of its
+// parts that was found.
+func (tr *DedupRTreeGN[N, T, K]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	first := tr.dedup()
+	tr.base.Search(min, max, func(min, max [2]N, data T) bool {
+		if !first(data) {
+			return true
+		}
+		return iter(min, max, data)
+	})
+}
+
+// Nearby performs a kNN-type operation on the index, like RTreeGN.Nearby.
+// Each item is returned only once, with the rectangle and distance of its
+// nearest part.
+func (tr *DedupRTreeGN[N, T, K]) Nearby(
+	dist func(min, max [2]N, data T, item bool) N,
+	iter func(min, max [2]N, data T, dist N) bool,
+) {
+	first := tr.dedup()
+	tr.base.Nearby(dist, func(min, max [2]N, data T, dist N) bool {
+		if !first(data) {
+			return true
+		}
+		return iter(min, max, data, dist)
+	})
+}
+
+// Scan all items in the tree.
+// Each item is returned only once, with the rectangle of one of its parts.
+func (tr *DedupRTreeGN[N, T, K]) Scan(
+	iter func(min, max [2]N, data T) bool,
+) {
+	first := tr.dedup()
+	tr.base.Scan(func(min, max [2]N, data T) bool {
+		if !first(data) {
+			return true
+		}
+		return iter(min, max, data)
+	})
+}
+
+// ScanParts scans all parts of all items in the tree.
+func (tr *DedupRTreeGN[N, T, K]) ScanParts(
+	iter func(min, max [2]N, data T) bool,
+) {
+	tr.base.Scan(iter)
+}
+
+// Copy the tree.
+// The tree itself is copied using copy-on-write, but the number of parts of
+// every item is copied right away.
+func (tr *DedupRTreeGN[N, T, K]) Copy() *DedupRTreeGN[N, T, K] {
+	return &DedupRTreeGN[N, T, K]{
+		key:   tr.key,
+		parts: maps.Clone(tr.parts),
+		base:  *tr.base.Copy(),
+	}
+}
+
+// Clear will delete all items.
+func (tr *DedupRTreeGN[N, T, K]) Clear() {
+	clear(tr.parts)
+	tr.base.Clear()
+}
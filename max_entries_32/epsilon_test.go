@@ -0,0 +1,89 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEpsilon(t *testing.T) {
+	var tr RTreeG[int]
+	if tr.Epsilon() != 0 {
+		t.Fatal("expected zero")
+	}
+	rects := make([]rect[float64], 2000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	nudge := func(r rect[float64]) rect[float64] {
+		for i := 0; i < 2; i++ {
+			r.min[i] = math.Nextafter(r.min[i], math.Inf(1))
+			r.max[i] = math.Nextafter(r.max[i], math.Inf(-1))
+		}
+		r.min[0] = math.Nextafter(r.min[0], math.Inf(-1))
+		r.min[0] = math.Nextafter(r.min[0], math.Inf(-1))
+		return r
+	}
+	// without a tolerance the nudged rects are not found
+	for i := 0; i < 100; i++ {
+		r := nudge(rects[i])
+		tr.Delete(r.min, r.max, i)
+	}
+	if tr.Len() != len(rects) {
+		t.Fatalf("expected %d, got %d", len(rects), tr.Len())
+	}
+	tr.SetEpsilon(1e-9)
+	if tr.Epsilon() != 1e-9 {
+		t.Fatal("expected 1e-9")
+	}
+	for i := 0; i < 100; i++ {
+		r := nudge(rects[i])
+		tr.Delete(r.min, r.max, i)
+		if err := tr.SanityCheck(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tr.Len() != len(rects)-100 {
+		t.Fatalf("expected %d, got %d", len(rects)-100, tr.Len())
+	}
+	// still needs the right data
+	r := nudge(rects[100])
+	tr.Delete(r.min, r.max, 101)
+	if tr.Len() != len(rects)-100 {
+		t.Fatal("deleted the wrong item")
+	}
+	// too far away
+	r = rects[100]
+	r.min[0] += 1e-6
+	tr.Delete(r.min, r.max, 100)
+	if tr.Len() != len(rects)-100 {
+		t.Fatal("deleted an item that is too far away")
+	}
+	r = nudge(rects[100])
+	tr.Replace(r.min, r.max, 100, r.min, r.max, -100)
+	var found bool
+	tr.Search(r.min, r.max, func(min, max [2]float64, data int) bool {
+		if data == 100 {
+			t.Fatal("expected the item to be replaced")
+		}
+		found = found || data == -100
+		return true
+	})
+	if !found {
+		t.Fatal("expected the new item")
+	}
+	for i := 101; i < len(rects); i++ {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+	if tr.Len() != 1 {
+		t.Fatalf("expected 1, got %d", tr.Len())
+	}
+	tr.SetEpsilon(-1)
+	if tr.Epsilon() != 0 {
+		t.Fatal("expected zero")
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// rangeFilter summarizes the items by the range of their values.
+type rangeFilter struct{}
+
+func (rangeFilter) Summary(data int) [2]int {
+	return [2]int{data, data}
+}
+
+func (rangeFilter) Merge(a, b [2]int) [2]int {
+	return [2]int{min(a[0], b[0]), max(a[1], b[1])}
+}
+
+func TestFilterIndex(t *testing.T) {
+	var tr RTreeG[int]
+	N := 10000
+	rects := make([]rect[float64], N)
+	tags := make([][]string, N)
+	for i := 0; i < N; i++ {
+		rects[i] = randRect('m')
+		for j := rand.Intn(3); j >= 0; j-- {
+			tags[i] = append(tags[i], fmt.Sprintf("tag%d", rand.Intn(100)))
+		}
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	tf := RegisterFilterG[int](&tr, TagFilter[int]{
+		Tags: func(data int) []string { return tags[data] },
+	})
+	rf := RegisterFilterG[int, [2]int](&tr, rangeFilter{})
+	deleted := make([]bool, N)
+	search := func(q rect[float64], tag string, lo, hi int,
+	) (bytag, bytagBloom, byrange []int) {
+		iter := func(dst *[]int) func(min, max [2]float64, data int) bool {
+			return func(min, max [2]float64, data int) bool {
+				*dst = append(*dst, data)
+				return true
+			}
+		}
+		tf.Search(q.min, q.max,
+			func(b TagBloom) bool { return b.MayHave(tag) },
+			func(data int) bool { return slices.Contains(tags[data], tag) },
+			iter(&bytag))
+		tf.Search(q.min, q.max,
+			func(b TagBloom) bool { return b.MayHave(tag) }, nil,
+			iter(&bytagBloom))
+		rf.Search(q.min, q.max,
+			func(r [2]int) bool { return r[1] >= lo && r[0] <= hi }, nil,
+			iter(&byrange))
+		slices.Sort(bytag)
+		slices.Sort(bytagBloom)
+		slices.Sort(byrange)
+		return bytag, bytagBloom, byrange
+	}
+	check := func() {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			q := randRect('r')
+			q.max[0] = q.min[0] + rand.Float64()*100
+			q.max[1] = q.min[1] + rand.Float64()*50
+			tag := fmt.Sprintf("tag%d", rand.Intn(100))
+			lo := rand.Intn(N)
+			hi := lo + rand.Intn(1000)
+			var expectTag, expectRange []int
+			for j := 0; j < N; j++ {
+				if deleted[j] || !rects[j].intersects(&q) {
+					continue
+				}
+				if slices.Contains(tags[j], tag) {
+					expectTag = append(expectTag, j)
+				}
+				if j >= lo && j <= hi {
+					expectRange = append(expectRange, j)
+				}
+			}
+			bytag, bytagBloom, byrange := search(q, tag, lo, hi)
+			if !slices.Equal(bytag, expectTag) {
+				t.Fatalf("expected %v, got %v", expectTag, bytag)
+			}
+			for _, data := range expectTag {
+				if _, ok := slices.BinarySearch(bytagBloom, data); !ok {
+					t.Fatalf("expected %d in %v", data, bytagBloom)
+				}
+			}
+			if !slices.Equal(byrange, expectRange) {
+				t.Fatalf("expected %v, got %v", expectRange, byrange)
+			}
+		}
+	}
+	check()
+	for i := 0; i < N; i += 3 {
+		tr.Delete(rects[i].min, rects[i].max, i)
+		deleted[i] = true
+	}
+	check()
+
+	// the copy keeps the filters
+	cp := tr.Copy()
+	tf = tf.In(&cp.base)
+	rf = rf.In(&cp.base)
+	check()
+
+	// the bloom filter never misses a tag
+	var b TagBloom
+	for i := 0; i < 10; i++ {
+		b.Add(fmt.Sprint(i))
+	}
+	for i := 0; i < 10; i++ {
+		if !b.MayHave(fmt.Sprint(i)) {
+			t.Fatalf("expected %d", i)
+		}
+	}
+
+	tf.Unregister()
+	rf.Unregister()
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "math"
+
+// earthRadius is the mean radius of the earth in meters.
+const earthRadius = 6371008.8
+
+const radians = math.Pi / 180
+const degrees = 180 / math.Pi
+
+// haversine returns the great-circle distance, in meters, between two
+// points that are in radians.
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	sdlat := math.Sin((lat2 - lat1) / 2)
+	sdlon := math.Sin((lon2 - lon1) / 2)
+	a := sdlat*sdlat + math.Cos(lat1)*math.Cos(lat2)*sdlon*sdlon
+	return 2 * earthRadius * math.Asin(math.Sqrt(math.Min(a, 1)))
+}
+
+// geoDist returns the great-circle distance, in meters, from the point at
+// lat/lon to the nearest point of the rectangle, where the X axis of the
+// rectangle is longitude and Y axis is latitude, all in degrees.
+// Returns zero when the point is inside of the rectangle.
+func geoDist(lat, lon float64, r *rect[float64]) float64 {
+	if lon >= r.min[0] && lon <= r.max[0] {
+		// The nearest point is on the same meridian.
+		if lat < r.min[1] {
+			return (r.min[1] - lat) * radians * earthRadius
+		}
+		if lat > r.max[1] {
+			return (lat - r.max[1]) * radians * earthRadius
+		}
+		return 0
+	}
+	// The nearest point is on one of the two meridian edges.
+	return math.Min(
+		geoEdgeDist(lat*radians, lon*radians,
+			r.min[0]*radians, r.min[1]*radians, r.max[1]*radians),
+		geoEdgeDist(lat*radians, lon*radians,
+			r.max[0]*radians, r.min[1]*radians, r.max[1]*radians),
+	)
+}
+
+// geoEdgeDist returns the distance from a point to the meridian segment at
+// elon running from minLat to maxLat, all in radians.
+func geoEdgeDist(lat, lon, elon, minLat, maxLat float64) float64 {
+	dlon := math.Remainder(lon-elon, 2*math.Pi)
+	if math.Abs(dlon) >= math.Pi/2 {
+		// The point is on the far side of the meridian, where the distance
+		// is largest somewhere in the middle of the segment, so the nearest
+		// point is one of the ends.
+		return math.Min(
+			haversine(lat, lon, minLat, elon),
+			haversine(lat, lon, maxLat, elon),
+		)
+	}
+	// The latitude of the nearest point on the meridian great circle.
+	// The distance along the meridian is unimodal, so clamping to the segment
+	// gives the nearest point on the segment.
+	nlat := math.Atan(math.Tan(lat) / math.Cos(dlon))
+	nlat = math.Max(minLat, math.Min(maxLat, nlat))
+	return haversine(lat, lon, nlat, elon)
+}
+
+// geoBounds returns the lat/lon bounding boxes, in degrees, that fully
+// contain the circle at lat/lon with a radius in meters.
+// A second box is returned when the circle crosses the antimeridian.
+func geoBounds(lat, lon, meters float64) (boxes [2]rect[float64], n int) {
+	r := meters / earthRadius
+	rlat, rlon := lat*radians, lon*radians
+	minLat, maxLat := rlat-r, rlat+r
+	var minLon, maxLon float64
+	if minLat > -math.Pi/2 && maxLat < math.Pi/2 {
+		dlon := math.Asin(math.Min(math.Sin(r)/math.Cos(rlat), 1))
+		minLon, maxLon = rlon-dlon, rlon+dlon
+		if dlon >= math.Pi/2 || maxLon-minLon >= 2*math.Pi {
+			minLon, maxLon = -math.Pi, math.Pi
+		}
+	} else {
+		// A pole is within the radius.
+		minLat = math.Max(minLat, -math.Pi/2)
+		maxLat = math.Min(maxLat, math.Pi/2)
+		minLon, maxLon = -math.Pi, math.Pi
+	}
+	minLat, maxLat = minLat*degrees, maxLat*degrees
+	minLon, maxLon = minLon*degrees, maxLon*degrees
+	switch {
+	case minLon < -180:
+		boxes[0] = rect[float64]{[2]float64{minLon + 360, minLat},
+			[2]float64{180, maxLat}}
+		boxes[1] = rect[float64]{[2]float64{-180, minLat},
+			[2]float64{maxLon, maxLat}}
+		return boxes, 2
+	case maxLon > 180:
+		boxes[0] = rect[float64]{[2]float64{minLon, minLat},
+			[2]float64{180, maxLat}}
+		boxes[1] = rect[float64]{[2]float64{-180, minLat},
+			[2]float64{maxLon - 360, maxLat}}
+		return boxes, 2
+	}
+	boxes[0] = rect[float64]{[2]float64{minLon, minLat},
+		[2]float64{maxLon, maxLat}}
+	return boxes, 1
+}
+
+// SearchGeoRadius searches for items that are within the provided distance,
+// in meters, from the lat/lon point.
+// The tree is expected to contain longitude on the X axis and latitude on the
+// Y axis, in degrees.
+// The radius is converted into one or two bounding boxes that account for the
+// poles and the antimeridian, and each candidate is refined using its
+// great-circle distance to the point.
+func (tr *RTreeG[T]) SearchGeoRadius(lat, lon, meters float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	boxes, n := geoBounds(lat, lon, meters)
+	for i := 0; i < n; i++ {
+		ok := true
+		tr.base.Search(boxes[i].min, boxes[i].max,
+			func(min, max [2]float64, data T) bool {
+				r := rect[float64]{min, max}
+				if i > 0 && r.intersects(&boxes[0]) {
+					// already seen in the first box
+					return true
+				}
+				if geoDist(lat, lon, &r) > meters {
+					return true
+				}
+				ok = iter(min, max, data)
+				return ok
+			},
+		)
+		if !ok {
+			return
+		}
+	}
+}
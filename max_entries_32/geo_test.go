@@ -0,0 +1,74 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSearchGeoRadius(t *testing.T) {
+	var tr RTreeG[int]
+	var pts [][2]float64
+	for i := 0; i < 10000; i++ {
+		p := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		tr.Insert(p, p, i)
+		pts = append(pts, p)
+	}
+	check := func(lat, lon, meters float64) {
+		t.Helper()
+		var expect int
+		for _, p := range pts {
+			if haversine(lat*radians, lon*radians,
+				p[1]*radians, p[0]*radians) <= meters {
+				expect++
+			}
+		}
+		var count int
+		seen := make(map[int]bool)
+		tr.SearchGeoRadius(lat, lon, meters,
+			func(min, max [2]float64, data int) bool {
+				if seen[data] {
+					t.Fatalf("duplicate item %d", data)
+				}
+				seen[data] = true
+				count++
+				return true
+			},
+		)
+		if count != expect {
+			t.Fatalf("%v %v %v: expected %d, got %d",
+				lat, lon, meters, expect, count)
+		}
+	}
+	check(33.4, -112.0, 1000000)
+	check(0, 179.5, 2000000)  // antimeridian
+	check(0, -179.5, 2000000) // antimeridian
+	check(88, 10, 1000000)    // north pole
+	check(-89, -100, 500000)  // south pole
+	check(45, 0, 30000000)    // whole world
+	for i := 0; i < 100; i++ {
+		check(rand.Float64()*180-90, rand.Float64()*360-180,
+			rand.Float64()*3000000)
+	}
+}
+
+func TestGeoDist(t *testing.T) {
+	r := rect[float64]{[2]float64{10, 10}, [2]float64{20, 20}}
+	for i := 0; i < 1000; i++ {
+		lat, lon := rand.Float64()*180-90, rand.Float64()*360-180
+		d := geoDist(lat, lon, &r)
+		// brute force the nearest point on the rect
+		for j := 0; j < 1000; j++ {
+			plat := r.min[1] + rand.Float64()*(r.max[1]-r.min[1])
+			plon := r.min[0] + rand.Float64()*(r.max[0]-r.min[0])
+			pd := haversine(lat*radians, lon*radians,
+				plat*radians, plon*radians)
+			if pd < d-0.001 {
+				t.Fatalf("%v %v: %v < %v", lat, lon, pd, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "strings"
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeohashBounds returns the bounding rectangle of a geohash cell, where X is
+// longitude and Y is latitude in degrees, or false if the geohash contains
+// an invalid character. Upper case characters are allowed. An empty geohash
+// is the whole world.
+func GeohashBounds(hash string) (min, max [2]float64, ok bool) {
+	min, max = [2]float64{-180, -90}, [2]float64{180, 90}
+	axis := 0 // the bits alternate between longitude and latitude
+	for i := 0; i < len(hash); i++ {
+		v := strings.IndexByte(geohashAlphabet, lower(hash[i]))
+		if v == -1 {
+			return min, max, false
+		}
+		for bit := 4; bit >= 0; bit-- {
+			mid := (min[axis] + max[axis]) / 2
+			if v>>bit&1 == 1 {
+				min[axis] = mid
+			} else {
+				max[axis] = mid
+			}
+			axis ^= 1
+		}
+	}
+	return min, max, true
+}
+
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// QuadkeyBounds returns the bounding rectangle of the Web Mercator tile with
+// the quadkey, where X is longitude and Y is latitude in degrees, or false if
+// the quadkey is invalid. Each digit of a quadkey, from 0 to 3, selects a
+// quarter of the previous tile, which makes the zoom level the length of the
+// quadkey. An empty quadkey is the whole world.
+func QuadkeyBounds(key string) (min, max [2]float64, ok bool) {
+	if len(key) > 32 {
+		return min, max, false
+	}
+	var x, y uint32
+	for i := 0; i < len(key); i++ {
+		d := key[i] - '0'
+		if d > 3 {
+			return min, max, false
+		}
+		x = x<<1 | uint32(d&1)
+		y = y<<1 | uint32(d>>1)
+	}
+	min, max = TileBounds(uint32(len(key)), x, y)
+	return min, max, true
+}
+
+// SearchGeohash searches for items in the tree that intersect the geohash
+// cell, where X is longitude and Y is latitude in degrees.
+// Returns false if the geohash is invalid.
+func (tr *RTreeG[T]) SearchGeohash(hash string,
+	iter func(min, max [2]float64, data T) bool,
+) bool {
+	min, max, ok := GeohashBounds(hash)
+	if ok {
+		tr.Search(min, max, iter)
+	}
+	return ok
+}
+
+// SearchQuadkey searches for items in the tree that intersect the Web
+// Mercator tile with the quadkey, where X is longitude and Y is latitude in
+// degrees. Returns false if the quadkey is invalid.
+func (tr *RTreeG[T]) SearchQuadkey(key string,
+	iter func(min, max [2]float64, data T) bool,
+) bool {
+	min, max, ok := QuadkeyBounds(key)
+	if ok {
+		tr.Search(min, max, iter)
+	}
+	return ok
+}
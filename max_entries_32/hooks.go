@@ -0,0 +1,38 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Hooks are functions that are called for internal events of a tree, which
+// are useful for exporting metrics about how the tree behaves, such as how
+// often nodes are split or copied. Any of the functions may be nil.
+//
+// The functions are called while the tree is being modified and must not
+// access the tree. OnNodeAlloc may be called concurrently by
+// LoadBulkParallel.
+type Hooks struct {
+	// OnSplit is called when a full node is split into two.
+	OnSplit func(leaf bool)
+	// OnReinsert is called when the items of a removed node are inserted
+	// into the tree again.
+	OnReinsert func(items int)
+	// OnCopyOnWrite is called when a node that is shared with a copy of the
+	// tree is copied before it's modified.
+	OnCopyOnWrite func(leaf bool)
+	// OnNodeAlloc is called when a new node is allocated.
+	OnNodeAlloc func(leaf bool)
+}
+
+// SetHooks sets the hooks that are called for internal events of the tree.
+// Passing nil removes the hooks.
+// Copies of the tree share the same hooks.
+func (tr *RTreeGN[N, T]) SetHooks(hooks *Hooks) {
+	tr.hooks = hooks
+}
+
+// SetHooks sets the hooks that are called for internal events of the tree.
+// Passing nil removes the hooks.
+func (tr *RTreeG[T]) SetHooks(hooks *Hooks) {
+	tr.base.SetHooks(hooks)
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// height returns the number of levels below and including the node.
+func (n *node[N, T]) height() int {
+	h := 1
+	for !n.leaf() {
+		n = n.children()[0]
+		h++
+	}
+	return h
+}
+
+// boxDist2 is like boxDist but returns the squared distance as a float64,
+// which cannot overflow for integer types.
+func (r *rect[N]) boxDist2(b *rect[N]) float64 {
+	var dist float64
+	d := float64(fmax(r.min[0], b.min[0])) - float64(fmin(r.max[0], b.max[0]))
+	if d > 0 {
+		dist += d * d
+	}
+	d = float64(fmax(r.min[1], b.min[1])) - float64(fmin(r.max[1], b.max[1]))
+	if d > 0 {
+		dist += d * d
+	}
+	return dist
+}
+
+// JoinWithin finds all pairs of items, one from this tree and one from the
+// other tree, whose rectangles are within maxDist of each other.
+// The distance between two rectangles is the shortest distance between any of
+// their points, which is zero when they intersect.
+// Both trees are walked at the same time, and pairs of nodes that are farther
+// apart than maxDist are skipped along with everything below them.
+func (tr *RTreeGN[N, T]) JoinWithin(other *RTreeGN[N, T], maxDist N,
+	iter func(aMin, aMax [2]N, a T, bMin, bMax [2]N, b T) bool,
+) {
+	if tr.root == nil || other.root == nil {
+		return
+	}
+	max2 := float64(maxDist) * float64(maxDist)
+	if tr.rect.boxDist2(&other.rect) > max2 {
+		return
+	}
+	gen, ogen := tr.gen, other.gen
+	joinWithin(tr.root, tr.root.height(), other.root, other.root.height(),
+		max2, func(aMin, aMax [2]N, a T, bMin, bMax [2]N, b T) bool {
+			if !iter(aMin, aMax, a, bMin, bMax, b) {
+				return false
+			}
+			if tr.gen != gen || other.gen != ogen {
+				panic(errModified)
+			}
+			return true
+		},
+	)
+}
+
+// joinWithin finds all pairs of items within the distance between two
+// nodes. The taller node is descended first until both are at the same
+// height.
+func joinWithin[N numeric, T any](a *node[N, T], ah int, b *node[N, T],
+	bh int, max2 float64,
+	iter func(aMin, aMax [2]N, a T, bMin, bMax [2]N, b T) bool,
+) bool {
+	switch {
+	case ah > bh:
+		br := b.rect()
+		children := a.children()
+		for i := 0; i < int(a.count); i++ {
+			if ar := a.rects.at(i); ar.boxDist2(&br) <= max2 {
+				if !joinWithin(children[i], ah-1, b, bh, max2, iter) {
+					return false
+				}
+			}
+		}
+	case bh > ah:
+		ar := a.rect()
+		children := b.children()
+		for j := 0; j < int(b.count); j++ {
+			if br := b.rects.at(j); ar.boxDist2(&br) <= max2 {
+				if !joinWithin(a, ah, children[j], bh-1, max2, iter) {
+					return false
+				}
+			}
+		}
+	case a.leaf():
+		aitems := a.items()
+		bitems := b.items()
+		for i := 0; i < int(a.count); i++ {
+			ar := a.rects.at(i)
+			for j := 0; j < int(b.count); j++ {
+				if br := b.rects.at(j); ar.boxDist2(&br) <= max2 {
+					if !iter(ar.min, ar.max, aitems[i],
+						br.min, br.max, bitems[j]) {
+						return false
+					}
+				}
+			}
+		}
+	default:
+		achildren := a.children()
+		bchildren := b.children()
+		for i := 0; i < int(a.count); i++ {
+			ar := a.rects.at(i)
+			for j := 0; j < int(b.count); j++ {
+				if br := b.rects.at(j); ar.boxDist2(&br) <= max2 {
+					if !joinWithin(achildren[i], ah-1, bchildren[j], bh-1,
+						max2, iter) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// JoinWithin finds all pairs of items, one from this tree and one from the
+// other tree, whose rectangles are within maxDist of each other.
+func (tr *RTreeG[T]) JoinWithin(other *RTreeG[T], maxDist float64,
+	iter func(aMin, aMax [2]float64, a T, bMin, bMax [2]float64, b T) bool,
+) {
+	tr.base.JoinWithin(&other.base, maxDist, iter)
+}
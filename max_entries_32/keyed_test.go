@@ -0,0 +1,143 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type keyedVehicle struct {
+	id   string
+	name string
+}
+
+func TestKeyedRTree(t *testing.T) {
+	tr := NewKeyedRTreeGN[float64](func(v keyedVehicle) string {
+		return v.id
+	})
+	rects := make(map[string]rect[float64])
+	for i := 0; i < 5000; i++ {
+		v := keyedVehicle{id: string(rune('a'+i%26)) + string(rune(i)),
+			name: "vehicle"}
+		r := randRect('p')
+		if !tr.Insert(r.min, r.max, v) {
+			t.Fatalf("failed to insert %q", v.id)
+		}
+		rects[v.id] = r
+	}
+	if tr.Insert([2]float64{}, [2]float64{}, keyedVehicle{id: "a\x00"}) {
+		t.Fatal("expected a duplicate key to not be inserted")
+	}
+	if tr.Len() != len(rects) {
+		t.Fatalf("expected %d, got %d", len(rects), tr.Len())
+	}
+	tr2 := tr.Copy()
+	// move every vehicle
+	for id := range rects {
+		r := randRect('p')
+		if !tr.UpdateByKey(id, r.min, r.max) {
+			t.Fatalf("failed to update %q", id)
+		}
+		rects[id] = r
+	}
+	for id, r := range rects {
+		min, max, v, ok := tr.GetByKey(id)
+		if !ok || v.id != id || v.name != "vehicle" || min != r.min ||
+			max != r.max {
+			t.Fatalf("unexpected item for %q", id)
+		}
+		var found bool
+		tr.Search(r.min, r.max, func(_, _ [2]float64, v keyedVehicle) bool {
+			found = found || v.id == id
+			return !found
+		})
+		if !found {
+			t.Fatalf("%q not found", id)
+		}
+	}
+	if tr.Len() != len(rects) {
+		t.Fatalf("expected %d, got %d", len(rects), tr.Len())
+	}
+	if err := rSane(&RTreeG[keyedVehicle]{tr.base}); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	for id := range rects {
+		if rand.Intn(2) == 0 {
+			continue
+		}
+		if !tr.DeleteByKey(id) || tr.DeleteByKey(id) {
+			t.Fatalf("failed to delete %q", id)
+		}
+		if _, _, _, ok := tr.GetByKey(id); ok {
+			t.Fatalf("%q was not deleted", id)
+		}
+		delete(rects, id)
+		n++
+	}
+	var count int
+	tr.Scan(func(min, max [2]float64, v keyedVehicle) bool {
+		if _, ok := rects[v.id]; !ok {
+			t.Fatalf("%q was not deleted", v.id)
+		}
+		count++
+		return true
+	})
+	if count != len(rects) || tr.Len() != count {
+		t.Fatalf("expected %d, got %d", len(rects), count)
+	}
+	if tr.UpdateByKey("missing", [2]float64{}, [2]float64{}) {
+		t.Fatal("expected no item")
+	}
+	// the copy is not affected
+	if tr2.Len() != len(rects)+n {
+		t.Fatalf("expected %d, got %d", len(rects)+n, tr2.Len())
+	}
+	tr2.Clear()
+	if _, _, _, ok := tr2.GetByKey("a\x00"); ok || tr2.Len() != 0 {
+		t.Fatal("expected an empty tree")
+	}
+}
+
+func TestKeyedRTreeUpsert(t *testing.T) {
+	tr := NewKeyedRTreeGN[float64](func(v keyedVehicle) string {
+		return v.id
+	})
+	rects := make(map[string]rect[float64])
+	names := make(map[string]string)
+	for i := 0; i < 20000; i++ {
+		v := keyedVehicle{id: string(rune('a' + rand.Intn(26*26))),
+			name: string(rune('A' + i%26))}
+		r := randRect('p')
+		_, exists := rects[v.id]
+		if tr.Upsert(r.min, r.max, v) != exists {
+			t.Fatalf("expected %v for %q", exists, v.id)
+		}
+		rects[v.id] = r
+		names[v.id] = v.name
+	}
+	if tr.Len() != len(rects) {
+		t.Fatalf("expected %d, got %d", len(rects), tr.Len())
+	}
+	// same rect, new data
+	for id, r := range rects {
+		tr.Upsert(r.min, r.max, keyedVehicle{id: id, name: "parked"})
+		names[id] = "parked"
+		break
+	}
+	var count int
+	tr.Scan(func(min, max [2]float64, v keyedVehicle) bool {
+		r := rects[v.id]
+		if min != r.min || max != r.max || v.name != names[v.id] {
+			t.Fatalf("unexpected item for %q", v.id)
+		}
+		count++
+		return true
+	})
+	if count != len(rects) {
+		t.Fatalf("expected %d, got %d", len(rects), count)
+	}
+}
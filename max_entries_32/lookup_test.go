@@ -0,0 +1,46 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestExists(t *testing.T) {
+	var tr RTreeG[int]
+	if tr.Exists([2]float64{1, 1}, [2]float64{2, 2}, 1) {
+		t.Fatal("expected false")
+	}
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for i := range rects {
+		if !tr.Exists(rects[i].min, rects[i].max, i) {
+			t.Fatalf("item %d not found", i)
+		}
+		if tr.Exists(rects[i].min, rects[i].max, -i-1) {
+			t.Fatalf("unexpected item %d", -i-1)
+		}
+		data, ok := tr.GetEqual(rects[i].min, rects[i].max, nil)
+		if !ok || data != i {
+			t.Fatalf("expected %d, got %d", i, data)
+		}
+	}
+	// contained, but not equal
+	r := rects[0]
+	r.max[0] += 1
+	tr.Insert(r.min, r.max, -1)
+	if _, ok := tr.GetEqual(r.min, r.max, func(data int) bool {
+		return data == 0
+	}); ok {
+		t.Fatal("expected false")
+	}
+	data, ok := tr.GetEqual(r.min, r.max, func(data int) bool {
+		return data < 0
+	})
+	if !ok || data != -1 {
+		t.Fatalf("expected %d, got %d", -1, data)
+	}
+}
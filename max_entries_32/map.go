@@ -0,0 +1,79 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Map returns a new tree with the same rectangles and structure as the
+// provided tree, where the data of each item is transformed by f. This is
+// much faster than inserting the transformed items into a new tree, because
+// the nodes are copied as they are rather than rebuilt.
+//
+// The new tree keeps the options of the tree that don't depend on the type
+// of the data, such as ordering, min fill and limits. Hooks, aggregators,
+// splitters, allocators, codecs and comparators are not kept.
+// The f function must not modify the tree.
+func Map[N numeric, T, U any](tr *RTreeGN[N, T],
+	f func(min, max [2]N, data T) U,
+) *RTreeGN[N, U] {
+	tr2 := &RTreeGN[N, U]{
+		count:         tr.count,
+		seq:           tr.seq,
+		rect:          tr.rect,
+		strict:        tr.strict,
+		ordered:       tr.ordered,
+		eps:           tr.eps,
+		choose:        tr.choose,
+		unordered:     tr.unordered,
+		minFill:       tr.minFill,
+		leafFanout:    tr.leafFanout,
+		branchFanout:  tr.branchFanout,
+		deterministic: tr.deterministic,
+		maxItems:      tr.maxItems,
+		maxMemory:     tr.maxMemory,
+	}
+	if tr.root != nil {
+		gen := tr.gen
+		tr2.initPool()
+		tr2.root = mapNode(tr2, tr.root, func(min, max [2]N, data T) U {
+			data2 := f(min, max, data)
+			if tr.gen != gen {
+				panic(errModified)
+			}
+			return data2
+		})
+	}
+	return tr2
+}
+
+func mapNode[N numeric, T, U any](tr *RTreeGN[N, U], n *node[N, T],
+	f func(min, max [2]N, data T) U,
+) *node[N, U] {
+	n2 := tr.newNode(n.leaf())
+	n2.unordered = n.unordered
+	n2.count = n.count
+	n2.rects = n.rects
+	if n.leaf() {
+		items, items2 := n.items(), n2.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			items2[i] = f(r.min, r.max, items[i])
+		}
+		if seqs := n.seqs(); seqs != nil {
+			copy(n2.allocSeqs(), seqs)
+		}
+	} else {
+		children, children2 := n.children(), n2.children()
+		for i := 0; i < int(n.count); i++ {
+			children2[i] = mapNode(tr, children[i], f)
+		}
+	}
+	return n2
+}
+
+// MapG returns a new tree where the data of each item is transformed by f.
+// See Map.
+func MapG[T, U any](tr *RTreeG[T], f func(min, max [2]float64, data T) U,
+) *RTreeG[U] {
+	return &RTreeG[U]{base: *Map(&tr.base, f)}
+}
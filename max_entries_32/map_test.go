@@ -0,0 +1,79 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	var tr RTreeG[int]
+	tr.base.SetInsertionOrder(true)
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	tr2 := MapG(&tr, func(min, max [2]float64, data int) string {
+		return strconv.Itoa(data)
+	})
+	if err := rSane(tr2); err != nil {
+		t.Fatal(err)
+	}
+	if tr2.Len() != tr.Len() {
+		t.Fatalf("expected %d, got %d", tr.Len(), tr2.Len())
+	}
+	var expect, got []string
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		expect = append(expect, strconv.Itoa(data))
+		return true
+	})
+	tr2.Scan(func(min, max [2]float64, data string) bool {
+		got = append(got, data)
+		return true
+	})
+	if !slices.Equal(got, expect) {
+		t.Fatal("mismatch")
+	}
+	// the insertion order is kept
+	got = got[:0]
+	tr2.base.ScanByInsertionOrder(func(min, max [2]float64, data string) bool {
+		got = append(got, data)
+		return true
+	})
+	for i := range got {
+		if got[i] != strconv.Itoa(i) {
+			t.Fatalf("expected %d, got %s", i, got[i])
+		}
+	}
+	// the new tree is independent of the original
+	var min, max [2]float64
+	var data int
+	tr.Scan(func(amin, amax [2]float64, adata int) bool {
+		min, max, data = amin, amax, adata
+		return false
+	})
+	tr.Delete(min, max, data)
+	if !tr2.base.Exists(min, max, strconv.Itoa(data)) {
+		t.Fatal("expected item to exist")
+	}
+	tr2.Insert([2]float64{1, 1}, [2]float64{2, 2}, "new")
+	if err := rSane(tr2); err != nil {
+		t.Fatal(err)
+	}
+	var empty RTreeG[int]
+	if MapG(&empty, func(min, max [2]float64, data int) int {
+		return data
+	}).Len() != 0 {
+		t.Fatal("expected an empty tree")
+	}
+	expectPanic(t, func() {
+		MapG(&tr, func(min, max [2]float64, data int) int {
+			tr.Delete(min, max, data)
+			return data
+		})
+	})
+}
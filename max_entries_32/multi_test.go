@@ -0,0 +1,82 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func multiValues(tr *MultiRTreeGN[float64, int], r rect[float64]) []int {
+	var res []int
+	tr.Search(r.min, r.max, func(min, max [2]float64, data int) bool {
+		if min == r.min && max == r.max {
+			res = append(res, data)
+		}
+		return true
+	})
+	return res
+}
+
+func TestMultiRTree(t *testing.T) {
+	var tr MultiRTreeGN[float64, int]
+	rects := make([]rect[float64], 100)
+	for i := range rects {
+		rects[i] = randRect('r')
+	}
+	for i := 0; i < 10000; i++ {
+		r := rects[i%len(rects)]
+		tr.Insert(r.min, r.max, i)
+	}
+	if tr.Len() != 10000 || tr.Rects() != len(rects) {
+		t.Fatalf("expected %d/%d, got %d/%d", 10000, len(rects),
+			tr.Len(), tr.Rects())
+	}
+	vals := multiValues(&tr, rects[3])
+	if len(vals) != 100 || vals[0] != 3 || vals[99] != 9903 {
+		t.Fatalf("unexpected values %v", vals)
+	}
+	var count int
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		count++
+		return true
+	})
+	if count != 10000 {
+		t.Fatalf("expected %d, got %d", 10000, count)
+	}
+
+	// copies don't share changes
+	tr2 := tr.Copy()
+	if !tr.Delete(rects[3].min, rects[3].max, 503) {
+		t.Fatal("expected delete")
+	}
+	if tr.Delete(rects[3].min, rects[3].max, 503) {
+		t.Fatal("unexpected delete")
+	}
+	tr.Insert(rects[3].min, rects[3].max, -1)
+	tr2.Insert(rects[3].min, rects[3].max, -2)
+	vals = multiValues(&tr, rects[3])
+	if len(vals) != 100 || vals[5] != 603 || vals[99] != -1 {
+		t.Fatalf("unexpected values %v", vals)
+	}
+	vals = multiValues(tr2, rects[3])
+	if len(vals) != 101 || vals[5] != 503 || vals[100] != -2 {
+		t.Fatalf("unexpected values %v", vals)
+	}
+
+	// delete everything
+	for i := 0; i < 10000; i++ {
+		r := rects[i%len(rects)]
+		tr2.Delete(r.min, r.max, i)
+	}
+	tr2.Delete(rects[3].min, rects[3].max, -2)
+	if tr2.Len() != 0 || tr2.Rects() != 0 {
+		t.Fatalf("expected empty tree, got %d/%d", tr2.Len(), tr2.Rects())
+	}
+	if tr.Len() != 10000 {
+		t.Fatalf("expected %d, got %d", 10000, tr.Len())
+	}
+	tr.Clear()
+	if tr.Len() != 0 || tr.Rects() != 0 {
+		t.Fatalf("expected empty tree, got %d/%d", tr.Len(), tr.Rects())
+	}
+}
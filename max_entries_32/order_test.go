@@ -0,0 +1,87 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScanByInsertionOrder(t *testing.T) {
+	var tr RTreeG[int]
+	tr.ScanByInsertionOrder(func(min, max [2]float64, data int) bool {
+		t.Fatal("expected no items")
+		return true
+	})
+	// items without a sequence number come first
+	for i := -10; i < 0; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	if tr.InsertionOrder() {
+		t.Fatal("expected false")
+	}
+	tr.SetInsertionOrder(true)
+	if !tr.InsertionOrder() {
+		t.Fatal("expected true")
+	}
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	// delete every third and replace every tenth, which moves them to the end
+	var expect []int
+	var replaced []int
+	for i := range rects {
+		switch {
+		case i%3 == 0:
+			tr.Delete(rects[i].min, rects[i].max, i)
+		case i%10 == 1:
+			tr.Replace(rects[i].min, rects[i].max, i,
+				rects[i].min, rects[i].max, i)
+			replaced = append(replaced, i)
+		default:
+			expect = append(expect, i)
+		}
+	}
+	expect = append(expect, replaced...)
+	var items []Item[float64, int]
+	for i := 5000; i < 6000; i++ {
+		r := randRect('r')
+		items = append(items, Item[float64, int]{r.min, r.max, i})
+		expect = append(expect, i)
+	}
+	tr.LoadBulk(items)
+	if err := tr.SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+	tr2 := tr.Copy()
+	tr2.Compact(1)
+	for _, tr := range []*RTreeG[int]{&tr, tr2} {
+		var got []int
+		tr.ScanByInsertionOrder(func(min, max [2]float64, data int) bool {
+			got = append(got, data)
+			return true
+		})
+		first := got[:10]
+		slices.Sort(first)
+		if first[0] != -10 || first[9] != -1 {
+			t.Fatalf("expected the untracked items first, got %v", first)
+		}
+		if !slices.Equal(got[10:], expect) {
+			t.Fatal("mismatch")
+		}
+	}
+	var count int
+	tr.ScanByInsertionOrder(func(min, max [2]float64, data int) bool {
+		count++
+		tr.Delete(min, max, data)
+		return count < 20
+	})
+	if count != 20 || tr.Len() != 10+len(expect)-20 {
+		t.Fatalf("expected 20, got %d", count)
+	}
+}
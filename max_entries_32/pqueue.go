@@ -0,0 +1,68 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// pqueue is a min priority queue of elements ordered by a float64 priority.
+// Elements with the same priority are popped in the order that they were
+// pushed, so that the order doesn't depend on the other elements.
+type pqueue[E any] struct {
+	items []pqitem[E]
+	seq   uint64
+}
+
+type pqitem[E any] struct {
+	prio float64
+	seq  uint64
+	elem E
+}
+
+func (a *pqitem[E]) less(b *pqitem[E]) bool {
+	return a.prio < b.prio || a.prio == b.prio && a.seq < b.seq
+}
+
+func (q *pqueue[E]) push(prio float64, elem E) {
+	q.seq++
+	q.items = append(q.items, pqitem[E]{prio, q.seq, elem})
+	items := q.items
+	i := len(items) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !items[i].less(&items[parent]) {
+			break
+		}
+		items[parent], items[i] = items[i], items[parent]
+		i = parent
+	}
+}
+
+func (q *pqueue[E]) pop() (prio float64, elem E, ok bool) {
+	items := q.items
+	if len(items) == 0 {
+		return 0, elem, false
+	}
+	top := items[0]
+	items[0] = items[len(items)-1]
+	items[len(items)-1] = pqitem[E]{}
+	items = items[:len(items)-1]
+	q.items = items
+	i := 0
+	for {
+		smallest := i
+		left := i*2 + 1
+		right := i*2 + 2
+		if left < len(items) && items[left].less(&items[smallest]) {
+			smallest = left
+		}
+		if right < len(items) && items[right].less(&items[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			break
+		}
+		items[smallest], items[i] = items[i], items[smallest]
+		i = smallest
+	}
+	return top.prio, top.elem, true
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// QueryProfile describes the work that was done by a search.
+type QueryProfile struct {
+	Nodes  []int // nodes visited at each level, starting at the root
+	Rects  int   // node entries whose rect was compared to the target
+	Leaves int   // leaf nodes visited
+	Items  int   // items sent to iter
+}
+
+// SearchProfiled is like Search, but also returns a profile of the work that
+// was done to find the items. The profile can be used to measure how well the
+// tree fits a query mix, such as when tuning maxEntries or the split strategy.
+func (tr *RTreeGN[N, T]) SearchProfiled(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) QueryProfile {
+	var p QueryProfile
+	target := rect[N]{min, max}
+	if tr.root == nil || !target.intersects(&tr.rect) {
+		return p
+	}
+	tr.root.searchProfiled(&target, 0, &p, tr.guard(iter))
+	return p
+}
+
+func (n *node[N, T]) searchProfiled(target *rect[N], depth int,
+	p *QueryProfile, iter func(min, max [2]N, data T) bool,
+) bool {
+	if depth == len(p.Nodes) {
+		p.Nodes = append(p.Nodes, 0)
+	}
+	p.Nodes[depth]++
+	if n.leaf() {
+		p.Leaves++
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			if n.ordered() && n.rects.min[0][i] > target.max[0] {
+				break
+			}
+			p.Rects++
+			if r := n.rects.at(i); r.intersects(target) {
+				p.Items++
+				if !iter(r.min, r.max, items[i]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		if n.ordered() && n.rects.min[0][i] > target.max[0] {
+			break
+		}
+		p.Rects++
+		if r := n.rects.at(i); target.intersects(&r) {
+			if !children[i].searchProfiled(target, depth+1, p, iter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SearchProfiled is like Search, but also returns a profile of the work that
+// was done to find the items.
+func (tr *RTreeG[T]) SearchProfiled(min, max [2]float64,
+	iter func(min, max [2]float64, data T) bool,
+) QueryProfile {
+	return tr.base.SearchProfiled(min, max, iter)
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestProjections(t *testing.T) {
+	for _, proj := range []Projection{
+		WebMercator(), Equirectangular(0), Equirectangular(45),
+	} {
+		for i := 0; i < 1000; i++ {
+			p := LatLon{rand.Float64()*170 - 85, rand.Float64()*360 - 180}
+			q := proj.Unproject(proj.Project(p))
+			if math.Abs(p.Lat-q.Lat) > 1e-9 || math.Abs(p.Lon-q.Lon) > 1e-9 {
+				t.Fatalf("%T: expected %v, got %v", proj, p, q)
+			}
+		}
+	}
+	// one degree of longitude at the equator
+	xy := WebMercator().Project(LatLon{0, 1})
+	if math.Abs(xy[0]-111319.49) > 0.01 || xy[1] != 0 {
+		t.Fatalf("unexpected %v", xy)
+	}
+	xy = WebMercator().Project(LatLon{90, 180})
+	if math.Abs(xy[0]-xy[1]) > 1e-6 {
+		t.Fatalf("expected a square world, got %v", xy)
+	}
+	xy = Equirectangular(60).Project(LatLon{60, 1})
+	if math.Abs(xy[0]-earthRadius*radians/2) > 1e-6 {
+		t.Fatalf("unexpected %v", xy)
+	}
+}
+
+func TestGeoTreeProjected(t *testing.T) {
+	var tr GeoTree[int]
+	if _, ok := tr.Projection().(webMercator); !ok {
+		t.Fatal("expected web mercator")
+	}
+	proj := WebMercator()
+	var pts [][2]float64
+	for i := 0; i < 5000; i++ {
+		p := proj.Project(LatLon{rand.Float64()*170 - 85,
+			rand.Float64()*360 - 180})
+		if err := tr.InsertProjected(p, p, i); err != nil {
+			t.Fatal(err)
+		}
+		pts = append(pts, p)
+	}
+	// the edges of the projected world
+	world := proj.Project(LatLon{90, 180})
+	if err := tr.InsertProjected([2]float64{-world[0], -world[1]}, world,
+		-1); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.InsertProjected([2]float64{0, 0},
+		[2]float64{world[0] * 2, 0}, 0); err != ErrInvalidLatLon {
+		t.Fatalf("expected %v, got %v", ErrInvalidLatLon, err)
+	}
+	// Searching within a radius in meters of a projected point matches the
+	// great-circle distance, which differs from the projected distance.
+	center := proj.Project(LatLon{60, 10})
+	meters := 500000.0
+	c := proj.Unproject(center)
+	var expect, count int
+	for _, p := range pts {
+		q := proj.Unproject(p)
+		if haversine(c.Lat*radians, c.Lon*radians, q.Lat*radians,
+			q.Lon*radians) <= meters {
+			expect++
+		}
+	}
+	tr.SearchRadiusMetersProjected(center, meters,
+		func(min, max [2]float64, data int) bool {
+			if data >= 0 {
+				count++
+			}
+			return true
+		})
+	if count != expect || expect == 0 {
+		t.Fatalf("expected %d, got %d", expect, count)
+	}
+	count = 0
+	tr.SearchProjected([2]float64{0, 0}, world,
+		func(min, max [2]float64, data int) bool {
+			if data >= 0 {
+				if math.Abs(min[0]-pts[data][0]) > 1e-6 ||
+					math.Abs(min[1]-pts[data][1]) > 1e-6 {
+					t.Fatalf("expected %v, got %v", pts[data], min)
+				}
+				count++
+			}
+			return true
+		})
+	expect = 0
+	for _, p := range pts {
+		if p[0] >= 0 && p[1] >= 0 {
+			expect++
+		}
+	}
+	if count != expect {
+		t.Fatalf("expected %d, got %d", expect, count)
+	}
+	for i := 0; i < len(pts); i += 2 {
+		tr.DeleteProjected(pts[i], pts[i], i)
+	}
+	if tr.Len() != len(pts)/2+1 {
+		t.Fatalf("expected %d, got %d", len(pts)/2+1, tr.Len())
+	}
+	tr.SetProjection(Equirectangular(0))
+	if _, ok := tr.Projection().(equirectangular); !ok {
+		t.Fatal("expected equirectangular")
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestQuadraticSplitter(t *testing.T) {
+	testSplitter(t, QuadraticSplitter[float64, int]())
+
+	// two clusters of long and thin rects, one horizontal and one vertical,
+	// which cross the middle of each other's bounds
+	s := QuadraticSplitter[float64, int]()
+	var entries []Item[float64, int]
+	for i := 0; i < 5; i++ {
+		y := float64(i)
+		entries = append(entries, Item[float64, int]{
+			Min: [2]float64{0, y}, Max: [2]float64{100, y + 0.1}, Data: 0,
+		})
+		x := 50 + float64(i)
+		entries = append(entries, Item[float64, int]{
+			Min: [2]float64{x, 0}, Max: [2]float64{x + 0.1, 100}, Data: 1,
+		})
+	}
+	right := make([]bool, len(entries))
+	s.Split(true, [2]float64{0, 0}, [2]float64{100, 100}, entries, right)
+	for i := range entries {
+		if right[i] != right[entries[i].Data] {
+			t.Fatalf("expected the clusters to be split apart, got %v",
+				right)
+		}
+	}
+	if right[0] == right[1] {
+		t.Fatal("expected both clusters to be in different nodes")
+	}
+}
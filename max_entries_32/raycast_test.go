@@ -0,0 +1,73 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRaycastNearest(t *testing.T) {
+	var tr RTreeG[int]
+	if _, _, ok := tr.RaycastNearest([2]float64{0, 0}, [2]float64{1, 0}); ok {
+		t.Fatal("expected no hit")
+	}
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for j := 0; j < 100; j++ {
+		o := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		a := rand.Float64() * 2 * math.Pi
+		d := [2]float64{math.Cos(a) * 3, math.Sin(a) * 3}
+		if j == 0 {
+			d = [2]float64{-2, 0}
+		}
+		data, dist, ok := tr.RaycastNearest(o, d)
+		expect := math.Inf(1)
+		for i := range rects {
+			t0, _, hit := clipLine(o, d, &rects[i], 0, math.Inf(1))
+			if hit && t0 < expect {
+				expect = t0
+			}
+		}
+		if math.IsInf(expect, 1) {
+			if ok {
+				t.Fatalf("expected no hit, got %d", data)
+			}
+			continue
+		}
+		if !ok {
+			t.Fatal("expected a hit")
+		}
+		scale := math.Hypot(d[0], d[1])
+		if math.Abs(dist-expect*scale) > 1e-9 {
+			t.Fatalf("expected %v, got %v", expect*scale, dist)
+		}
+		t0, _, hit := clipLine(o, d, &rects[data], 0, math.Inf(1))
+		if !hit || t0 != expect {
+			t.Fatalf("item %d is not the nearest hit", data)
+		}
+	}
+
+	// origin inside of an item
+	var tr2 RTreeG[string]
+	tr2.Insert([2]float64{0, 0}, [2]float64{10, 10}, "inside")
+	tr2.Insert([2]float64{20, 0}, [2]float64{30, 10}, "right")
+	data, dist, ok := tr2.RaycastNearest([2]float64{5, 5}, [2]float64{1, 0})
+	if !ok || data != "inside" || dist != 0 {
+		t.Fatalf("got %v %v %v", data, dist, ok)
+	}
+	data, dist, ok = tr2.RaycastNearest([2]float64{15, 5}, [2]float64{1, 0})
+	if !ok || data != "right" || dist != 5 {
+		t.Fatalf("got %v %v %v", data, dist, ok)
+	}
+	_, _, ok = tr2.RaycastNearest([2]float64{15, 5}, [2]float64{0, 1})
+	if ok {
+		t.Fatal("expected no hit")
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"math/bits"
+)
+
+// The S2 cell id decoding below follows the reference implementation of the
+// S2 geometry library, where a cell id holds the cube face in the top three
+// bits followed by the position of the cell along the Hilbert curve of that
+// face.
+
+const (
+	s2MaxLevel   = 30
+	s2PosBits    = 2*s2MaxLevel + 1
+	s2LookupBits = 4
+	s2SwapMask   = 1
+	s2InvertMask = 2
+)
+
+var (
+	s2PosToIJ = [4][4]int{
+		{0, 1, 3, 2}, // canonical order
+		{0, 2, 3, 1}, // axes swapped
+		{3, 2, 0, 1}, // bits inverted
+		{3, 1, 0, 2}, // swapped & inverted
+	}
+	s2PosToOrientation = [4]int{s2SwapMask, 0, 0, s2InvertMask | s2SwapMask}
+	s2LookupIJ         [1 << (2*s2LookupBits + 2)]int
+)
+
+func init() {
+	s2InitLookupCell(0, 0, 0, 0, 0, 0)
+	s2InitLookupCell(0, 0, 0, s2SwapMask, 0, s2SwapMask)
+	s2InitLookupCell(0, 0, 0, s2InvertMask, 0, s2InvertMask)
+	s2InitLookupCell(0, 0, 0, s2SwapMask|s2InvertMask, 0,
+		s2SwapMask|s2InvertMask)
+}
+
+func s2InitLookupCell(level, i, j, origOrientation, pos, orientation int) {
+	if level == s2LookupBits {
+		ij := (i << s2LookupBits) + j
+		s2LookupIJ[(pos<<2)+origOrientation] = (ij << 2) + orientation
+		return
+	}
+	level++
+	i <<= 1
+	j <<= 1
+	pos <<= 2
+	r := s2PosToIJ[orientation]
+	for k := 0; k < 4; k++ {
+		s2InitLookupCell(level, i+(r[k]>>1), j+(r[k]&1), origOrientation,
+			pos+k, orientation^s2PosToOrientation[k])
+	}
+}
+
+// s2Level returns the level of a cell id, or false if the id is invalid.
+func s2Level(id uint64) (int, bool) {
+	if id>>s2PosBits > 5 || id == 0 {
+		return 0, false
+	}
+	tz := bits.TrailingZeros64(id)
+	if tz%2 != 0 || tz > 2*s2MaxLevel {
+		return 0, false
+	}
+	return s2MaxLevel - tz/2, true
+}
+
+// s2FaceIJ returns the cube face and the i, j position of the leaf cell at
+// the start of the cell.
+func s2FaceIJ(id uint64) (face, i, j int) {
+	face = int(id >> s2PosBits)
+	orientation := face & s2SwapMask
+	nbits := s2MaxLevel - 7*s2LookupBits
+	for k := 7; k >= 0; k-- {
+		orientation += (int(id>>uint(k*2*s2LookupBits+1)) &
+			((1 << uint(2*nbits)) - 1)) << 2
+		orientation = s2LookupIJ[orientation]
+		i += (orientation >> (s2LookupBits + 2)) << uint(k*s2LookupBits)
+		j += ((orientation >> 2) & ((1 << s2LookupBits) - 1)) <<
+			uint(k*s2LookupBits)
+		orientation &= s2SwapMask | s2InvertMask
+		nbits = s2LookupBits
+	}
+	return face, i, j
+}
+
+// s2STToUV converts from the cell space to the face space using the
+// quadratic projection of the S2 library.
+func s2STToUV(s float64) float64 {
+	if s >= 0.5 {
+		return (1 / 3.) * (4*s*s - 1)
+	}
+	return (1 / 3.) * (1 - 4*(1-s)*(1-s))
+}
+
+// s2Point returns the unit vector of a point on a cube face.
+func s2Point(face int, u, v float64) [3]float64 {
+	var p [3]float64
+	switch face {
+	case 0:
+		p = [3]float64{1, u, v}
+	case 1:
+		p = [3]float64{-u, 1, v}
+	case 2:
+		p = [3]float64{-u, -v, 1}
+	case 3:
+		p = [3]float64{-1, -v, -u}
+	case 4:
+		p = [3]float64{v, -1, -u}
+	default:
+		p = [3]float64{v, u, -1}
+	}
+	n := math.Sqrt(p[0]*p[0] + p[1]*p[1] + p[2]*p[2])
+	return [3]float64{p[0] / n, p[1] / n, p[2] / n}
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// pointLat returns the latitude of a vector in degrees.
+func pointLat(p [3]float64) float64 {
+	return math.Atan2(p[2], math.Hypot(p[0], p[1])) * degrees
+}
+
+// arcLatRange expands the latitude range to include the great-circle arc
+// from a to b, whose highest or lowest point may be between the ends.
+func arcLatRange(a, b [3]float64, minLat, maxLat *float64) {
+	n := cross(a, b)
+	// The highest point of the whole great circle is the north pole
+	// projected onto the plane of the circle.
+	top := [3]float64{-n[0] * n[2], -n[1] * n[2], n[0]*n[0] + n[1]*n[1]}
+	if top == ([3]float64{}) {
+		return
+	}
+	for _, p := range [2][3]float64{top, {-top[0], -top[1], -top[2]}} {
+		if dot(cross(a, p), n) > 0 && dot(cross(p, b), n) > 0 {
+			lat := pointLat(p)
+			*minLat = math.Min(*minLat, lat)
+			*maxLat = math.Max(*maxLat, lat)
+		}
+	}
+}
+
+// S2CellBounds returns the bounding rectangle of an S2 cell, where X is
+// longitude and Y is latitude in degrees, or false if the cell id is not
+// valid.
+// Cells that contain a pole or cross the antimeridian span all longitudes.
+func S2CellBounds(id uint64) (min, max [2]float64, ok bool) {
+	level, ok := s2Level(id)
+	if !ok {
+		return min, max, false
+	}
+	face, i, j := s2FaceIJ(id)
+	size := 1 << uint(s2MaxLevel-level)
+	i &= -size
+	j &= -size
+	var corners [4][3]float64
+	for k, ij := range [4][2]int{
+		{i, j}, {i + size, j}, {i + size, j + size}, {i, j + size},
+	} {
+		u := s2STToUV(float64(ij[0]) / (1 << s2MaxLevel))
+		v := s2STToUV(float64(ij[1]) / (1 << s2MaxLevel))
+		corners[k] = s2Point(face, u, v)
+	}
+	minLat, maxLat := 90.0, -90.0
+	minLon, maxLon := 180.0, -180.0
+	for k := range corners {
+		lat := pointLat(corners[k])
+		lon := math.Atan2(corners[k][1], corners[k][0]) * degrees
+		minLat, maxLat = math.Min(minLat, lat), math.Max(maxLat, lat)
+		minLon, maxLon = math.Min(minLon, lon), math.Max(maxLon, lon)
+		arcLatRange(corners[k], corners[(k+1)%4], &minLat, &maxLat)
+	}
+	// The north pole is at the center of face 2 and the south pole at the
+	// center of face 5.
+	center := 1 << (s2MaxLevel - 1)
+	hasPole := (face == 2 || face == 5) &&
+		i <= center && center <= i+size && j <= center && center <= j+size
+	if hasPole {
+		if face == 2 {
+			maxLat = 90
+		} else {
+			minLat = -90
+		}
+	}
+	if hasPole || maxLon-minLon > 180 {
+		minLon, maxLon = -180, 180
+	}
+	return [2]float64{minLon, minLat}, [2]float64{maxLon, maxLat}, true
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SanityCheck validates the structure of the tree and returns an error
+// describing the first problem found, or nil when the tree is healthy.
+// It checks that the item count is correct, that every rectangle is the
+// minimum bounding rectangle of its children, that all leaves are at the
+// same height, that nodes are neither empty nor overfull, and that the
+// rectangles in each node are ordered by their min x.
+//
+// This visits every node in the tree and is intended for tests, such as fuzz
+// tests that apply arbitrary sequences of operations.
+func (tr *RTreeGN[N, T]) SanityCheck() error {
+	if tr.root == nil {
+		if tr.count != 0 {
+			return fmt.Errorf("rtree: nil root with count %d", tr.count)
+		}
+		var empty rect[N]
+		if !tr.rect.equals(&empty) {
+			return errors.New("rtree: nil root with non-zero rect")
+		}
+		return nil
+	}
+	if !tr.root.leaf() && tr.root.count < 2 {
+		return fmt.Errorf("rtree: root branch has %d children",
+			tr.root.count)
+	}
+	height := -1
+	count, err := tr.root.sanityCheck(tr, &tr.rect, 0, &height)
+	if err != nil {
+		return err
+	}
+	if count != tr.count {
+		return fmt.Errorf("rtree: counted %d items, expected %d", count,
+			tr.count)
+	}
+	return nil
+}
+
+func (n *node[N, T]) sanityCheck(tr *RTreeGN[N, T], nr *rect[N], depth int,
+	height *int,
+) (count int, err error) {
+	if n.count < 1 || int(n.count) > tr.fanout(n.leaf()) {
+		return 0, fmt.Errorf("rtree: node at depth %d has %d entries",
+			depth, n.count)
+	}
+	r := n.rect()
+	if !r.equals(nr) {
+		return 0, fmt.Errorf("rtree: node at depth %d has incorrect "+
+			"bounding rect", depth)
+	}
+	rects := &n.rects
+	for i := 0; i < int(n.count); i++ {
+		if rects.min[0][i] > rects.max[0][i] ||
+			rects.min[1][i] > rects.max[1][i] {
+			return 0, fmt.Errorf("rtree: invalid rect at depth %d", depth)
+		}
+		if i > 0 && rects.min[0][i-1] > rects.min[0][i] &&
+			n.ordered() {
+			return 0, fmt.Errorf("rtree: node at depth %d is not ordered",
+				depth)
+		}
+	}
+	if n.leaf() {
+		if *height == -1 {
+			*height = depth
+		} else if *height != depth {
+			return 0, fmt.Errorf("rtree: leaves at depths %d and %d",
+				*height, depth)
+		}
+		if seqs := n.seqs(); seqs != nil {
+			for i := int(n.count); i < maxEntries; i++ {
+				if seqs[i] != 0 {
+					return 0, errors.New("rtree: unused sequence not zero")
+				}
+			}
+		}
+		return int(n.count), nil
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		if children[i] == nil {
+			return 0, fmt.Errorf("rtree: nil child at depth %d", depth)
+		}
+		cr := rects.at(i)
+		c, err := children[i].sanityCheck(tr, &cr, depth+1, height)
+		if err != nil {
+			return 0, err
+		}
+		count += c
+	}
+	for i := int(n.count); i < maxEntries; i++ {
+		if children[i] != nil {
+			return 0, fmt.Errorf("rtree: unused child at depth %d not nil",
+				depth)
+		}
+	}
+	return count, nil
+}
+
+// SanityCheck validates the structure of the tree and returns an error
+// describing the first problem found, or nil when the tree is healthy.
+func (tr *RTreeG[T]) SanityCheck() error {
+	return tr.base.SanityCheck()
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSanityCheck(t *testing.T) {
+	var tr RTreeG[int]
+	if err := tr.SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+	var rects []rect[float64]
+	check := func() {
+		t.Helper()
+		if err := tr.SanityCheck(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 5000; i++ {
+		switch rand.Intn(10) {
+		case 0, 1, 2, 3, 4, 5:
+			r := randRect('r')
+			tr.Insert(r.min, r.max, len(rects))
+			rects = append(rects, r)
+		case 6, 7:
+			if len(rects) > 0 {
+				j := rand.Intn(len(rects))
+				tr.Delete(rects[j].min, rects[j].max, j)
+			}
+		case 8:
+			tr2 := tr.Copy()
+			r := randRect('r')
+			tr2.Insert(r.min, r.max, -1)
+		case 9:
+			if rand.Intn(10) == 0 {
+				tr.ScanDelete(func(min, max [2]float64, data int) bool {
+					return data%3 == 0
+				})
+			}
+		}
+		check()
+	}
+	tr.LoadBulk([]Item[float64, int]{{[2]float64{1, 1}, [2]float64{2, 2}, 1}})
+	check()
+
+	// corrupt the tree
+	for tr.base.Len() < 100 {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, 1)
+	}
+	saved := tr.base.root.rects.at(0)
+	tr.base.root.rects.max[0][0] += 1000
+	if tr.SanityCheck() == nil {
+		t.Fatal("expected error")
+	}
+	tr.base.root.rects.set(0, saved)
+	check()
+	tr.base.count++
+	if tr.SanityCheck() == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Searcher searches a tree without allocating. It owns the stack of the
+// nodes that are being visited, which is reused by every search, so that
+// services performing many searches per second don't create garbage.
+// A Searcher must not be used by more than one goroutine at a time.
+type Searcher[N numeric, T any] struct {
+	tr    *RTreeGN[N, T]
+	stack []searchFrame[N, T]
+}
+
+// NewSearcher returns a Searcher for the tree.
+func (tr *RTreeGN[N, T]) NewSearcher() *Searcher[N, T] {
+	return &Searcher[N, T]{tr: tr}
+}
+
+// NewSearcher returns a Searcher for the tree.
+func (tr *RTreeG[T]) NewSearcher() *Searcher[float64, T] {
+	return tr.base.NewSearcher()
+}
+
+// Search for items in tree that intersect the provided rectangle, like
+// RTreeGN.Search, in the same order.
+func (s *Searcher[N, T]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	target := rect[N]{min, max}
+	if s.tr.root == nil || !target.intersects(&s.tr.rect) {
+		return
+	}
+	s.stack = append(s.stack[:0], searchFrame[N, T]{n: s.tr.root})
+	s.stack, _ = searchStack(s.stack, target, s.tr.guard(iter))
+	clear(s.stack[:cap(s.stack)])
+	s.stack = s.stack[:0]
+}
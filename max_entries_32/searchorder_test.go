@@ -0,0 +1,74 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSearchOrdered(t *testing.T) {
+	for _, ordered := range []bool{true, false} {
+		tr := New(WithOrdering[float64, int](ordered))
+		N := 10000
+		rects := make([]rect[float64], N)
+		for i := 0; i < N; i++ {
+			rects[i] = randRect('r')
+			tr.Insert(rects[i].min, rects[i].max, i)
+		}
+		for i := 0; i < 100; i++ {
+			q := randRect('r')
+			q.max[0] = q.min[0] + rand.Float64()*100
+			q.max[1] = q.min[1] + rand.Float64()*50
+			p := randRect('p').min
+			pr := rect[float64]{p, p}
+			orders := []struct {
+				order Order[float64, int]
+				key   func(r *rect[float64], data int) float64
+			}{
+				{OrderByMinX[float64, int](),
+					func(r *rect[float64], data int) float64 {
+						return r.min[0]
+					}},
+				{OrderByDistance[float64, int](p),
+					func(r *rect[float64], data int) float64 {
+						return pr.boxDist(r)
+					}},
+				{OrderByCustom(func(a, b Item[float64, int]) bool {
+					return a.Data > b.Data
+				}), func(r *rect[float64], data int) float64 {
+					return float64(-data)
+				}},
+			}
+			for _, o := range orders {
+				var expect []float64
+				for j := 0; j < N; j++ {
+					if rects[j].intersects(&q) {
+						expect = append(expect, o.key(&rects[j], j))
+					}
+				}
+				slices.Sort(expect)
+				k := len(expect)
+				if i%2 == 0 {
+					// stop early
+					k = min(k, rand.Intn(10)+1)
+				}
+				var got []float64
+				tr.SearchOrdered(q.min, q.max, o.order,
+					func(min, max [2]float64, data int) bool {
+						if rects[data] != (rect[float64]{min, max}) {
+							t.Fatalf("unexpected rect for %d", data)
+						}
+						got = append(got, o.key(&rects[data], data))
+						return len(got) < k
+					})
+				if !slices.Equal(got, expect[:k]) {
+					t.Fatalf("expected %v, got %v", expect[:k], got)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// segmentHitsRect is a simple reference for SearchSegment that steps along
+// the segment.
+func segmentHitsRect(a, b [2]float64, r rect[float64]) bool {
+	const steps = 10000
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / steps
+		p := rect[float64]{
+			[2]float64{a[0] + (b[0]-a[0])*t, a[1] + (b[1]-a[1])*t},
+			[2]float64{a[0] + (b[0]-a[0])*t, a[1] + (b[1]-a[1])*t},
+		}
+		if r.contains(&p) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSearchSegment(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 2000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		rects[i].max[0] += 2
+		rects[i].max[1] += 2
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for j := 0; j < 20; j++ {
+		a := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		b := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		if j == 0 {
+			b[1] = a[1] // horizontal
+		}
+		found := make(map[int]bool)
+		tr.SearchSegment(a, b, func(min, max [2]float64, data int) bool {
+			found[data] = true
+			return true
+		})
+		var expect int
+		for i := range rects {
+			if segmentHitsRect(a, b, rects[i]) {
+				expect++
+				if !found[i] {
+					t.Fatalf("item %d not found", i)
+				}
+			}
+		}
+		// the stepping reference may miss the corner of a rect
+		if len(found) < expect || len(found) > expect+2 {
+			t.Fatalf("expected %d, got %d", expect, len(found))
+		}
+	}
+	// a single point
+	var count int
+	p := rects[0].min
+	tr.SearchSegment(p, p, func(min, max [2]float64, data int) bool {
+		count++
+		return true
+	})
+	if count == 0 {
+		t.Fatal("expected items")
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestSelfJoin(t *testing.T) {
+	var tr RTreeG[int]
+	tr.SelfJoin(func(aMin, aMax [2]float64, a int,
+		bMin, bMax [2]float64, b int) bool {
+		t.Fatal("expected no pairs")
+		return true
+	})
+	rects := make([]rect[float64], 3000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		rects[i].max[0] += 3
+		rects[i].max[1] += 3
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	type pair struct{ a, b int }
+	found := make(map[pair]bool)
+	tr.SelfJoin(func(aMin, aMax [2]float64, a int,
+		bMin, bMax [2]float64, b int) bool {
+		if a == b {
+			t.Fatalf("item %d paired with itself", a)
+		}
+		if a > b {
+			a, b = b, a
+		}
+		if found[pair{a, b}] {
+			t.Fatalf("duplicate pair %d %d", a, b)
+		}
+		found[pair{a, b}] = true
+		return true
+	})
+	var expect int
+	for i := range rects {
+		for j := i + 1; j < len(rects); j++ {
+			if rects[i].intersects(&rects[j]) {
+				expect++
+				if !found[pair{i, j}] {
+					t.Fatalf("pair %d %d not found", i, j)
+				}
+			}
+		}
+	}
+	if expect == 0 || len(found) != expect {
+		t.Fatalf("expected %d, got %d", expect, len(found))
+	}
+	var count int
+	tr.SelfJoin(func(aMin, aMax [2]float64, a int,
+		bMin, bMax [2]float64, b int) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		t.Fatalf("expected 10, got %d", count)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"sync"
+)
+
+// ShardedRTree is an R-tree that is safe for concurrent use, where the items
+// are spread over a number of independent trees, called shards, that each
+// have their own lock. Writes to different shards don't wait for each other,
+// which allows for many more writes per second than a single tree behind a
+// mutex, such as when ingesting telemetry from many goroutines.
+//
+// Searches visit every shard and merge their results, so they are somewhat
+// slower than searching a single tree.
+type ShardedRTree[N numeric, T any] struct {
+	shard  func(min, max [2]N, data T) uint64
+	shards []rtreeShard[N, T]
+}
+
+type rtreeShard[N numeric, T any] struct {
+	mu sync.RWMutex
+	tr RTreeGN[N, T]
+}
+
+// NewShardedRTree returns a new tree with the provided number of shards.
+// The shard function returns the shard of an item, which must always be the
+// same for the same item, such as a hash of the ID of the item. When shard
+// is nil, the items are spread by a hash of their rectangle.
+func NewShardedRTree[N numeric, T any](shards int,
+	shard func(min, max [2]N, data T) uint64,
+) *ShardedRTree[N, T] {
+	if shard == nil {
+		shard = func(min, max [2]N, _ T) uint64 {
+			return hashRect(min, max)
+		}
+	}
+	return &ShardedRTree[N, T]{
+		shard:  shard,
+		shards: make([]rtreeShard[N, T], max(shards, 1)),
+	}
+}
+
+// hashRect returns a hash of the rectangle.
+func hashRect[N numeric](min, max [2]N) uint64 {
+	h := uint64(14695981039346656037)
+	for _, v := range [4]N{min[0], min[1], max[0], max[1]} {
+		h = (h ^ math.Float64bits(float64(v))) * 1099511628211
+	}
+	// mix the high bits into the low bits, which are used for picking a shard
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h
+}
+
+// shardOf returns the index of the shard of the item.
+func (tr *ShardedRTree[N, T]) shardOf(min, max [2]N, data T) int {
+	return int(tr.shard(min, max, data) % uint64(len(tr.shards)))
+}
+
+// Insert data into tree
+func (tr *ShardedRTree[N, T]) Insert(min, max [2]N, data T) {
+	s := &tr.shards[tr.shardOf(min, max, data)]
+	s.mu.Lock()
+	s.tr.Insert(min, max, data)
+	s.mu.Unlock()
+}
+
+// Delete data from tree.
+// Returns false if the item was not found.
+func (tr *ShardedRTree[N, T]) Delete(min, max [2]N, data T) bool {
+	s := &tr.shards[tr.shardOf(min, max, data)]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tr.delete(min, max, data, 0)
+}
+
+// Replace an item.
+// If the old item does not exist then the new item is not inserted.
+// Returns false if the old item was not found.
+func (tr *ShardedRTree[N, T]) Replace(
+	oldMin, oldMax [2]N, oldData T,
+	newMin, newMax [2]N, newData T,
+) bool {
+	i, j := tr.shardOf(oldMin, oldMax, oldData),
+		tr.shardOf(newMin, newMax, newData)
+	s1, s2 := &tr.shards[i], &tr.shards[j]
+	// lock the shards in order, so that concurrent replaces don't deadlock
+	tr.shards[min(i, j)].mu.Lock()
+	defer tr.shards[min(i, j)].mu.Unlock()
+	if i != j {
+		tr.shards[max(i, j)].mu.Lock()
+		defer tr.shards[max(i, j)].mu.Unlock()
+	}
+	if !s1.tr.delete(oldMin, oldMax, oldData, 0) {
+		return false
+	}
+	s2.tr.Insert(newMin, newMax, newData)
+	return true
+}
+
+// Len returns the number of items in tree
+func (tr *ShardedRTree[N, T]) Len() int {
+	var count int
+	for i := range tr.shards {
+		s := &tr.shards[i]
+		s.mu.RLock()
+		count += s.tr.Len()
+		s.mu.RUnlock()
+	}
+	return count
+}
+
+// Bounds returns the minimum bounding rect
+func (tr *ShardedRTree[N, T]) Bounds() (min, max [2]N) {
+	var r rect[N]
+	var ok bool
+	for i := range tr.shards {
+		s := &tr.shards[i]
+		s.mu.RLock()
+		if s.tr.root != nil {
+			if !ok {
+				r, ok = s.tr.rect, true
+			} else {
+				r.expand(&s.tr.rect)
+			}
+		}
+		s.mu.RUnlock()
+	}
+	return r.min, r.max
+}
+
+// Search for items in tree that intersect the provided rectangle.
+// The shards are searched one at a time, and each shard is read locked while
+// it's being searched, so iter must not modify the tree.
+func (tr *ShardedRTree[N, T]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	for i := range tr.shards {
+		s := &tr.shards[i]
+		ok := true
+		s.mu.RLock()
+		s.tr.Search(min, max, func(min, max [2]N, data T) bool {
+			ok = iter(min, max, data)
+			return ok
+		})
+		s.mu.RUnlock()
+		if !ok {
+			return
+		}
+	}
+}
+
+// Scan all items in the tree.
+// The shards are scanned one at a time, and each shard is read locked while
+// it's being scanned, so iter must not modify the tree.
+func (tr *ShardedRTree[N, T]) Scan(iter func(min, max [2]N, data T) bool) {
+	for i := range tr.shards {
+		s := &tr.shards[i]
+		ok := true
+		s.mu.RLock()
+		s.tr.Scan(func(min, max [2]N, data T) bool {
+			ok = iter(min, max, data)
+			return ok
+		})
+		s.mu.RUnlock()
+		if !ok {
+			return
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// dominates returns true if the point p is at least as small as q on both
+// axes and smaller on one of them.
+func dominates[N numeric](p, q [2]N) bool {
+	return p[0] <= q[0] && p[1] <= q[1] && (p[0] < q[0] || p[1] < q[1])
+}
+
+// Skyline finds the items that are not dominated by any other item, where
+// smaller values are better on both axes, such as items that are closest and
+// cheapest. An item dominates another when its min corner is at least as
+// small on both axes and smaller on one of them.
+// The iter function will return the items in order of the sum of their min
+// coordinates.
+// Uses a branch-and-bound traversal, so nodes that are dominated by an item
+// that has already been found are never visited.
+func (tr *RTreeGN[N, T]) Skyline(iter func(min, max [2]N, data T) bool) {
+	if tr.root == nil {
+		return
+	}
+	var sky [][2]N
+	dominated := func(p [2]N) bool {
+		for i := range sky {
+			if dominates(sky[i], p) {
+				return true
+			}
+		}
+		return false
+	}
+	prio := func(p [2]N) float64 {
+		return float64(p[0]) + float64(p[1])
+	}
+	gen := tr.gen
+	var q pqueue[topkElem[N, T]]
+	q.push(prio(tr.rect.min), topkElem[N, T]{rect: tr.rect, node: tr.root})
+	for {
+		_, e, ok := q.pop()
+		if !ok {
+			return
+		}
+		if dominated(e.rect.min) {
+			continue
+		}
+		if e.node == nil {
+			sky = append(sky, e.rect.min)
+			if !iter(e.rect.min, e.rect.max, e.data) {
+				return
+			}
+			if tr.gen != gen {
+				panic(errModified)
+			}
+			continue
+		}
+		rects := &e.node.rects
+		if e.node.leaf() {
+			items := e.node.items()[:e.node.count]
+			for i := range items {
+				if r := rects.at(i); !dominated(r.min) {
+					q.push(prio(r.min),
+						topkElem[N, T]{rect: r, data: items[i]})
+				}
+			}
+		} else {
+			children := e.node.children()[:e.node.count]
+			for i := range children {
+				if r := rects.at(i); !dominated(r.min) {
+					q.push(prio(r.min),
+						topkElem[N, T]{rect: r, node: children[i]})
+				}
+			}
+		}
+	}
+}
+
+// Skyline finds the items that are not dominated by any other item, where
+// smaller values are better on both axes.
+func (tr *RTreeG[T]) Skyline(iter func(min, max [2]float64, data T) bool) {
+	tr.base.Skyline(iter)
+}
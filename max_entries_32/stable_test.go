@@ -0,0 +1,86 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestNearbyStable(t *testing.T) {
+	// a grid of points, where many are at the same distance from the center
+	type point struct {
+		p    [2]float64
+		data int
+	}
+	var pts []point
+	for x := 0; x < 30; x++ {
+		for y := 0; y < 30; y++ {
+			p := [2]float64{float64(x), float64(y)}
+			pts = append(pts, point{p, x*30 + y})
+			// duplicate points that differ only by data
+			pts = append(pts, point{p, -(x*30 + y)})
+		}
+	}
+	center := [2]float64{15, 15}
+	var results [][]int
+	for round := 0; round < 5; round++ {
+		var tr RTreeG[int]
+		for _, i := range rand.Perm(len(pts)) {
+			tr.Insert(pts[i].p, pts[i].p, pts[i].data)
+		}
+		var res []int
+		var last float64
+		tr.NearbyStable(BoxDist[float64, int](center, center, nil),
+			cmp.Compare[int],
+			func(min, max [2]float64, data int, dist float64) bool {
+				if dist < last {
+					t.Fatal("out of order")
+				}
+				last = dist
+				res = append(res, data)
+				return true
+			},
+		)
+		if len(res) != len(pts) {
+			t.Fatalf("expected %d, got %d", len(pts), len(res))
+		}
+		results = append(results, res)
+	}
+	for i := 1; i < len(results); i++ {
+		if !slices.Equal(results[0], results[i]) {
+			t.Fatal("expected the same order")
+		}
+	}
+	// the nearest are the two items at the center, smallest data first
+	if results[0][0] != -(15*30+15) || results[0][1] != 15*30+15 {
+		t.Fatalf("got %v", results[0][:2])
+	}
+
+	// stop early, and no cmp
+	var tr RTreeG[int]
+	for _, pt := range pts {
+		tr.Insert(pt.p, pt.p, pt.data)
+	}
+	var count int
+	var prev rect[float64]
+	var prevDist float64
+	tr.NearbyStable(BoxDist[float64, int](center, center, nil), nil,
+		func(min, max [2]float64, data int, dist float64) bool {
+			r := rect[float64]{min, max}
+			if count > 0 && dist == prevDist && compareRects(&prev, &r) > 0 {
+				t.Fatal("expected items ordered by rect")
+			}
+			prev, prevDist = r, dist
+			count++
+			return count < 7
+		},
+	)
+	if count != 7 {
+		t.Fatalf("expected 7, got %d", count)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// sweptHit returns the time of impact, from 0 to 1, of a box with the
+// provided size, whose min corner moves from o to o+d, against the rectangle.
+func sweptHit[N numeric](o, d, size [2]float64, r *rect[N]) (float64, bool) {
+	// Grow the rectangle by the size of the box (a Minkowski sum) and then
+	// clip the path of the min corner.
+	x := rect[float64]{
+		[2]float64{float64(r.min[0]) - size[0], float64(r.min[1]) - size[1]},
+		[2]float64{float64(r.max[0]), float64(r.max[1])},
+	}
+	t, _, ok := clipLine(o, d, &x, 0, 1)
+	return t, ok
+}
+
+// SearchSwept searches for items in the tree that are hit by the box from min
+// to max as it moves by delta.
+// The time of impact is the fraction of delta, from 0 to 1, that the box
+// travels before first touching the item. Items that already intersect the
+// box have a time of impact of zero.
+// Only the nodes that the moving box passes through are visited, rather than
+// the bounding box of the whole sweep.
+func (tr *RTreeGN[N, T]) SearchSwept(min, max, delta [2]N,
+	iter func(min, max [2]N, data T, toi float64) bool,
+) {
+	if tr.root == nil {
+		return
+	}
+	o := [2]float64{float64(min[0]), float64(min[1])}
+	d := [2]float64{float64(delta[0]), float64(delta[1])}
+	size := [2]float64{
+		float64(max[0]) - float64(min[0]),
+		float64(max[1]) - float64(min[1]),
+	}
+	if _, ok := sweptHit(o, d, size, &tr.rect); ok {
+		gen := tr.gen
+		tr.root.searchSwept(o, d, size,
+			func(min, max [2]N, data T, toi float64) bool {
+				if !iter(min, max, data, toi) {
+					return false
+				}
+				if tr.gen != gen {
+					panic(errModified)
+				}
+				return true
+			},
+		)
+	}
+}
+
+func (n *node[N, T]) searchSwept(o, d, size [2]float64,
+	iter func(min, max [2]N, data T, toi float64) bool,
+) bool {
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if toi, ok := sweptHit(o, d, size, &r); ok {
+				if !iter(r.min, r.max, items[i], toi) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		if _, ok := sweptHit(o, d, size, &r); ok {
+			if !children[i].searchSwept(o, d, size, iter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SearchSwept searches for items in the tree that are hit by the box from min
+// to max as it moves by delta.
+func (tr *RTreeG[T]) SearchSwept(min, max, delta [2]float64,
+	iter func(min, max [2]float64, data T, toi float64) bool,
+) {
+	tr.base.SearchSwept(min, max, delta, iter)
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "errors"
+
+var errNoScore = errors.New("rtree: no score function")
+
+// SetScore sets the function that returns the score of an item, such as the
+// importance of a point of interest.
+// The maximum score of every node is maintained as items are inserted and
+// deleted, which is used by SearchTopK to skip nodes that cannot contain any
+// of the highest-scoring items.
+// Passing nil removes the score function.
+func (tr *RTreeGN[N, T]) SetScore(score func(data T) float64) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.score != nil {
+		tr.removeAggregator(tr.score.idx)
+		tr.score = nil
+	}
+	if score != nil {
+		ai := &aggIndex[N, T, float64]{agg: Aggregator[T, float64]{
+			Item: score,
+			Merge: func(a, b float64) float64 {
+				if a > b {
+					return a
+				}
+				return b
+			},
+		}}
+		tr.addAggregator(ai, &ai.idx)
+		tr.score = ai
+	}
+}
+
+// topkElem is either an item or a node
+type topkElem[N numeric, T any] struct {
+	rect rect[N]
+	data T
+	node *node[N, T]
+}
+
+// SearchTopK searches for the k highest-scoring items that intersect the
+// provided rectangle, using the score function provided to SetScore.
+// The iter function will return the items from the highest score to the
+// lowest.
+// Nodes are visited in order of their maximum score, so only a small part of
+// the tree is visited when k is small, even for large areas.
+//
+// Panics if no score function has been set.
+func (tr *RTreeGN[N, T]) SearchTopK(min, max [2]N, k int,
+	iter func(min, max [2]N, data T, score float64) bool,
+) {
+	if tr.score == nil {
+		panic(errNoScore)
+	}
+	target := rect[N]{min, max}
+	if tr.root == nil || k <= 0 || !target.intersects(&tr.rect) {
+		return
+	}
+	score := tr.score.agg.Item
+	idx := tr.score.idx
+	var q pqueue[topkElem[N, T]]
+	q.push(-tr.root.aggs.vals[idx].(float64),
+		topkElem[N, T]{rect: tr.rect, node: tr.root})
+	for k > 0 {
+		prio, e, ok := q.pop()
+		if !ok {
+			return
+		}
+		if e.node == nil {
+			if !iter(e.rect.min, e.rect.max, e.data, -prio) {
+				return
+			}
+			k--
+			continue
+		}
+		rects := &e.node.rects
+		if e.node.leaf() {
+			items := e.node.items()
+			for i := 0; i < int(e.node.count); i++ {
+				if r := rects.at(i); target.intersects(&r) {
+					q.push(-score(items[i]),
+						topkElem[N, T]{rect: r, data: items[i]})
+				}
+			}
+		} else {
+			children := e.node.children()
+			for i := 0; i < int(e.node.count); i++ {
+				if r := rects.at(i); target.intersects(&r) {
+					q.push(-children[i].aggs.vals[idx].(float64),
+						topkElem[N, T]{rect: r, node: children[i]})
+				}
+			}
+		}
+	}
+}
+
+// SetScore sets the function that returns the score of an item.
+// Passing nil removes the score function.
+func (tr *RTreeG[T]) SetScore(score func(data T) float64) {
+	tr.base.SetScore(score)
+}
+
+// SearchTopK searches for the k highest-scoring items that intersect the
+// provided rectangle, using the score function provided to SetScore.
+func (tr *RTreeG[T]) SearchTopK(min, max [2]float64, k int,
+	iter func(min, max [2]float64, data T, score float64) bool,
+) {
+	tr.base.SearchTopK(min, max, k, iter)
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSearchTopK(t *testing.T) {
+	var tr RTreeG[int]
+	expectPanic(t, func() {
+		tr.SearchTopK([2]float64{}, [2]float64{}, 1, nil)
+	})
+	N := 10000
+	scores := make([]float64, N)
+	for i := 0; i < N; i++ {
+		scores[i] = rand.Float64()
+		r := randRect('m')
+		tr.Insert(r.min, r.max, i)
+	}
+	tr.SetScore(func(data int) float64 { return scores[data] })
+	for i := 0; i < 100; i++ {
+		q := randRect('r')
+		q.max[0] = q.min[0] + rand.Float64()*100
+		q.max[1] = q.min[1] + rand.Float64()*50
+		var all []float64
+		tr.Search(q.min, q.max, func(min, max [2]float64, data int) bool {
+			all = append(all, scores[data])
+			return true
+		})
+		sort.Sort(sort.Reverse(sort.Float64Slice(all)))
+		k := rand.Intn(20) + 1
+		if k > len(all) {
+			k = len(all)
+		}
+		var got []float64
+		tr.SearchTopK(q.min, q.max, k,
+			func(min, max [2]float64, data int, score float64) bool {
+				if score != scores[data] {
+					t.Fatalf("expected %v, got %v", scores[data], score)
+				}
+				got = append(got, score)
+				return true
+			},
+		)
+		if len(got) != k {
+			t.Fatalf("expected %d, got %d", k, len(got))
+		}
+		for j := range got {
+			if got[j] != all[j] {
+				t.Fatalf("expected %v, got %v", all[:k], got)
+			}
+		}
+	}
+}
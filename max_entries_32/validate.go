@@ -0,0 +1,56 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidRect is returned by TryInsert and TryReplace, or panicked by
+// Insert on a strict tree, for a rectangle that has a NaN or infinite
+// coordinate, or a min that is greater than its max.
+var ErrInvalidRect = errors.New("rtree: invalid rect")
+
+// validRect returns true if the rectangle has no NaN or infinite coordinates
+// and its min is not greater than its max.
+func validRect[N numeric](min, max [2]N) bool {
+	for i := 0; i < 2; i++ {
+		if !(min[i] <= max[i]) {
+			// also catches NaN, which is never less or equal
+			return false
+		}
+		if math.IsInf(float64(min[i]), 0) || math.IsInf(float64(max[i]), 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetStrict turns strict mode on or off.
+// A strict tree panics with ErrInvalidRect when inserting a rectangle that
+// has a NaN or infinite coordinate, or a min that is greater than its max.
+// Such rectangles are otherwise silently accepted, which corrupts the order
+// of the nodes and makes items impossible to find.
+func (tr *RTreeGN[N, T]) SetStrict(strict bool) {
+	tr.strict = strict
+}
+
+// Strict returns true if the tree is in strict mode.
+func (tr *RTreeGN[N, T]) Strict() bool {
+	return tr.strict
+}
+
+// SetStrict turns strict mode on or off.
+// A strict tree panics with ErrInvalidRect when inserting an invalid
+// rectangle.
+func (tr *RTreeG[T]) SetStrict(strict bool) {
+	tr.base.SetStrict(strict)
+}
+
+// Strict returns true if the tree is in strict mode.
+func (tr *RTreeG[T]) Strict() bool {
+	return tr.base.Strict()
+}
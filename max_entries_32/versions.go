@@ -0,0 +1,88 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"sort"
+	"sync"
+)
+
+// VersionID identifies a version of a tree that was saved by Checkpoint.
+type VersionID uint64
+
+// Versions keeps immutable versions of a tree that can be searched while the
+// tree continues to be modified, such as for time-travel queries.
+// Each version is a frozen copy-on-write snapshot of the tree, so a version
+// only costs memory for the nodes that were modified since.
+//
+// The tree itself is not safe for concurrent use, and Checkpoint must be
+// called by the writer of the tree. The versions returned by AtVersion are
+// safe for concurrent reads, including while the tree is being modified.
+type Versions[N numeric, T any] struct {
+	tr       *RTreeGN[N, T]
+	mu       sync.RWMutex
+	last     VersionID
+	versions map[VersionID]*RTreeGN[N, T]
+}
+
+// NewVersions returns a version store for the tree.
+func NewVersions[N numeric, T any](tr *RTreeGN[N, T]) *Versions[N, T] {
+	return &Versions[N, T]{
+		tr:       tr,
+		versions: make(map[VersionID]*RTreeGN[N, T]),
+	}
+}
+
+// NewVersionsG returns a version store for the tree.
+func NewVersionsG[T any](tr *RTreeG[T]) *Versions[float64, T] {
+	return NewVersions(&tr.base)
+}
+
+// Checkpoint saves the current state of the tree as a new version.
+func (v *Versions[N, T]) Checkpoint() VersionID {
+	snap := v.tr.Copy()
+	snap.Freeze()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.last++
+	v.versions[v.last] = snap
+	return v.last
+}
+
+// AtVersion returns the tree as it was when the version was saved, or false
+// if the version does not exist or has been released.
+// The returned tree is frozen. Use MutableCopy to make changes to it.
+func (v *Versions[N, T]) AtVersion(id VersionID) (*RTreeGN[N, T], bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	tr, ok := v.versions[id]
+	return tr, ok
+}
+
+// ReleaseVersion removes the version, allowing for the nodes that are only
+// used by it to be garbage collected. Trees that were already returned by
+// AtVersion remain usable.
+// Returns false if the version does not exist.
+func (v *Versions[N, T]) ReleaseVersion(id VersionID) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.versions[id]; !ok {
+		return false
+	}
+	delete(v.versions, id)
+	return true
+}
+
+// Versions returns the IDs of all saved versions, from oldest to newest.
+func (v *Versions[N, T]) Versions() []VersionID {
+	v.mu.RLock()
+	ids := make([]VersionID, 0, len(v.versions))
+	for id := range v.versions {
+		ids = append(ids, id)
+	}
+	v.mu.RUnlock()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,73 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestVersions(t *testing.T) {
+	var tr RTreeG[int]
+	v := NewVersionsG(&tr)
+	rects := make([]rect[float64], 10000)
+	var ids []VersionID
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+		if i%1000 == 999 {
+			ids = append(ids, v.Checkpoint())
+		}
+	}
+	// readers search old versions while the tree is modified
+	var wg sync.WaitGroup
+	for j, id := range ids {
+		wg.Add(1)
+		go func(j int, id VersionID) {
+			defer wg.Done()
+			snap, ok := v.AtVersion(id)
+			if !ok {
+				t.Errorf("version %d not found", id)
+				return
+			}
+			for k := 0; k < 5; k++ {
+				var count int
+				snap.Scan(func(min, max [2]float64, data int) bool {
+					count++
+					return true
+				})
+				if count != (j+1)*1000 {
+					t.Errorf("expected %d, got %d", (j+1)*1000, count)
+					return
+				}
+			}
+		}(j, id)
+	}
+	for i := range rects {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+	wg.Wait()
+	if tr.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, tr.Len())
+	}
+
+	snap, _ := v.AtVersion(ids[0])
+	if !snap.Frozen() {
+		t.Fatal("expected frozen version")
+	}
+	expectPanic(t, func() { snap.Insert([2]float64{}, [2]float64{}, 0) })
+	if !v.ReleaseVersion(ids[0]) || v.ReleaseVersion(ids[0]) {
+		t.Fatal("unexpected release result")
+	}
+	if _, ok := v.AtVersion(ids[0]); ok {
+		t.Fatal("expected released version")
+	}
+	if got := v.Versions(); len(got) != len(ids)-1 || got[0] != ids[1] {
+		t.Fatalf("unexpected versions %v", got)
+	}
+	if snap.Len() != 1000 {
+		t.Fatalf("expected %d, got %d", 1000, snap.Len())
+	}
+}
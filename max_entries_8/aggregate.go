@@ -0,0 +1,220 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Aggregator describes an aggregate value, such as a sum, minimum, or
+// maximum, that is computed over the items of a tree.
+type Aggregator[T, A any] struct {
+	// Item returns the aggregate value of a single item.
+	Item func(data T) A
+	// Merge returns the combined aggregate value of a and b.
+	// Merge must be associative and commutative.
+	Merge func(a, b A) A
+}
+
+// AggregateIndex is an Aggregator that has been registered with a tree.
+// The aggregate value for every node in the tree is maintained as items are
+// inserted and deleted, and nodes are split.
+type AggregateIndex[N numeric, T, A any] struct {
+	tr *RTreeGN[N, T]
+	ai *aggIndex[N, T, A]
+}
+
+// aggregator is a registered Aggregator, with the aggregate value type erased.
+type aggregator[N numeric, T any] interface {
+	// fix updates the aggregate value of the node from its children.
+	fix(n *node[N, T])
+}
+
+type aggIndex[N numeric, T, A any] struct {
+	idx int // index of aggregate value in the nodes
+	agg Aggregator[T, A]
+}
+
+// RegisterAggregator adds an aggregator to the tree.
+// The aggregate value of every node is computed immediately and maintained
+// from then on, which allows for AggregateIndex.Aggregate to answer without
+// visiting every item in the searched area.
+//
+// Copies of the tree, see Copy, continue to maintain all aggregators that
+// were registered before the copy was made.
+func RegisterAggregator[N numeric, T, A any](tr *RTreeGN[N, T],
+	agg Aggregator[T, A],
+) *AggregateIndex[N, T, A] {
+	ai := &aggIndex[N, T, A]{agg: agg}
+	tr.addAggregator(ai, &ai.idx)
+	return &AggregateIndex[N, T, A]{tr: tr, ai: ai}
+}
+
+// RegisterAggregatorG adds an aggregator to the tree.
+// See RegisterAggregator.
+func RegisterAggregatorG[T, A any](tr *RTreeG[T], agg Aggregator[T, A],
+) *AggregateIndex[float64, T, A] {
+	return RegisterAggregator(&tr.base, agg)
+}
+
+// Unregister removes the aggregator from the tree.
+func (ai *AggregateIndex[N, T, A]) Unregister() {
+	ai.tr.removeAggregator(ai.ai.idx)
+}
+
+// In returns the same aggregator for a copy of the tree that the aggregator
+// was originally registered with.
+func (ai *AggregateIndex[N, T, A]) In(tr *RTreeGN[N, T],
+) *AggregateIndex[N, T, A] {
+	return &AggregateIndex[N, T, A]{tr: tr, ai: ai.ai}
+}
+
+// Aggregate returns the aggregate value of all items that intersect the
+// provided rectangle.
+// Returns false when there are no such items.
+func (ai *AggregateIndex[N, T, A]) Aggregate(min, max [2]N) (agg A, ok bool) {
+	return ai.ai.query(ai.tr, &rect[N]{min, max})
+}
+
+// addAggregator adds the aggregator to the tree and stores its index in idx.
+func (tr *RTreeGN[N, T]) addAggregator(agg aggregator[N, T], idx *int) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	// Always copy the aggregators, which may be shared with other trees.
+	aggs := make([]aggregator[N, T], len(tr.aggs), len(tr.aggs)+1)
+	copy(aggs, tr.aggs)
+	*idx = len(aggs)
+	for i := range aggs {
+		if aggs[i] == nil {
+			*idx = i
+			break
+		}
+	}
+	if *idx == len(aggs) {
+		aggs = append(aggs, agg)
+	} else {
+		aggs[*idx] = agg
+	}
+	tr.aggs = aggs
+	if tr.root != nil {
+		tr.cowAll(&tr.root)
+		tr.fixAggs()
+	}
+}
+
+func (tr *RTreeGN[N, T]) removeAggregator(idx int) {
+	aggs := make([]aggregator[N, T], len(tr.aggs))
+	copy(aggs, tr.aggs)
+	aggs[idx] = nil
+	tr.aggs = aggs
+}
+
+// cowAll ensures that the node and all of its children are not shared with
+// other R-trees, and marks them as dirty.
+func (tr *RTreeGN[N, T]) cowAll(n **node[N, T]) {
+	tr.cow(n)
+	if !(*n).leaf() {
+		children := (*n).children()[:(*n).count]
+		for i := range children {
+			tr.cowAll(&children[i])
+		}
+	}
+}
+
+// fixAggs updates the aggregates of every dirty node.
+func (tr *RTreeGN[N, T]) fixAggs() {
+	if len(tr.aggs) == 0 || tr.root == nil {
+		return
+	}
+	tr.nodeFixAggs(tr.root)
+}
+
+// nodeFixAggs updates the aggregates of the node, and of its children, when
+// they are dirty. Nodes without aggregates are always dirty.
+func (tr *RTreeGN[N, T]) nodeFixAggs(n *node[N, T]) {
+	if n.aggs != nil && !n.aggs.dirty {
+		return
+	}
+	if !n.leaf() {
+		children := n.children()[:n.count]
+		for i := range children {
+			tr.nodeFixAggs(children[i])
+		}
+	}
+	if n.aggs == nil {
+		n.aggs = new(nodeAggs)
+	}
+	if len(n.aggs.vals) != len(tr.aggs) {
+		n.aggs.vals = make([]any, len(tr.aggs))
+	}
+	for _, agg := range tr.aggs {
+		if agg != nil {
+			agg.fix(n)
+		}
+	}
+	n.aggs.dirty = false
+}
+
+func (ai *aggIndex[N, T, A]) fix(n *node[N, T]) {
+	if n.count == 0 {
+		n.aggs.vals[ai.idx] = nil
+		return
+	}
+	var agg A
+	if n.leaf() {
+		items := n.items()[:n.count]
+		agg = ai.agg.Item(items[0])
+		for i := 1; i < len(items); i++ {
+			agg = ai.agg.Merge(agg, ai.agg.Item(items[i]))
+		}
+	} else {
+		children := n.children()[:n.count]
+		agg = children[0].aggs.vals[ai.idx].(A)
+		for i := 1; i < len(children); i++ {
+			agg = ai.agg.Merge(agg, children[i].aggs.vals[ai.idx].(A))
+		}
+	}
+	n.aggs.vals[ai.idx] = agg
+}
+
+func (ai *aggIndex[N, T, A]) query(tr *RTreeGN[N, T], target *rect[N],
+) (agg A, ok bool) {
+	if tr.root == nil || !target.intersects(&tr.rect) {
+		return agg, false
+	}
+	if target.contains(&tr.rect) {
+		agg, ok = tr.root.aggs.vals[ai.idx].(A)
+		return agg, ok
+	}
+	ai.nodeQuery(tr.root, target, &agg, &ok)
+	return agg, ok
+}
+
+func (ai *aggIndex[N, T, A]) nodeQuery(n *node[N, T], target *rect[N],
+	agg *A, ok *bool,
+) {
+	merge := func(v A) {
+		if *ok {
+			*agg = ai.agg.Merge(*agg, v)
+		} else {
+			*agg, *ok = v, true
+		}
+	}
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			if r := n.rects.at(i); target.intersects(&r) {
+				merge(ai.agg.Item(items[i]))
+			}
+		}
+		return
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		if target.contains(&r) {
+			merge(children[i].aggs.vals[ai.idx].(A))
+		} else if target.intersects(&r) {
+			ai.nodeQuery(children[i], target, agg, ok)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// nodeAllocator allocates and recycles the nodes of a tree.
+type nodeAllocator[N numeric, T any] interface {
+	// alloc returns a new empty leaf or branch node.
+	alloc(isleaf bool) *node[N, T]
+	// free is called when a node is no longer used by the tree.
+	free(n *node[N, T])
+}
+
+// SetAllocator sets the pool from which new nodes are allocated and to which
+// nodes are returned when they are removed from the tree, such as when they
+// become empty during a Delete. Passing nil removes the pool.
+// By default nodes are allocated by the runtime and left to the garbage
+// collector.
+//
+// Only nodes that are exclusively owned by this tree are ever returned to the
+// pool. Nodes that are shared with copies of this tree, see Copy, are left to
+// the garbage collector.
+func (tr *RTreeGN[N, T]) SetAllocator(alloc *PoolAllocator[N, T]) {
+	if alloc == nil {
+		tr.alloc = nil
+	} else {
+		tr.alloc = alloc
+	}
+}
+
+// release returns the node, and all of its children, to the allocator, but
+// only if they are not shared with other trees.
+func (tr *RTreeGN[N, T]) release(n *node[N, T]) {
+	if tr.alloc == nil || n.icow != tr.icow || tr.frozen {
+		return
+	}
+	if !n.leaf() {
+		children := n.children()[:n.count]
+		for i := 0; i < len(children); i++ {
+			tr.release(children[i])
+		}
+	}
+	tr.alloc.free(n)
+}
+
+// Reset deletes all items, like Clear, and also returns the nodes to the
+// allocator so they can be reused when the tree is filled again, such as when
+// rebuilding the tree on every frame.
+// Nodes that are shared with copies of this tree are left to the garbage
+// collector.
+func (tr *RTreeGN[N, T]) Reset() {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.root != nil {
+		if writeGuardEnabled {
+			tr.writes.enter()
+		}
+		tr.release(tr.root)
+		if writeGuardEnabled {
+			tr.writes.exit()
+		}
+	}
+	tr.Clear()
+}
+
+// PoolAllocator recycles the nodes of trees using a sync.Pool, which greatly
+// reduces the number of allocations for delete-heavy workloads.
+// A PoolAllocator is safe to share between multiple trees.
+type PoolAllocator[N numeric, T any] struct {
+	leaves   sync.Pool
+	branches sync.Pool
+}
+
+// NewPoolAllocator returns a new PoolAllocator.
+func NewPoolAllocator[N numeric, T any]() *PoolAllocator[N, T] {
+	a := new(PoolAllocator[N, T])
+	a.leaves.New = func() any {
+		return &leafNode[N, T]{node: node[N, T]{kind: leaf}}
+	}
+	a.branches.New = func() any {
+		return &branchNode[N, T]{node: node[N, T]{kind: branch}}
+	}
+	return a
+}
+
+func (a *PoolAllocator[N, T]) alloc(isleaf bool) *node[N, T] {
+	if isleaf {
+		return (*node[N, T])(unsafe.Pointer(a.leaves.Get().(*leafNode[N, T])))
+	}
+	return (*node[N, T])(unsafe.Pointer(a.branches.Get().(*branchNode[N, T])))
+}
+
+func (a *PoolAllocator[N, T]) free(n *node[N, T]) {
+	if n.leaf() {
+		ln := (*leafNode[N, T])(unsafe.Pointer(n))
+		*ln = leafNode[N, T]{node: node[N, T]{kind: leaf}}
+		a.leaves.Put(ln)
+	} else {
+		bn := (*branchNode[N, T])(unsafe.Pointer(n))
+		*bn = branchNode[N, T]{node: node[N, T]{kind: branch}}
+		a.branches.Put(bn)
+	}
+}
+
+// SetAllocator sets the pool from which new nodes are allocated and to which
+// nodes are returned when they are removed from the tree.
+func (tr *RTreeG[T]) SetAllocator(alloc *PoolAllocator[float64, T]) {
+	tr.base.SetAllocator(alloc)
+}
+
+// Reset deletes all items, like Clear, and also returns the nodes to the
+// allocator so they can be reused when the tree is filled again.
+func (tr *RTreeG[T]) Reset() {
+	tr.base.Reset()
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type countingAllocator[N numeric, T any] struct {
+	nodeAllocator[N, T]
+	allocs, frees int
+}
+
+func (a *countingAllocator[N, T]) alloc(isleaf bool) *node[N, T] {
+	a.allocs++
+	return a.nodeAllocator.alloc(isleaf)
+}
+
+func (a *countingAllocator[N, T]) free(n *node[N, T]) {
+	a.frees++
+	a.nodeAllocator.free(n)
+}
+
+func TestPoolAllocator(t *testing.T) {
+	alloc := &countingAllocator[float64, int]{
+		nodeAllocator: NewPoolAllocator[float64, int](),
+	}
+	var tr RTreeG[int]
+	tr.base.alloc = alloc
+	N := 10000
+	rects := make([]rect[float64], N)
+	for i := 0; i < N; i++ {
+		rects[i] = randRect('p')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	snap := tr.Copy()
+	for round := 0; round < 3; round++ {
+		for _, i := range rand.Perm(N) {
+			tr.Delete(rects[i].min, rects[i].max, i)
+		}
+		if tr.Len() != 0 {
+			t.Fatalf("expected %d, got %d", 0, tr.Len())
+		}
+		for i := 0; i < N; i++ {
+			tr.Insert(rects[i].min, rects[i].max, i)
+		}
+		if err := rSane(&tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if alloc.frees == 0 {
+		t.Fatal("expected nodes to be returned to the allocator")
+	}
+	// the snapshot must be untouched by recycled nodes
+	if err := rSane(snap); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	snap.Scan(func(min, max [2]float64, data int) bool {
+		if min != rects[data].min {
+			t.Fatalf("item %d: wrong rect", data)
+		}
+		count++
+		return true
+	})
+	if count != N {
+		t.Fatalf("expected %d, got %d", N, count)
+	}
+}
+
+func TestReset(t *testing.T) {
+	alloc := &countingAllocator[float64, int]{
+		nodeAllocator: NewPoolAllocator[float64, int](),
+	}
+	var tr RTreeG[int]
+	tr.Reset()
+	tr.base.alloc = alloc
+	for frame := 0; frame < 5; frame++ {
+		for i := 0; i < 5000; i++ {
+			r := randRect('r')
+			tr.Insert(r.min, r.max, i)
+		}
+		allocs := alloc.allocs
+		frees := alloc.frees
+		tr.Reset()
+		if tr.Len() != 0 {
+			t.Fatalf("expected 0, got %d", tr.Len())
+		}
+		if err := tr.SanityCheck(); err != nil {
+			t.Fatal(err)
+		}
+		if alloc.frees-frees != allocs-frees {
+			// every node that was allocated and not freed is now freed
+			t.Fatalf("expected %d frees, got %d", allocs-frees,
+				alloc.frees-frees)
+		}
+	}
+	// nodes that are shared with a copy are not returned
+	for i := 0; i < 5000; i++ {
+		r := randRect('p')
+		tr.Insert(r.min, r.max, i)
+	}
+	snap := tr.Copy()
+	frees := alloc.frees
+	tr.Reset()
+	if alloc.frees != frees {
+		t.Fatal("expected no frees")
+	}
+	if snap.Len() != 5000 {
+		t.Fatalf("expected 5000, got %d", snap.Len())
+	}
+	if err := rSane(snap); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetAllocator(t *testing.T) {
+	var tr RTreeG[int]
+	tr.SetAllocator(NewPoolAllocator[float64, int]())
+	for i := 0; i < 1000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+	tr.SetAllocator(nil)
+	if tr.base.alloc != nil {
+		t.Fatal("expected no allocator")
+	}
+	tr.Reset()
+}
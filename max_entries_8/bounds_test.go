@@ -0,0 +1,66 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestRecalcBounds(t *testing.T) {
+	var tr RTreeG[int]
+	if tr.RecalcBounds() {
+		t.Fatal("expected false")
+	}
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	if tr.RecalcBounds() {
+		t.Fatal("expected false")
+	}
+	// inflate the rects along a path to a leaf
+	inflate := func(r *rect[float64]) {
+		r.min[0] -= 10
+		r.min[1] -= 10
+		r.max[0] += 10
+		r.max[1] += 10
+	}
+	min, max := tr.Bounds()
+	inflate(&tr.base.rect)
+	n := tr.base.root
+	for !n.leaf() {
+		i := int(n.count) / 2
+		r := n.rects.at(i)
+		inflate(&r)
+		n.rects.set(i, r)
+		n = n.children()[i]
+	}
+	if tr.SanityCheck() == nil {
+		t.Fatal("expected an error")
+	}
+	tr2 := tr.Copy()
+	if !tr2.RecalcBounds() {
+		t.Fatal("expected true")
+	}
+	if err := tr2.SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+	if min2, max2 := tr2.Bounds(); min2 != min || max2 != max {
+		t.Fatal("expected the original bounds")
+	}
+	// the source tree is not changed
+	if tr.SanityCheck() == nil {
+		t.Fatal("expected an error")
+	}
+	if !tr.RecalcBounds() {
+		t.Fatal("expected true")
+	}
+	if err := tr.SanityCheck(); err != nil {
+		t.Fatal(err)
+	}
+	if tr.RecalcBounds() {
+		t.Fatal("expected false")
+	}
+	tr.Freeze()
+	expectPanic(t, func() { tr.RecalcBounds() })
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Centroid returns the average of the centers of all item rectangles, or
+// false if the tree is empty.
+func (tr *RTreeGN[N, T]) Centroid() (center [2]float64, ok bool) {
+	if tr.root == nil {
+		return center, false
+	}
+	var sum [2]float64
+	tr.root.sumCenters(&sum)
+	center[0] = sum[0] / float64(tr.count)
+	center[1] = sum[1] / float64(tr.count)
+	return center, true
+}
+
+func (n *node[N, T]) sumCenters(sum *[2]float64) {
+	if n.leaf() {
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			sum[0] += r.center(0)
+			sum[1] += r.center(1)
+		}
+		return
+	}
+	children := n.children()[:n.count]
+	for i := range children {
+		children[i].sumCenters(sum)
+	}
+}
+
+// BoundsOf returns the bounding rectangle of all items that intersect the
+// provided rectangle, along with the number of items, such as for zooming to
+// a selection.
+// The rectangle of a node that is entirely inside of the window is used as
+// is, so the items below it are never visited.
+func (tr *RTreeGN[N, T]) BoundsOf(min, max [2]N) (rmin, rmax [2]N, n int) {
+	target := rect[N]{min, max}
+	if tr.root == nil || !target.intersects(&tr.rect) {
+		return rmin, rmax, 0
+	}
+	if target.contains(&tr.rect) {
+		return tr.rect.min, tr.rect.max, tr.count
+	}
+	var r rect[N]
+	tr.root.boundsOf(&target, &r, &n)
+	return r.min, r.max, n
+}
+
+func (n *node[N, T]) boundsOf(target, r *rect[N], count *int) {
+	if n.leaf() {
+		for i := 0; i < int(n.count); i++ {
+			if ir := n.rects.at(i); target.intersects(&ir) {
+				addBounds(r, &ir, count, 1)
+			}
+		}
+		return
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		cr := n.rects.at(i)
+		if target.contains(&cr) {
+			addBounds(r, &cr, count, children[i].deepCount())
+		} else if target.intersects(&cr) {
+			children[i].boundsOf(target, r, count)
+		}
+	}
+}
+
+// addBounds expands r to include b, where r is empty when count is zero.
+func addBounds[N numeric](r, b *rect[N], count *int, n int) {
+	if *count == 0 {
+		*r = *b
+	} else {
+		r.expand(b)
+	}
+	*count += n
+}
+
+// Centroid returns the average of the centers of all item rectangles, or
+// false if the tree is empty.
+func (tr *RTreeG[T]) Centroid() (center [2]float64, ok bool) {
+	return tr.base.Centroid()
+}
+
+// BoundsOf returns the bounding rectangle of all items that intersect the
+// provided rectangle, along with the number of items.
+func (tr *RTreeG[T]) BoundsOf(min, max [2]float64,
+) (rmin, rmax [2]float64, n int) {
+	return tr.base.BoundsOf(min, max)
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "math"
+
+// closestElem is a pair of items or nodes, one from each tree, along with
+// their heights, where items have a height of zero.
+type closestElem[N numeric, T any] struct {
+	a, b   topkElem[N, T]
+	ah, bh int
+}
+
+// expandElem calls fn for every child item or node of the node in e.
+func expandElem[N numeric, T any](e topkElem[N, T], h int,
+	fn func(e topkElem[N, T], h int),
+) {
+	rects := &e.node.rects
+	if e.node.leaf() {
+		items := e.node.items()[:e.node.count]
+		for i := range items {
+			fn(topkElem[N, T]{rect: rects.at(i), data: items[i]}, 0)
+		}
+	} else {
+		children := e.node.children()[:e.node.count]
+		for i := range children {
+			fn(topkElem[N, T]{rect: rects.at(i), node: children[i]}, h-1)
+		}
+	}
+}
+
+// ClosestPair finds the pair of items, one from this tree and one from the
+// other tree, whose rectangles are closest to each other, along with the
+// distance between them, which is zero when they intersect.
+// Pairs of nodes are visited from the closest to the farthest, so the search
+// ends as soon as the first pair of items is reached.
+// Returns false if either tree is empty.
+func (tr *RTreeGN[N, T]) ClosestPair(other *RTreeGN[N, T],
+) (a, b T, dist float64, ok bool) {
+	if tr.root == nil || other.root == nil {
+		return a, b, 0, false
+	}
+	var q pqueue[closestElem[N, T]]
+	q.push(tr.rect.boxDist2(&other.rect), closestElem[N, T]{
+		a:  topkElem[N, T]{rect: tr.rect, node: tr.root},
+		b:  topkElem[N, T]{rect: other.rect, node: other.root},
+		ah: tr.root.height(),
+		bh: other.root.height(),
+	})
+	for {
+		prio, e, _ := q.pop()
+		if e.a.node == nil && e.b.node == nil {
+			return e.a.data, e.b.data, math.Sqrt(prio), true
+		}
+		// Expand the taller side, or the one that isn't an item yet.
+		if e.a.node != nil && (e.b.node == nil || e.ah >= e.bh) {
+			expandElem(e.a, e.ah, func(ce topkElem[N, T], h int) {
+				q.push(ce.rect.boxDist2(&e.b.rect),
+					closestElem[N, T]{a: ce, b: e.b, ah: h, bh: e.bh})
+			})
+		} else {
+			expandElem(e.b, e.bh, func(ce topkElem[N, T], h int) {
+				q.push(e.a.rect.boxDist2(&ce.rect),
+					closestElem[N, T]{a: e.a, b: ce, ah: e.ah, bh: h})
+			})
+		}
+	}
+}
+
+// ClosestPair finds the pair of items, one from this tree and one from the
+// other tree, whose rectangles are closest to each other.
+func (tr *RTreeG[T]) ClosestPair(other *RTreeG[T],
+) (a, b T, dist float64, ok bool) {
+	return tr.base.ClosestPair(&other.base)
+}
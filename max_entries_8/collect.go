@@ -0,0 +1,88 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// SearchAppend appends the data of every item in tree that intersects the
+// provided rectangle to dst and returns the extended slice.
+// Nothing is allocated when dst has enough capacity, so the same slice can be
+// reused across searches by passing dst[:0].
+func (tr *RTreeGN[N, T]) SearchAppend(dst []T, min, max [2]N) []T {
+	target := rect[N]{min, max}
+	if tr.root == nil || !target.intersects(&tr.rect) {
+		return dst
+	}
+	tr.root.search(target, func(min, max [2]N, data T) bool {
+		dst = append(dst, data)
+		return true
+	})
+	return dst
+}
+
+// NearbyAppend appends the data of the k items in tree that are nearest to
+// the point p to dst, from the nearest to the farthest, and returns the
+// extended slice.
+// The distance is the same box distance that is used by BoxDist.
+// Nothing is allocated when dst has enough capacity, so the same slice can be
+// reused across searches by passing dst[:0].
+func (tr *RTreeGN[N, T]) NearbyAppend(dst []T, p [2]N, k int) []T {
+	if tr.root == nil || k <= 0 {
+		return dst
+	}
+	target := rect[N]{p, p}
+	q := tr.qpool.Get().(*queue[N, T])
+	defer func() {
+		clear(*q)
+		*q = (*q)[:0]
+		tr.qpool.Put(q)
+	}()
+	q.push(qnode[N, T]{rect: tr.rect, node: tr.root})
+	for k > 0 {
+		qn, ok := q.pop()
+		if !ok {
+			break
+		}
+		if qn.node == nil {
+			dst = append(dst, qn.data)
+			k--
+			continue
+		}
+		rects := &qn.node.rects
+		if qn.node.leaf() {
+			items := qn.node.items()[:qn.node.count]
+			for i := range items {
+				r := rects.at(i)
+				q.push(qnode[N, T]{
+					dist: target.boxDist(&r),
+					rect: r,
+					data: items[i],
+				})
+			}
+		} else {
+			children := qn.node.children()[:qn.node.count]
+			for i := range children {
+				r := rects.at(i)
+				q.push(qnode[N, T]{
+					dist: target.boxDist(&r),
+					rect: r,
+					node: children[i],
+				})
+			}
+		}
+	}
+	return dst
+}
+
+// SearchAppend appends the data of every item in tree that intersects the
+// provided rectangle to dst and returns the extended slice.
+func (tr *RTreeG[T]) SearchAppend(dst []T, min, max [2]float64) []T {
+	return tr.base.SearchAppend(dst, min, max)
+}
+
+// NearbyAppend appends the data of the k items in tree that are nearest to
+// the point p to dst, from the nearest to the farthest, and returns the
+// extended slice.
+func (tr *RTreeG[T]) NearbyAppend(dst []T, p [2]float64, k int) []T {
+	return tr.base.NearbyAppend(dst, p, k)
+}
@@ -0,0 +1,178 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"sync"
+	"time"
+)
+
+// compaction is a subtree of the root along with its rebuilt replacement.
+type compaction[N numeric, T any] struct {
+	old, new *node[N, T]
+}
+
+// Compact rebuilds the subtrees of the root whose leaves are on average less
+// filled than the threshold, which is a fraction from 0 to 1.
+// Trees that have seen many deletes tend to have many sparsely filled nodes,
+// which wastes memory and slows down searching. Each rebuilt subtree is
+// packed like LoadBulk does, but keeps its height.
+// Returns the number of subtrees that were rebuilt.
+func (tr *RTreeGN[N, T]) Compact(threshold float64) int {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	return tr.applyCompaction(tr.planCompaction(threshold))
+}
+
+// StartAutoCompaction starts a goroutine that compacts the tree at every
+// interval, like Compact does, and returns a function that stops it.
+//
+// A tree is not safe for concurrent use, so the provided locker must be the
+// same one that guards all other access to the tree.
+// The lock is only held while taking a copy-on-write snapshot of the tree
+// and while swapping in the rebuilt subtrees. The subtrees are rebuilt from
+// the snapshot without holding the lock, and are discarded if the original
+// subtree was modified in the meantime.
+// No snapshot is taken while the tree is unchanged since the last one, as
+// taking one makes the next write copy the nodes that it modifies.
+// Frozen trees are not compacted.
+func (tr *RTreeGN[N, T]) StartAutoCompaction(threshold float64,
+	interval time.Duration, mu sync.Locker,
+) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var planned bool
+		var gen uint64 // of the tree when it was last planned
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			mu.Lock()
+			if tr.frozen || tr.root == nil || tr.root.leaf() ||
+				planned && tr.gen == gen {
+				mu.Unlock()
+				continue
+			}
+			planned, gen = true, tr.gen
+			snap := tr.Copy()
+			mu.Unlock()
+			// The allocator and hooks of the tree may not be safe for
+			// concurrent use.
+			snap.alloc = nil
+			snap.hooks = nil
+			plan := snap.planCompaction(threshold)
+			if len(plan) == 0 {
+				continue
+			}
+			mu.Lock()
+			if !tr.frozen {
+				unchanged := tr.gen == gen
+				if tr.applyCompaction(plan) > 0 && unchanged {
+					// the compacted tree was planned already
+					gen = tr.gen
+				}
+			}
+			mu.Unlock()
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		wg.Wait()
+	}
+}
+
+// planCompaction rebuilds the sparse subtrees of the root without modifying
+// the tree.
+func (tr *RTreeGN[N, T]) planCompaction(threshold float64,
+) []compaction[N, T] {
+	if tr.root == nil || tr.root.leaf() {
+		return nil
+	}
+	var plan []compaction[N, T]
+	children := tr.root.children()[:tr.root.count]
+	for _, child := range children {
+		var s Stats
+		var leafEntries, branchEntries int
+		child.stats(&s, 1, &leafEntries, &branchEntries)
+		fanout := tr.fanout(true)
+		if float64(leafEntries)/float64(s.Leaves*fanout) >= threshold {
+			continue
+		}
+		// Only rebuild when it actually needs fewer leaves, otherwise the
+		// same subtree would be rebuilt over and over again.
+		if (leafEntries+fanout-1)/fanout >= s.Leaves {
+			continue
+		}
+		bitems := child.appendBulkItems(nil)
+		plan = append(plan, compaction[N, T]{
+			old: child,
+			new: tr.buildBulk(bitems, 1, s.Height),
+		})
+	}
+	return plan
+}
+
+// applyCompaction swaps in the rebuilt subtrees whose originals are still
+// children of the root, and returns how many were swapped.
+func (tr *RTreeGN[N, T]) applyCompaction(plan []compaction[N, T]) int {
+	var applied int
+	for _, c := range plan {
+		if tr.root == nil || tr.root.leaf() {
+			break
+		}
+		children := tr.root.children()[:tr.root.count]
+		for i := range children {
+			if children[i] != c.old {
+				continue
+			}
+			tr.cow(&tr.root)
+			tr.root.children()[i] = c.new
+			c.new.setOwner(tr.icow)
+			tr.release(c.old)
+			applied++
+			break
+		}
+	}
+	if applied > 0 {
+		tr.gen++
+		tr.fixAggs()
+	}
+	return applied
+}
+
+// setOwner makes the tree with the provided icow the owner of the subtree.
+func (n *node[N, T]) setOwner(icow uint64) {
+	n.icow = icow
+	if !n.leaf() {
+		children := n.children()[:n.count]
+		for i := range children {
+			children[i].setOwner(icow)
+		}
+	}
+}
+
+// Compact rebuilds the subtrees of the root whose leaves are on average less
+// filled than the threshold, which is a fraction from 0 to 1.
+func (tr *RTreeG[T]) Compact(threshold float64) int {
+	return tr.base.Compact(threshold)
+}
+
+// StartAutoCompaction starts a goroutine that compacts the tree at every
+// interval, and returns a function that stops it.
+// The provided locker must be the one that guards all access to the tree.
+func (tr *RTreeG[T]) StartAutoCompaction(threshold float64,
+	interval time.Duration, mu sync.Locker,
+) (stop func()) {
+	return tr.base.StartAutoCompaction(threshold, interval, mu)
+}
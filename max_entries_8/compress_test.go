@@ -0,0 +1,112 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestCompress(t *testing.T) {
+	var tr RTreeG[int]
+	ct := tr.Compress()
+	if ct.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, ct.Len())
+	}
+	ct.Search([2]float64{-180, -90}, [2]float64{180, 90},
+		func(min, max [2]float64, data int) bool {
+			t.Fatal("expected no items")
+			return true
+		})
+	for i := 0; i < 20000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	ct = tr.Compress()
+	if ct.Len() != tr.Len() {
+		t.Fatalf("expected %d, got %d", tr.Len(), ct.Len())
+	}
+	min, max := ct.Bounds()
+	emin, emax := tr.Bounds()
+	if min != emin || max != emax {
+		t.Fatal("bounds mismatch")
+	}
+	for i := 0; i < 200; i++ {
+		r := randRect('r')
+		r.max[0] += 10
+		r.max[1] += 10
+		var items []int
+		ct.Search(r.min, r.max, func(min, max [2]float64, data int) bool {
+			items = append(items, data)
+			return true
+		})
+		slices.Sort(items)
+		if !slices.Equal(items, bulkSearch(&tr, r)) {
+			t.Fatal("mismatch")
+		}
+	}
+	var count int
+	ct.Scan(func(min, max [2]float64, data int) bool {
+		count++
+		return true
+	})
+	if count != tr.Len() {
+		t.Fatalf("expected %d, got %d", tr.Len(), count)
+	}
+	s := tr.Stats()
+	size := s.Leaves*int(unsafe.Sizeof(leafNode[float64, int]{})) +
+		s.Branches*int(unsafe.Sizeof(branchNode[float64, int]{}))
+	if ct.Size() >= size {
+		t.Fatalf("expected less than %d bytes, got %d", size, ct.Size())
+	}
+}
+
+func TestCompressNumeric(t *testing.T) {
+	testCompressNumeric[int32](t, 1000)
+	testCompressNumeric[uint16](t, 1000)
+	testCompressNumeric[float32](t, 1)
+}
+
+func testCompressNumeric[N numeric](t *testing.T, scale float64) {
+	t.Helper()
+	var tr RTreeGN[N, int]
+	rects := make([]rect[N], 10000)
+	for i := range rects {
+		x, y := N(rand.Float64()*scale*60), N(rand.Float64()*scale*60)
+		rects[i] = rect[N]{[2]N{x, y}, [2]N{x + N(scale), y + N(scale)}}
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	ct := tr.Compress()
+	for i := range rects {
+		var found bool
+		ct.Search(rects[i].min, rects[i].min,
+			func(min, max [2]N, data int) bool {
+				found = found || data == i
+				return !found
+			})
+		if !found {
+			t.Fatalf("item %d not found", i)
+		}
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	for i := 0; i < 100000; i++ {
+		min := rand.Float64()*360 - 180
+		max := min + rand.Float64()*10
+		v := min + rand.Float64()*(max-min)
+		if q := quantizeMin(min, max, v); dequantize(min, max, q) > v {
+			t.Fatalf("%v dequantized to more than %v", q, v)
+		}
+		if q := quantizeMax(min, max, v); dequantize(min, max, q) < v {
+			t.Fatalf("%v dequantized to less than %v", q, v)
+		}
+	}
+	if quantizeMin(5.0, 5.0, 5.0) != 0 || quantizeMax(5.0, 5.0, 5.0) != 0 {
+		t.Fatal("expected zero for an empty range")
+	}
+}
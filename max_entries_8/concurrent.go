@@ -0,0 +1,113 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentRTree is an R-tree that is safe for concurrent use, where
+// readers never wait for writers or for each other.
+// The tree is published as a frozen snapshot. Writers make a copy-on-write
+// copy of the current snapshot, modify it, and then atomically publish it as
+// the new snapshot, while readers keep searching the snapshot that they
+// loaded. Writers wait for each other.
+//
+// Every publish means that the next write copies the nodes that it modifies,
+// so use Update for making many changes at once.
+type ConcurrentRTree[N numeric, T any] struct {
+	mu   sync.Mutex // held by writers
+	snap atomic.Pointer[RTreeGN[N, T]]
+}
+
+// NewConcurrentRTree returns a concurrent tree that starts with the items
+// and the options of the provided tree, such as a tree from New or nil for
+// an empty tree.
+// The provided tree is frozen and must not be used for writing afterwards.
+func NewConcurrentRTree[N numeric, T any](tr *RTreeGN[N, T],
+) *ConcurrentRTree[N, T] {
+	if tr == nil {
+		tr = new(RTreeGN[N, T])
+	}
+	tr.Freeze()
+	ctr := new(ConcurrentRTree[N, T])
+	ctr.snap.Store(tr)
+	return ctr
+}
+
+// Load returns the current snapshot of the tree, which is frozen and can be
+// searched by any number of goroutines. Later writes are not seen by the
+// snapshot.
+func (tr *ConcurrentRTree[N, T]) Load() *RTreeGN[N, T] {
+	return tr.snap.Load()
+}
+
+// Update calls fn with a mutable copy of the current snapshot, and then
+// publishes the copy as the new snapshot. Nothing is published when fn
+// panics.
+// The tree passed to fn must not be used after fn returns.
+func (tr *ConcurrentRTree[N, T]) Update(fn func(tr *RTreeGN[N, T])) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	next := tr.snap.Load().Copy()
+	fn(next)
+	next.Freeze()
+	tr.snap.Store(next)
+}
+
+// Insert data into tree
+func (tr *ConcurrentRTree[N, T]) Insert(min, max [2]N, data T) {
+	tr.Update(func(tr *RTreeGN[N, T]) {
+		tr.Insert(min, max, data)
+	})
+}
+
+// Delete data from tree.
+// Returns false if the item was not found.
+func (tr *ConcurrentRTree[N, T]) Delete(min, max [2]N, data T) bool {
+	var deleted bool
+	tr.Update(func(tr *RTreeGN[N, T]) {
+		deleted = tr.delete(min, max, data, 0)
+	})
+	return deleted
+}
+
+// Replace an item.
+// If the old item does not exist then the new item is not inserted.
+// Returns false if the old item was not found.
+func (tr *ConcurrentRTree[N, T]) Replace(
+	oldMin, oldMax [2]N, oldData T,
+	newMin, newMax [2]N, newData T,
+) bool {
+	var replaced bool
+	tr.Update(func(tr *RTreeGN[N, T]) {
+		if tr.delete(oldMin, oldMax, oldData, 0) {
+			tr.Insert(newMin, newMax, newData)
+			replaced = true
+		}
+	})
+	return replaced
+}
+
+// Len returns the number of items in the current snapshot.
+func (tr *ConcurrentRTree[N, T]) Len() int {
+	return tr.Load().Len()
+}
+
+// Search for items in the current snapshot that intersect the provided
+// rectangle.
+func (tr *ConcurrentRTree[N, T]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	tr.Load().Search(min, max, iter)
+}
+
+// Scan all items in the current snapshot.
+func (tr *ConcurrentRTree[N, T]) Scan(
+	iter func(min, max [2]N, data T) bool,
+) {
+	tr.Load().Scan(iter)
+}
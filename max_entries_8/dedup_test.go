@@ -0,0 +1,122 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+type dedupShape struct {
+	id    int
+	parts []rect[float64]
+}
+
+func TestDedupRTree(t *testing.T) {
+	tr := NewDedupRTreeGN[float64](func(s *dedupShape) int { return s.id })
+	var shapes []*dedupShape
+	var parts int
+	for i := 0; i < 2000; i++ {
+		s := &dedupShape{id: i}
+		for j := rand.Intn(4); j >= 0; j-- {
+			r := randRect('r')
+			s.parts = append(s.parts, r)
+			tr.Insert(r.min, r.max, s)
+			parts++
+		}
+		shapes = append(shapes, s)
+	}
+	if tr.Len() != len(shapes) || tr.Parts() != parts {
+		t.Fatalf("expected %d and %d, got %d and %d", len(shapes), parts,
+			tr.Len(), tr.Parts())
+	}
+	check := func(tr *DedupRTreeGN[float64, *dedupShape, int]) {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			target := randRect('r')
+			target.max[0] += 20
+			target.max[1] += 20
+			expect := make(map[int]bool)
+			tr.ScanParts(func(min, max [2]float64, s *dedupShape) bool {
+				r := rect[float64]{min, max}
+				if r.intersects(&target) {
+					expect[s.id] = true
+				}
+				return true
+			})
+			seen := make(map[int]bool)
+			tr.Search(target.min, target.max,
+				func(min, max [2]float64, s *dedupShape) bool {
+					if seen[s.id] {
+						t.Fatalf("duplicate item %d", s.id)
+					}
+					seen[s.id] = true
+					return true
+				})
+			if len(seen) != len(expect) {
+				t.Fatalf("expected %d, got %d", len(expect), len(seen))
+			}
+		}
+		var count int
+		tr.Scan(func(min, max [2]float64, s *dedupShape) bool {
+			count++
+			return true
+		})
+		if count != tr.Len() {
+			t.Fatalf("expected %d, got %d", tr.Len(), count)
+		}
+	}
+	check(tr)
+
+	// nearest parts first
+	p := [2]float64{10, 20}
+	seen := make(map[int]bool)
+	var last float64
+	tr.Nearby(BoxDist[float64, *dedupShape](p, p, nil),
+		func(_, _ [2]float64, s *dedupShape, dist float64) bool {
+			if seen[s.id] {
+				t.Fatalf("duplicate item %d", s.id)
+			}
+			seen[s.id] = true
+			nearest := dist
+			for _, r := range s.parts {
+				d := BoxDist[float64, *dedupShape](p, p, nil)(r.min, r.max,
+					s, true)
+				nearest = min(nearest, d)
+			}
+			if dist != nearest || dist < last {
+				t.Fatalf("expected %v, got %v", nearest, dist)
+			}
+			last = dist
+			return true
+		})
+	if len(seen) != tr.Len() {
+		t.Fatalf("expected %d, got %d", tr.Len(), len(seen))
+	}
+
+	// delete the first part of every shape
+	tr2 := tr.Copy()
+	var single int
+	for _, s := range shapes {
+		tr.Delete(s.parts[0].min, s.parts[0].max, s)
+		if len(s.parts) == 1 {
+			single++
+		}
+	}
+	tr.Delete([2]float64{}, [2]float64{}, shapes[0])
+	if tr.Len() != len(shapes)-single || tr.Parts() != parts-len(shapes) {
+		t.Fatalf("expected %d and %d, got %d and %d", len(shapes)-single,
+			parts-len(shapes), tr.Len(), tr.Parts())
+	}
+	check(tr)
+	if tr2.Len() != len(shapes) || tr2.Parts() != parts {
+		t.Fatal("the copy was modified")
+	}
+	check(tr2)
+	tr2.Clear()
+	if tr2.Len() != 0 || tr2.Parts() != 0 {
+		t.Fatal("expected an empty tree")
+	}
+}
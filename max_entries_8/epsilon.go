@@ -0,0 +1,66 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// SetEpsilon sets the tolerance that is used when matching the rectangle of
+// an item for Delete and Replace.
+// With a tolerance, an item matches when each of its coordinates is within
+// eps of the provided rectangle, so coordinates that are recomputed, such as
+// from a projection, and differ in the last few bits still find their item.
+// The default of zero requires the item rectangle to be inside of the
+// provided rectangle.
+func (tr *RTreeGN[N, T]) SetEpsilon(eps float64) {
+	if eps < 0 {
+		eps = 0
+	}
+	tr.eps = eps
+}
+
+// Epsilon returns the tolerance that is used when matching the rectangle of
+// an item for Delete and Replace.
+func (tr *RTreeGN[N, T]) Epsilon() float64 {
+	return tr.eps
+}
+
+// near returns true if every coordinate of r is within eps of b.
+func (r *rect[N]) near(b *rect[N], eps float64) bool {
+	for i := 0; i < 2; i++ {
+		if !(absDiff(r.min[i], b.min[i]) <= eps &&
+			absDiff(r.max[i], b.max[i]) <= eps) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsNear returns true if r may contain a rectangle that is near b.
+func (r *rect[N]) containsNear(b *rect[N], eps float64) bool {
+	for i := 0; i < 2; i++ {
+		if !(float64(r.min[i]) <= float64(b.min[i])+eps &&
+			float64(r.max[i]) >= float64(b.max[i])-eps) {
+			return false
+		}
+	}
+	return true
+}
+
+func absDiff[N numeric](a, b N) float64 {
+	if a < b {
+		return float64(b - a)
+	}
+	return float64(a - b)
+}
+
+// SetEpsilon sets the tolerance that is used when matching the rectangle of
+// an item for Delete and Replace.
+func (tr *RTreeG[T]) SetEpsilon(eps float64) {
+	tr.base.SetEpsilon(eps)
+}
+
+// Epsilon returns the tolerance that is used when matching the rectangle of
+// an item for Delete and Replace.
+func (tr *RTreeG[T]) Epsilon() float64 {
+	return tr.base.Epsilon()
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// exportVersion is the first byte of a stream that is written by Export.
+const exportVersion = 1
+
+// Export writes all items to w in chunks of up to chunkItems items, where
+// the data of each item is encoded by the encode function. The items are
+// written in the order of the leaves, like Scan, and each chunk holds the
+// next chunkItems items, so that the items of each chunk are near each other,
+// which allows for consumers to build tiled files directly from the chunks.
+// A chunk may hold the items of more than one leaf, and the items of a leaf
+// may be split across chunks. When chunkItems is less than one, the maximum
+// number of items of a leaf is used.
+//
+// The stream starts with a version byte, which is followed by the chunks.
+// Each chunk is a record, as written by WriteLogRecord, that holds:
+//
+//   - the bounding box of the chunk, as min[0], min[1], max[0], max[1]
+//   - the number of items, as a uvarint
+//   - for each item, its rectangle in the same order as the bounding box,
+//     followed by the length of its encoded data, as a uvarint, and the data
+//
+// Coordinates are written as little-endian IEEE 754 float64 values, which
+// are exact for every coordinate type except 64-bit integers, such as int64
+// and uint64, whose values beyond 2^53 are rounded.
+// The encode function must not modify the tree.
+func (tr *RTreeGN[N, T]) Export(w io.Writer, chunkItems int,
+	encode func(data T) ([]byte, error),
+) error {
+	if chunkItems < 1 {
+		chunkItems = tr.fanout(true)
+	}
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte(exportVersion); err != nil {
+		return err
+	}
+	var body []byte
+	var rec []byte
+	var bbox rect[N]
+	var count int
+	flush := func() error {
+		rec = appendExportRect(rec[:0], bbox)
+		rec = binary.AppendUvarint(rec, uint64(count))
+		rec = append(rec, body...)
+		body = body[:0]
+		count = 0
+		return WriteLogRecord(bw, rec)
+	}
+	var err error
+	tr.Scan(func(min, max [2]N, data T) bool {
+		var b []byte
+		if b, err = encode(data); err != nil {
+			return false
+		}
+		ir := rect[N]{min, max}
+		if count == 0 {
+			bbox = ir
+		} else {
+			bbox.expand(&ir)
+		}
+		body = appendExportRect(body, ir)
+		body = binary.AppendUvarint(body, uint64(len(b)))
+		body = append(body, b...)
+		count++
+		if count == chunkItems {
+			err = flush()
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// appendExportRect appends the rectangle as four float64 values.
+func appendExportRect[N numeric](dst []byte, r rect[N]) []byte {
+	for _, v := range [4]N{r.min[0], r.min[1], r.max[0], r.max[1]} {
+		dst = binary.LittleEndian.AppendUint64(dst,
+			math.Float64bits(float64(v)))
+	}
+	return dst
+}
+
+// Export writes all items to w in spatially clustered chunks.
+// See RTreeGN.Export.
+func (tr *RTreeG[T]) Export(w io.Writer, chunkItems int,
+	encode func(data T) ([]byte, error),
+) error {
+	return tr.base.Export(w, chunkItems, encode)
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestFarthest(t *testing.T) {
+	var tr RTreeG[int]
+	tr.Farthest([2]float64{0, 0}, 10,
+		func(min, max [2]float64, data int, dist float64) bool {
+			t.Fatal("expected no items")
+			return true
+		},
+	)
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('m')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for j := 0; j < 20; j++ {
+		p := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		dists := make([]float64, len(rects))
+		for i := range rects {
+			dists[i] = rects[i].farDist2(p)
+		}
+		sort.Sort(sort.Reverse(sort.Float64Slice(dists)))
+		var got []float64
+		tr.Farthest(p, 25, func(min, max [2]float64, data int,
+			dist float64) bool {
+			if dist != rects[data].farDist2(p) {
+				t.Fatalf("bad distance for %d", data)
+			}
+			got = append(got, dist)
+			return true
+		})
+		if len(got) != 25 {
+			t.Fatalf("expected 25, got %d", len(got))
+		}
+		for i := range got {
+			if got[i] != dists[i] {
+				t.Fatalf("%d: expected %v, got %v", i, dists[i], got[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "slices"
+
+// Polygon is a polygon with an exterior ring and optional holes. Each ring is
+// a list of points, where the last point may or may not be equal to the first
+// point.
+type Polygon struct {
+	Exterior [][2]float64
+	Holes    [][][2]float64
+}
+
+// Contains returns true if the point is inside of the polygon, or on its
+// boundary.
+func (p *Polygon) Contains(point [2]float64) bool {
+	if !ringContains(p.Exterior, point, true) {
+		return false
+	}
+	for _, hole := range p.Holes {
+		if ringContains(hole, point, false) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rect returns the bounding rectangle of the exterior ring.
+func (p *Polygon) Rect() (min, max [2]float64) {
+	if len(p.Exterior) == 0 {
+		return min, max
+	}
+	r := rect[float64]{p.Exterior[0], p.Exterior[0]}
+	for _, point := range p.Exterior[1:] {
+		r.expand(&rect[float64]{point, point})
+	}
+	return r.min, r.max
+}
+
+// ringContains returns true if the point is inside of the ring, using the
+// even-odd rule. A point on an edge of the ring is inside when onEdge is
+// true.
+func ringContains(ring [][2]float64, p [2]float64, onEdge bool) bool {
+	var in bool
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		if onSegment(p, a, b) {
+			return onEdge
+		}
+		if (a[1] > p[1]) != (b[1] > p[1]) &&
+			p[0] < (b[0]-a[0])*(p[1]-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	return in
+}
+
+// onSegment returns true if the point is on the segment from a to b.
+func onSegment(p, a, b [2]float64) bool {
+	cross := (b[0]-a[0])*(p[1]-a[1]) - (b[1]-a[1])*(p[0]-a[0])
+	return cross == 0 &&
+		p[0] >= min(a[0], b[0]) && p[0] <= max(a[0], b[0]) &&
+		p[1] >= min(a[1], b[1]) && p[1] <= max(a[1], b[1])
+}
+
+// Fences is a set of polygon geofences, each with a unique ID, that can be
+// matched against points.
+// The fences are indexed by their bounding rectangles, so only the few
+// fences whose rectangle contains a point are tested against their polygon.
+type Fences[K comparable] struct {
+	base *KeyedRTreeGN[float64, *fence[K], K]
+}
+
+type fence[K comparable] struct {
+	id   K
+	poly Polygon
+}
+
+// NewFences returns a new empty set of fences.
+func NewFences[K comparable]() *Fences[K] {
+	return &Fences[K]{
+		base: NewKeyedRTreeGN[float64](func(f *fence[K]) K { return f.id }),
+	}
+}
+
+// Add a fence, or replace the fence with the same ID.
+// The polygon is not copied and must not be modified afterwards.
+func (fs *Fences[K]) Add(id K, poly Polygon) {
+	min, max := poly.Rect()
+	fs.base.Upsert(min, max, &fence[K]{id, poly})
+}
+
+// Remove the fence with the ID.
+// Returns false if there is no such fence.
+func (fs *Fences[K]) Remove(id K) bool {
+	return fs.base.DeleteByKey(id)
+}
+
+// Get returns the polygon of the fence with the ID.
+func (fs *Fences[K]) Get(id K) (poly Polygon, ok bool) {
+	_, _, f, ok := fs.base.GetByKey(id)
+	if !ok {
+		return poly, false
+	}
+	return f.poly, true
+}
+
+// Len returns the number of fences.
+func (fs *Fences[K]) Len() int {
+	return fs.base.Len()
+}
+
+// Match returns the IDs of the fences that contain the point, in no
+// particular order.
+func (fs *Fences[K]) Match(point [2]float64) []K {
+	var ids []K
+	fs.match(point, func(id K) bool {
+		ids = append(ids, id)
+		return true
+	})
+	return ids
+}
+
+// match calls iter for every fence that contains the point.
+func (fs *Fences[K]) match(point [2]float64, iter func(id K) bool) {
+	fs.base.Search(point, point, func(_, _ [2]float64, f *fence[K]) bool {
+		if !f.poly.Contains(point) {
+			return true
+		}
+		return iter(f.id)
+	})
+}
+
+// FenceEventKind is the kind of a FenceEvent.
+type FenceEventKind int8
+
+const (
+	// FenceEnter is when an object is inside of a fence that it was not
+	// inside of at its previous point.
+	FenceEnter FenceEventKind = iota + 1
+	// FenceExit is when an object is no longer inside of a fence that it
+	// was inside of at its previous point.
+	FenceExit
+	// FenceCross is when an object passed through a fence between its
+	// previous point and its current point, without being inside of the
+	// fence at either point.
+	FenceCross
+)
+
+func (kind FenceEventKind) String() string {
+	switch kind {
+	case FenceEnter:
+		return "enter"
+	case FenceExit:
+		return "exit"
+	case FenceCross:
+		return "cross"
+	}
+	return "unknown"
+}
+
+// FenceEvent is a change of the fences that an object is inside of, as
+// returned by FenceTracker.Track.
+type FenceEvent[K comparable] struct {
+	Fence K
+	Kind  FenceEventKind
+}
+
+// FenceTracker keeps the last point of every tracked object, and the fences
+// that it was inside of, for detecting when objects enter or exit fences.
+// Objects are identified by an ID, such as the ID of a vehicle.
+type FenceTracker[K, ID comparable] struct {
+	fences  *Fences[K]
+	objects map[ID]*fenceObject[K]
+}
+
+type fenceObject[K comparable] struct {
+	point  [2]float64
+	inside []K
+}
+
+// NewFenceTracker returns a new tracker for the fences.
+// Changes to the fences are seen by the next call to Track, so removing a
+// fence makes the objects that were inside of it exit the fence.
+func NewFenceTracker[K, ID comparable](fences *Fences[K],
+) *FenceTracker[K, ID] {
+	return &FenceTracker[K, ID]{
+		fences:  fences,
+		objects: make(map[ID]*fenceObject[K]),
+	}
+}
+
+// Track updates the point of the object with the ID, and returns the
+// fences that it entered, exited, or crossed since its previous point, in
+// no particular order.
+// The first point of an object enters all fences that it is inside of.
+func (ft *FenceTracker[K, ID]) Track(id ID, point [2]float64,
+) []FenceEvent[K] {
+	var events []FenceEvent[K]
+	inside := ft.fences.Match(point)
+	obj, ok := ft.objects[id]
+	if !ok {
+		obj = &fenceObject[K]{}
+		ft.objects[id] = obj
+	}
+	for _, k := range inside {
+		if !slices.Contains(obj.inside, k) {
+			events = append(events, FenceEvent[K]{k, FenceEnter})
+		}
+	}
+	for _, k := range obj.inside {
+		if !slices.Contains(inside, k) {
+			events = append(events, FenceEvent[K]{k, FenceExit})
+		}
+	}
+	if ok && point != obj.point {
+		ft.fences.crossed(obj.point, point, func(k K) bool {
+			if !slices.Contains(obj.inside, k) &&
+				!slices.Contains(inside, k) {
+				events = append(events, FenceEvent[K]{k, FenceCross})
+			}
+			return true
+		})
+	}
+	obj.point = point
+	obj.inside = inside
+	return events
+}
+
+// Inside returns the fences that the object with the ID was inside of at
+// its last point.
+func (ft *FenceTracker[K, ID]) Inside(id ID) []K {
+	if obj, ok := ft.objects[id]; ok {
+		return slices.Clone(obj.inside)
+	}
+	return nil
+}
+
+// Forget stops tracking the object with the ID, without any events.
+func (ft *FenceTracker[K, ID]) Forget(id ID) {
+	delete(ft.objects, id)
+}
+
+// Len returns the number of tracked objects.
+func (ft *FenceTracker[K, ID]) Len() int {
+	return len(ft.objects)
+}
+
+// crossed calls iter for every fence whose boundary is crossed by the
+// segment from a to b.
+func (fs *Fences[K]) crossed(a, b [2]float64, iter func(id K) bool) {
+	r := rect[float64]{a, a}
+	r.expand(&rect[float64]{b, b})
+	fs.base.Search(r.min, r.max, func(_, _ [2]float64, f *fence[K]) bool {
+		if !f.poly.crosses(a, b) {
+			return true
+		}
+		return iter(f.id)
+	})
+}
+
+// crosses returns true if the segment from a to b crosses or touches an edge
+// of the polygon.
+func (p *Polygon) crosses(a, b [2]float64) bool {
+	if ringCrosses(p.Exterior, a, b) {
+		return true
+	}
+	for _, hole := range p.Holes {
+		if ringCrosses(hole, a, b) {
+			return true
+		}
+	}
+	return false
+}
+
+func ringCrosses(ring [][2]float64, a, b [2]float64) bool {
+	for i := range ring {
+		if segmentsIntersect(a, b, ring[i], ring[(i+1)%len(ring)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentsIntersect returns true if the segment from a to b intersects the
+// segment from c to d, including when they only touch.
+func segmentsIntersect(a, b, c, d [2]float64) bool {
+	orient := func(p, q, r [2]float64) float64 {
+		return (q[0]-p[0])*(r[1]-p[1]) - (q[1]-p[1])*(r[0]-p[0])
+	}
+	d1, d2 := orient(c, d, a), orient(c, d, b)
+	d3, d4 := orient(a, b, c), orient(a, b, d)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(a, c, d)) ||
+		(d2 == 0 && onSegment(b, c, d)) ||
+		(d3 == 0 && onSegment(c, a, b)) ||
+		(d4 == 0 && onSegment(d, a, b))
+}
@@ -0,0 +1,184 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testCircle returns a polygon that approximates a circle.
+func testCircle(center [2]float64, radius float64, n int) [][2]float64 {
+	ring := make([][2]float64, n)
+	for i := range ring {
+		a := float64(i) / float64(n) * 2 * math.Pi
+		ring[i] = [2]float64{center[0] + math.Cos(a)*radius,
+			center[1] + math.Sin(a)*radius}
+	}
+	return ring
+}
+
+func TestPolygonContains(t *testing.T) {
+	poly := Polygon{
+		Exterior: [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		Holes:    [][][2]float64{{{4, 4}, {6, 4}, {6, 6}, {4, 6}}},
+	}
+	for _, tc := range []struct {
+		p      [2]float64
+		inside bool
+	}{
+		{[2]float64{1, 1}, true},
+		{[2]float64{5, 5}, false}, // in the hole
+		{[2]float64{11, 5}, false},
+		{[2]float64{0, 5}, true},   // on the exterior
+		{[2]float64{10, 10}, true}, // on a corner
+		{[2]float64{4, 5}, true},   // on the hole
+		{[2]float64{-1, 0}, false},
+	} {
+		if poly.Contains(tc.p) != tc.inside {
+			t.Fatalf("%v: expected %v", tc.p, tc.inside)
+		}
+	}
+	min, max := poly.Rect()
+	if min != [2]float64{0, 0} || max != [2]float64{10, 10} {
+		t.Fatalf("unexpected rect %v %v", min, max)
+	}
+	// concave
+	poly = Polygon{Exterior: [][2]float64{{0, 0}, {10, 0}, {10, 10},
+		{5, 2}, {0, 10}}}
+	if poly.Contains([2]float64{5, 5}) || !poly.Contains([2]float64{5, 1}) {
+		t.Fatal("unexpected result for a concave polygon")
+	}
+}
+
+func TestFences(t *testing.T) {
+	fs := NewFences[int]()
+	polys := make(map[int]Polygon)
+	for i := 0; i < 1000; i++ {
+		c := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		poly := Polygon{Exterior: testCircle(c, rand.Float64()*10, 16)}
+		if i%3 == 0 {
+			poly.Holes = [][][2]float64{testCircle(c, 1, 8)}
+		}
+		fs.Add(i, poly)
+		polys[i] = poly
+	}
+	// replace
+	poly := Polygon{Exterior: testCircle([2]float64{0, 0}, 5, 16)}
+	fs.Add(0, poly)
+	polys[0] = poly
+	if p, ok := fs.Get(0); !ok || len(p.Holes) != 0 {
+		t.Fatal("expected the replaced fence")
+	}
+	if fs.Len() != len(polys) {
+		t.Fatalf("expected %d, got %d", len(polys), fs.Len())
+	}
+	for i := 0; i < 500; i += 2 {
+		if !fs.Remove(i) {
+			t.Fatalf("failed to remove %d", i)
+		}
+		delete(polys, i)
+	}
+	if fs.Remove(0) {
+		t.Fatal("expected no fence")
+	}
+	if _, ok := fs.Get(0); ok {
+		t.Fatal("expected no fence")
+	}
+	for i := 0; i < 1000; i++ {
+		p := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		var expect []int
+		for id, poly := range polys {
+			if poly.Contains(p) {
+				expect = append(expect, id)
+			}
+		}
+		got := fs.Match(p)
+		slices.Sort(expect)
+		slices.Sort(got)
+		if !slices.Equal(got, expect) {
+			t.Fatalf("%v: expected %v, got %v", p, expect, got)
+		}
+	}
+}
+
+func TestFenceTracker(t *testing.T) {
+	fs := NewFences[string]()
+	fs.Add("square", Polygon{
+		Exterior: [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+	})
+	fs.Add("big", Polygon{
+		Exterior: [][2]float64{{-20, -20}, {20, -20}, {20, 20}, {-20, 20}},
+		Holes:    [][][2]float64{{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}}},
+	})
+	ft := NewFenceTracker[string, int](fs)
+	check := func(id int, point [2]float64, expect ...FenceEvent[string]) {
+		t.Helper()
+		events := ft.Track(id, point)
+		cmp := func(a, b FenceEvent[string]) int {
+			return strings.Compare(a.Fence+a.Kind.String(),
+				b.Fence+b.Kind.String())
+		}
+		slices.SortFunc(events, cmp)
+		slices.SortFunc(expect, cmp)
+		if !slices.Equal(events, expect) {
+			t.Fatalf("%v: expected %v, got %v", point, expect, events)
+		}
+	}
+	check(1, [2]float64{-30, 5})
+	check(1, [2]float64{-30, 6})
+	check(1, [2]float64{-10, 5}, FenceEvent[string]{"big", FenceEnter})
+	check(1, [2]float64{5, 5}, FenceEvent[string]{"big", FenceExit},
+		FenceEvent[string]{"square", FenceEnter})
+	if inside := ft.Inside(1); !slices.Equal(inside, []string{"square"}) {
+		t.Fatalf("expected [square], got %v", inside)
+	}
+	check(1, [2]float64{5, 5})
+	// through the big fence and out of the square
+	check(1, [2]float64{30, 5}, FenceEvent[string]{"square", FenceExit},
+		FenceEvent[string]{"big", FenceCross})
+	// through both
+	check(1, [2]float64{-30, 5}, FenceEvent[string]{"square", FenceCross},
+		FenceEvent[string]{"big", FenceCross})
+	// the first point enters
+	check(2, [2]float64{15, 15}, FenceEvent[string]{"big", FenceEnter})
+	fs.Remove("big")
+	check(2, [2]float64{15, 15}, FenceEvent[string]{"big", FenceExit})
+	if ft.Len() != 2 {
+		t.Fatalf("expected 2, got %d", ft.Len())
+	}
+	ft.Forget(2)
+	if ft.Len() != 1 || ft.Inside(2) != nil {
+		t.Fatal("expected the object to be forgotten")
+	}
+	if FenceEventKind(0).String() != "unknown" {
+		t.Fatal("expected unknown")
+	}
+}
+
+func TestSegmentsIntersect(t *testing.T) {
+	for _, tc := range []struct {
+		a, b, c, d [2]float64
+		expect     bool
+	}{
+		{[2]float64{0, 0}, [2]float64{10, 10}, [2]float64{0, 10},
+			[2]float64{10, 0}, true},
+		{[2]float64{0, 0}, [2]float64{4, 4}, [2]float64{0, 10},
+			[2]float64{10, 0}, false},
+		{[2]float64{0, 0}, [2]float64{5, 5}, [2]float64{0, 10},
+			[2]float64{10, 0}, true}, // touching
+		{[2]float64{0, 0}, [2]float64{10, 0}, [2]float64{5, 0},
+			[2]float64{20, 0}, true}, // collinear
+		{[2]float64{0, 0}, [2]float64{10, 0}, [2]float64{11, 0},
+			[2]float64{20, 0}, false},
+	} {
+		if segmentsIntersect(tc.a, tc.b, tc.c, tc.d) != tc.expect {
+			t.Fatalf("%v: expected %v", tc, tc.expect)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// ErrInvalidLatLon is returned when a latitude is not within [-90, 90] or a
+// longitude is not within [-180, 180].
+var ErrInvalidLatLon = errors.New("rtree: invalid lat/lon")
+
+// LatLon is a WGS84 point in degrees.
+type LatLon struct {
+	Lat, Lon float64
+}
+
+// valid returns true if the point is within the range of the lat/lon
+// degrees.
+func (p LatLon) valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+// xy returns the point with the longitude on the X axis and the latitude on
+// the Y axis.
+func (p LatLon) xy() [2]float64 {
+	return [2]float64{p.Lon, p.Lat}
+}
+
+func latLonOf(xy [2]float64) LatLon {
+	return LatLon{Lat: xy[1], Lon: xy[0]}
+}
+
+// GeoTree is an R-tree for WGS84 lat/lon data, which stores the longitude
+// on the X axis and the latitude on the Y axis, so that the order of the axes
+// is never mixed up by the caller.
+//
+// Items are points or bounding boxes, described by their south-west and
+// north-east corners. A box whose west longitude is greater than its east
+// longitude spans the antimeridian, such as from 170 to -170.
+type GeoTree[T any] struct {
+	proj Projection
+	base AntimeridianRTreeG[T]
+}
+
+// geoBox returns the rect of a box, or false if the box is not valid.
+func geoBox(sw, ne LatLon) (min, max [2]float64, ok bool) {
+	if !sw.valid() || !ne.valid() || sw.Lat > ne.Lat {
+		return min, max, false
+	}
+	return sw.xy(), ne.xy(), true
+}
+
+// InsertLatLon inserts data for a point into the tree.
+// Returns ErrInvalidLatLon if the point is not valid.
+func (tr *GeoTree[T]) InsertLatLon(p LatLon, data T) error {
+	return tr.InsertBBox(p, p, data)
+}
+
+// InsertBBox inserts data for a bounding box into the tree.
+// Returns ErrInvalidLatLon if a corner is not valid or when the south
+// latitude is greater than the north latitude.
+func (tr *GeoTree[T]) InsertBBox(sw, ne LatLon, data T) error {
+	min, max, ok := geoBox(sw, ne)
+	if !ok {
+		return ErrInvalidLatLon
+	}
+	tr.base.Insert(min, max, data)
+	return nil
+}
+
+// DeleteLatLon deletes data for a point from the tree.
+func (tr *GeoTree[T]) DeleteLatLon(p LatLon, data T) {
+	tr.DeleteBBox(p, p, data)
+}
+
+// DeleteBBox deletes data for a bounding box from the tree.
+func (tr *GeoTree[T]) DeleteBBox(sw, ne LatLon, data T) {
+	if min, max, ok := geoBox(sw, ne); ok {
+		tr.base.Delete(min, max, data)
+	}
+}
+
+// Len returns the number of items in tree.
+func (tr *GeoTree[T]) Len() int {
+	return tr.base.Len()
+}
+
+// SearchBBox searches for items that intersect the bounding box.
+// Nothing is found when the box is not valid.
+func (tr *GeoTree[T]) SearchBBox(sw, ne LatLon,
+	iter func(sw, ne LatLon, data T) bool,
+) {
+	min, max, ok := geoBox(sw, ne)
+	if !ok {
+		return
+	}
+	tr.base.Search(min, max, func(min, max [2]float64, data T) bool {
+		return iter(latLonOf(min), latLonOf(max), data)
+	})
+}
+
+// SearchRadiusMeters searches for items that are within the provided
+// distance, in meters, from the center, using the great-circle distance.
+// Nothing is found when the center is not valid.
+func (tr *GeoTree[T]) SearchRadiusMeters(center LatLon, meters float64,
+	iter func(sw, ne LatLon, data T) bool,
+) {
+	if !center.valid() || !(meters >= 0) {
+		return
+	}
+	boxes, n := geoBounds(center.Lat, center.Lon, meters)
+	target := boxes[0]
+	if n == 2 {
+		// one box that spans the antimeridian
+		target.max[0] = boxes[1].max[0]
+	}
+	tr.base.Search(target.min, target.max,
+		func(min, max [2]float64, data T) bool {
+			if geoBoxDist(center, min, max) > meters {
+				return true
+			}
+			return iter(latLonOf(min), latLonOf(max), data)
+		},
+	)
+}
+
+// geoBoxDist returns the great-circle distance, in meters, from the point to
+// the nearest point of the box, which may span the antimeridian.
+func geoBoxDist(p LatLon, min, max [2]float64) float64 {
+	rects, n := amSplit(min, max)
+	dist := math.Inf(1)
+	for i := 0; i < n; i++ {
+		dist = math.Min(dist, geoDist(p.Lat, p.Lon, &rects[i]))
+	}
+	return dist
+}
+
+// Scan all items in the tree.
+func (tr *GeoTree[T]) Scan(iter func(sw, ne LatLon, data T) bool) {
+	tr.base.Scan(func(min, max [2]float64, data T) bool {
+		return iter(latLonOf(min), latLonOf(max), data)
+	})
+}
+
+// Copy the tree.
+// This is a copy-on-write operation and is very fast because it only performs
+// a shadowed copy.
+func (tr *GeoTree[T]) Copy() *GeoTree[T] {
+	return &GeoTree[T]{proj: tr.proj, base: *tr.base.Copy()}
+}
+
+// Clear will delete all items.
+func (tr *GeoTree[T]) Clear() {
+	tr.base.Clear()
+}
+
+// Nearby returns the items in order of their great-circle distance, in
+// meters, from the point, nearest first.
+// The distance to each node is the distance to the nearest point of its
+// lat/lon box, which accounts for the poles and the antimeridian, so the
+// order is correct at all latitudes, unlike planar distances of degrees.
+// Nothing is found when the point is not valid.
+func (tr *GeoTree[T]) Nearby(p LatLon,
+	iter func(sw, ne LatLon, data T, meters float64) bool,
+) {
+	if !p.valid() {
+		return
+	}
+	// The ids of the items that span the antimeridian whose other part has
+	// not been reached yet.
+	var parts []uint64
+	tr.base.base.Nearby(
+		func(min, max [2]float64, _ amItem[T], _ bool) float64 {
+			r := rect[float64]{min, max}
+			return geoDist(p.Lat, p.Lon, &r)
+		},
+		func(min, max [2]float64, item amItem[T], meters float64) bool {
+			min, max = item.original(min, max)
+			if item.part != amWhole {
+				// The nearest part is reached first, so the item is only
+				// returned for that one.
+				if i := slices.Index(parts, item.id); i >= 0 {
+					parts = slices.Delete(parts, i, i+1)
+					return true
+				}
+				parts = append(parts, item.id)
+			}
+			return iter(latLonOf(min), latLonOf(max), item.data, meters)
+		},
+	)
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestItemHandle(t *testing.T) {
+	var tr RTreeG[int]
+	var handles []ItemHandle[float64]
+	N := 10000
+	for i := 0; i < N; i++ {
+		// lots of identical items
+		r := randRect('m')
+		if i%2 == 1 {
+			r = rect[float64]{[2]float64{1, 2}, [2]float64{3, 4}}
+		}
+		if i%3 == 0 {
+			// mix in plain items
+			tr.Insert(r.min, r.max, 1)
+		}
+		handles = append(handles, tr.InsertHandle(r.min, r.max, 1))
+	}
+	total := tr.Len()
+	tr2 := tr.Copy()
+	rand.Shuffle(len(handles), func(i, j int) {
+		handles[i], handles[j] = handles[j], handles[i]
+	})
+	for i, h := range handles {
+		if !tr.DeleteHandle(h) {
+			t.Fatalf("handle %d not found", i)
+		}
+		if tr.DeleteHandle(h) {
+			t.Fatalf("handle %d deleted twice", i)
+		}
+		if tr.Len() != total-i-1 {
+			t.Fatalf("expected %d, got %d", total-i-1, tr.Len())
+		}
+	}
+	if tr.Len() != total-N {
+		t.Fatalf("expected %d, got %d", total-N, tr.Len())
+	}
+	// the copy is unchanged and also accepts the handles
+	if tr2.Len() != total {
+		t.Fatalf("expected %d, got %d", total, tr2.Len())
+	}
+	for i, h := range handles {
+		if !tr2.DeleteHandle(h) {
+			t.Fatalf("handle %d not found", i)
+		}
+	}
+	if tr2.Len() != total-N {
+		t.Fatalf("expected %d, got %d", total-N, tr2.Len())
+	}
+	if tr.DeleteHandle(ItemHandle[float64]{}) {
+		t.Fatal("zero handle deleted an item")
+	}
+}
+
+func TestItemHandleNotComparable(t *testing.T) {
+	var tr RTreeG[[]int]
+	h := tr.InsertHandle([2]float64{1, 1}, [2]float64{1, 1}, []int{1})
+	tr.InsertHandle([2]float64{1, 1}, [2]float64{1, 1}, []int{2})
+	if !tr.DeleteHandle(h) {
+		t.Fatal("handle not found")
+	}
+	var items []int
+	tr.Scan(func(min, max [2]float64, data []int) bool {
+		items = append(items, data...)
+		return true
+	})
+	if len(items) != 1 || items[0] != 2 {
+		t.Fatalf("expected %v, got %v", []int{2}, items)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"math"
+)
+
+// hashAgg is the aggregator that maintains the content hash of every node,
+// which is the sum of the hashes of its items.
+type hashAgg[N numeric, T any] struct {
+	idx    int
+	encode func(data T) []byte
+}
+
+func (ha *hashAgg[N, T]) fix(n *node[N, T]) {
+	var h uint64
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			h += hashItem(n.rects.at(i), ha.encode(items[i]))
+		}
+	} else {
+		children := n.children()[:n.count]
+		for i := range children {
+			h += children[i].aggs.vals[ha.idx].(uint64)
+		}
+	}
+	n.aggs.vals[ha.idx] = h
+}
+
+// hashItem returns the hash of an item with its data encoded as bytes.
+func hashItem[N numeric](r rect[N], data []byte) uint64 {
+	h := uint64(14695981039346656037)
+	for _, v := range [4]N{r.min[0], r.min[1], r.max[0], r.max[1]} {
+		h = (h ^ math.Float64bits(float64(v))) * 1099511628211
+	}
+	for _, b := range data {
+		h = (h ^ uint64(b)) * 1099511628211
+	}
+	// mix the bits, so that adding the hashes of items doesn't cancel out
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h
+}
+
+// SetHash sets the function that encodes the data of an item for Hash.
+// The hash of every node is maintained as items are inserted and deleted,
+// like SetWeight, so that Hash answers without visiting every item, and
+// copies of the tree keep the hashes of the nodes that they share.
+// Passing nil removes the function.
+func (tr *RTreeGN[N, T]) SetHash(encode func(data T) []byte) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.hash != nil {
+		tr.removeAggregator(tr.hash.idx)
+		tr.hash = nil
+	}
+	if encode != nil {
+		ha := &hashAgg[N, T]{encode: encode}
+		tr.addAggregator(ha, &ha.idx)
+		tr.hash = ha
+	}
+}
+
+// Hash returns a hash of the content of the tree, which is all of the
+// rectangles and encoded data of its items. The hash doesn't depend on the
+// structure of the tree, such as the order in which the items were inserted,
+// so replicas of a tree can compare their hashes to check that they hold the
+// same items.
+//
+// The data is encoded by the function provided to SetHash. Without one, the
+// data is formatted using fmt's %v, which is only suitable for basic types,
+// such as strings and numbers, and every item is visited.
+func (tr *RTreeGN[N, T]) Hash() uint64 {
+	if tr.root == nil {
+		return 0
+	}
+	if tr.hash != nil {
+		return tr.root.aggs.vals[tr.hash.idx].(uint64)
+	}
+	var h uint64
+	var buf []byte
+	tr.Scan(func(min, max [2]N, data T) bool {
+		buf = fmt.Appendf(buf[:0], "%v", data)
+		h += hashItem(rect[N]{min, max}, buf)
+		return true
+	})
+	return h
+}
+
+// SetHash sets the function that encodes the data of an item for Hash.
+// Passing nil removes the function.
+func (tr *RTreeG[T]) SetHash(encode func(data T) []byte) {
+	tr.base.SetHash(encode)
+}
+
+// Hash returns a hash of the content of the tree.
+// See RTreeGN.Hash.
+func (tr *RTreeG[T]) Hash() uint64 {
+	return tr.base.Hash()
+}
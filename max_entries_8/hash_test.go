@@ -0,0 +1,71 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	var tr1, tr2 RTreeG[int]
+	if tr1.Hash() != 0 {
+		t.Fatal("expected zero hash for an empty tree")
+	}
+	encode := func(data int) []byte { return strconv.AppendInt(nil, int64(data), 10) }
+	tr2.SetHash(encode)
+	N := 5000
+	rects := make([]rect[float64], N)
+	for i := 0; i < N; i++ {
+		rects[i] = randRect('m')
+		tr1.Insert(rects[i].min, rects[i].max, i)
+	}
+	// insert the same items in a different order
+	for _, i := range rand.Perm(N) {
+		tr2.Insert(rects[i].min, rects[i].max, i)
+	}
+	if tr1.Hash() == 0 || tr1.Hash() != tr2.Hash() {
+		t.Fatalf("expected equal hashes, got %d and %d", tr1.Hash(), tr2.Hash())
+	}
+	// a copy shares the cached hashes, until it's modified
+	snap := tr2.Copy()
+	tr2.Delete(rects[0].min, rects[0].max, 0)
+	if tr2.Hash() == snap.Hash() {
+		t.Fatal("expected different hashes after delete")
+	}
+	if snap.Hash() != tr1.Hash() {
+		t.Fatal("expected the copy to keep its hash")
+	}
+	tr2.Insert(rects[0].min, rects[0].max, 0)
+	if tr2.Hash() != tr1.Hash() {
+		t.Fatal("expected equal hashes after insert")
+	}
+	// the same data with a different rectangle
+	tr2.Delete(rects[1].min, rects[1].max, 1)
+	tr2.Insert(rects[2].min, rects[2].max, 1)
+	if tr2.Hash() == tr1.Hash() {
+		t.Fatal("expected different hashes after moving an item")
+	}
+	var tr3 RTreeG[int]
+	tr3.SetHash(encode)
+	var items []Item[float64, int]
+	tr1.Scan(func(min, max [2]float64, data int) bool {
+		items = append(items, Item[float64, int]{min, max, data})
+		return true
+	})
+	tr3.LoadBulk(items)
+	if tr3.Hash() != tr1.Hash() {
+		t.Fatal("expected equal hashes after bulk load")
+	}
+	tr3.SetHash(nil)
+	if tr3.Hash() != tr1.Hash() {
+		t.Fatal("expected equal hashes after removing the encoder")
+	}
+	tr3.Clear()
+	if tr3.Hash() != 0 {
+		t.Fatal("expected zero hash after clear")
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestSearchItems(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	var dst []Item[float64, int]
+	for j := 0; j < 20; j++ {
+		r := randRect('r')
+		r.max[0] += 20
+		r.max[1] += 20
+		dst = tr.SearchItems(dst[:0], r.min, r.max)
+		var got []int
+		for _, item := range dst {
+			min, max := item.Rect()
+			if min != rects[item.Data].min || max != rects[item.Data].max {
+				t.Fatal("bad rect")
+			}
+			got = append(got, item.Data)
+		}
+		slices.Sort(got)
+		expect := bulkSearch(&tr, r)
+		if !slices.Equal(got, expect) {
+			t.Fatal("mismatch")
+		}
+		got = got[:0]
+		for item := range tr.SearchIter(r.min, r.max) {
+			got = append(got, item.Data)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, expect) {
+			t.Fatal("mismatch")
+		}
+	}
+	// sort the results by their rectangles
+	dst = tr.SearchItems(dst[:0], [2]float64{-180, -90}, [2]float64{180, 90})
+	byMinY := func(a, b Item[float64, int]) int {
+		return cmp.Compare(a.Min[1], b.Min[1])
+	}
+	slices.SortFunc(dst, byMinY)
+	if len(dst) != len(rects) || !slices.IsSortedFunc(dst, byMinY) {
+		t.Fatal("expected all items")
+	}
+	var count int
+	for range tr.SearchIter([2]float64{-180, -90}, [2]float64{180, 90}) {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Fatalf("expected 10, got %d", count)
+	}
+}
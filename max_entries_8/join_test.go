@@ -0,0 +1,73 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestJoinWithin(t *testing.T) {
+	var tr1, tr2 RTreeG[int]
+	rects1 := make([]rect[float64], 3000)
+	for i := range rects1 {
+		rects1[i] = randRect('r')
+		tr1.Insert(rects1[i].min, rects1[i].max, i)
+	}
+	// a much smaller tree, so the heights differ
+	rects2 := make([]rect[float64], 50)
+	for i := range rects2 {
+		rects2[i] = randRect('p')
+		tr2.Insert(rects2[i].min, rects2[i].max, i)
+	}
+	if tr1.base.root.height() == tr2.base.root.height() {
+		t.Fatal("expected different heights")
+	}
+	for _, maxDist := range []float64{0, 1, 5} {
+		type pair struct{ a, b int }
+		found := make(map[pair]bool)
+		tr1.JoinWithin(&tr2, maxDist, func(aMin, aMax [2]float64, a int,
+			bMin, bMax [2]float64, b int) bool {
+			if found[pair{a, b}] {
+				t.Fatalf("duplicate pair %d %d", a, b)
+			}
+			found[pair{a, b}] = true
+			return true
+		})
+		var expect int
+		for i := range rects1 {
+			for j := range rects2 {
+				if rects1[i].boxDist2(&rects2[j]) <= maxDist*maxDist {
+					expect++
+					if !found[pair{i, j}] {
+						t.Fatalf("pair %d %d not found", i, j)
+					}
+				}
+			}
+		}
+		if len(found) != expect {
+			t.Fatalf("expected %d, got %d", expect, len(found))
+		}
+		if maxDist == 5 && expect == 0 {
+			t.Fatal("expected pairs")
+		}
+		// the other way around
+		var count int
+		tr2.JoinWithin(&tr1, maxDist, func(aMin, aMax [2]float64, a int,
+			bMin, bMax [2]float64, b int) bool {
+			if !found[pair{b, a}] {
+				t.Fatalf("pair %d %d not expected", b, a)
+			}
+			count++
+			return true
+		})
+		if count != expect {
+			t.Fatalf("expected %d, got %d", expect, count)
+		}
+	}
+	var empty RTreeG[int]
+	empty.JoinWithin(&tr1, 10, func(aMin, aMax [2]float64, a int,
+		bMin, bMax [2]float64, b int) bool {
+		t.Fatal("expected no pairs")
+		return true
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaxItems(t *testing.T) {
+	tr := New(WithMaxItems[float64, int](100))
+	for i := 0; i < 100; i++ {
+		r := randRect('r')
+		if err := tr.TryInsert(r.min, r.max, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := randRect('r')
+	if err := tr.TryInsert(r.min, r.max, 100); !errors.Is(err, ErrCapacity) {
+		t.Fatalf("expected %v, got %v", ErrCapacity, err)
+	}
+	if tr.Len() != 100 {
+		t.Fatalf("expected %d, got %d", 100, tr.Len())
+	}
+	var min, max [2]float64
+	var data int
+	tr.Scan(func(amin, amax [2]float64, adata int) bool {
+		min, max, data = amin, amax, adata
+		return false
+	})
+	tr.Delete(min, max, data)
+	if err := tr.TryInsert(r.min, r.max, 100); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaxMemory(t *testing.T) {
+	var empty RTreeGN[float64, int]
+	if empty.MemoryUsage() <= 0 {
+		t.Fatalf("expected a positive size, got %d", empty.MemoryUsage())
+	}
+	const limit = 1 << 20
+	tr := New(WithMaxMemory[float64, int](limit))
+	var err error
+	for i := 0; err == nil; i++ {
+		r := randRect('r')
+		err = tr.TryInsert(r.min, r.max, i)
+		if i > 1e6 {
+			t.Fatal("expected the limit to be reached")
+		}
+	}
+	if !errors.Is(err, ErrCapacity) {
+		t.Fatalf("expected %v, got %v", ErrCapacity, err)
+	}
+	// the limit is approximate, but should be close
+	size := tr.MemoryUsage()
+	if size < limit*9/10 || size > limit*11/10 {
+		t.Fatalf("expected about %d bytes, got %d", limit, size)
+	}
+	if err := rSane(&RTreeG[int]{base: *tr}); err != nil {
+		t.Fatal(err)
+	}
+}
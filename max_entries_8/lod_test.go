@@ -0,0 +1,78 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSearchLOD(t *testing.T) {
+	var tr RTreeG[int]
+	expectPanic(t, func() {
+		tr.SearchLOD([2]float64{}, [2]float64{}, 1, nil, nil)
+	})
+	N := 10000
+	prios := make([]float64, N)
+	items := make([]Item[float64, int], N)
+	for i := 0; i < N; i++ {
+		prios[i] = rand.Float64()
+		r := randRect('m')
+		items[i] = Item[float64, int]{r.min, r.max, i}
+		tr.Insert(r.min, r.max, i)
+	}
+	priority := func(data int) float64 { return prios[data] }
+	tr.SetScore(priority)
+	for i := 0; i < 100; i++ {
+		q := randRect('r')
+		q.max[0] = q.min[0] + rand.Float64()*100
+		q.max[1] = q.min[1] + rand.Float64()*50
+		maxResults := rand.Intn(50) + 1
+
+		// the highest priority item of each cell, by brute force
+		var all []Item[float64, int]
+		for _, item := range items {
+			ir := rect[float64]{item.Min, item.Max}
+			if ir.intersects(&q) {
+				all = append(all, item)
+			}
+		}
+		slices.SortFunc(all, func(a, b Item[float64, int]) int {
+			return cmp.Compare(prios[b.Data], prios[a.Data])
+		})
+		size := 1
+		for size*size < maxResults {
+			size++
+		}
+		g := lodGrid[float64]{target: q, size: size}
+		filled := make(map[int]bool)
+		var expect []int
+		for _, item := range all {
+			ir := rect[float64]{item.Min, item.Max}
+			cell := g.cell(&ir, 1)*size + g.cell(&ir, 0)
+			if !filled[cell] && len(expect) < maxResults {
+				filled[cell] = true
+				expect = append(expect, item.Data)
+			}
+		}
+
+		for _, p := range []func(int) float64{nil, priority} {
+			var got []int
+			tr.SearchLOD(q.min, q.max, maxResults, p,
+				func(min, max [2]float64, data int, prio float64) bool {
+					if prio != prios[data] {
+						t.Fatalf("expected %v, got %v", prios[data], prio)
+					}
+					got = append(got, data)
+					return true
+				})
+			if !slices.Equal(got, expect) {
+				t.Fatalf("expected %v, got %v", expect, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Op is a write operation on a tree, see SetLogger.
+type Op int8
+
+const (
+	// OpInsert is an item that was inserted.
+	OpInsert Op = iota + 1
+	// OpDelete is an item that was deleted.
+	OpDelete
+	// OpClear is all items being deleted by Clear.
+	OpClear
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpInsert:
+		return "insert"
+	case OpDelete:
+		return "delete"
+	case OpClear:
+		return "clear"
+	}
+	return "unknown"
+}
+
+// SetLogger sets a function that is called for every item that is inserted
+// into or deleted from the tree, such as for writing the operations to a
+// write-ahead log, so that the tree can be rebuilt by applying them again.
+// Passing nil removes the logger.
+//
+// Replace is logged as an OpDelete of the old item and an OpInsert of the
+// new item. Deletes are logged with the rectangle and data of the item that
+// was found in the tree, which may differ from the ones that were passed to
+// Delete, such as for DeleteHandle or when using an epsilon. Clear is logged
+// as a single OpClear with zero values.
+//
+// The logger is called while the tree is being modified and must not access
+// the tree. Copies of the tree share the same logger.
+func (tr *RTreeGN[N, T]) SetLogger(logger func(op Op, min, max [2]N, data T)) {
+	tr.logger = logger
+}
+
+// logging returns true when the tree has a logger or subscribers, in which
+// case the writes call log.
+func (tr *RTreeGN[N, T]) logging() bool {
+	return tr.logger != nil || tr.subs != nil
+}
+
+// log calls the logger, if any, and notifies the subscribers.
+// It's kept out of line, as most trees never log.
+//
+//go:noinline
+func (tr *RTreeGN[N, T]) log(op Op, min, max [2]N, data T) {
+	if tr.logger != nil {
+		tr.logger(op, min, max, data)
+	}
+	if tr.subs != nil {
+		tr.subs.notify(op, min, max, data)
+	}
+}
+
+// SetLogger sets a function that is called for every item that is inserted
+// into or deleted from the tree. Passing nil removes the logger.
+// See RTreeGN.SetLogger.
+func (tr *RTreeG[T]) SetLogger(
+	logger func(op Op, min, max [2]float64, data T),
+) {
+	tr.base.SetLogger(logger)
+}
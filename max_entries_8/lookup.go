@@ -0,0 +1,42 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// Exists returns true if an item with exactly the same rectangle and data
+// exists in the tree.
+func (tr *RTreeGN[N, T]) Exists(min, max [2]N, data T) bool {
+	_, ok := tr.GetEqual(min, max, func(item T) bool {
+		return tr.equal(item, data)
+	})
+	return ok
+}
+
+// GetEqual returns the first item with exactly the same rectangle as the
+// provided one that matches, or false when there is no such item.
+// A nil match function matches any item.
+func (tr *RTreeGN[N, T]) GetEqual(min, max [2]N, match func(data T) bool,
+) (T, bool) {
+	if tr.root == nil {
+		return tr.empty, false
+	}
+	if match == nil {
+		match = func(T) bool { return true }
+	}
+	ir := rect[N]{min, max}
+	return tr.root.findEqual(&ir, match)
+}
+
+// Exists returns true if an item with exactly the same rectangle and data
+// exists in the tree.
+func (tr *RTreeG[T]) Exists(min, max [2]float64, data T) bool {
+	return tr.base.Exists(min, max, data)
+}
+
+// GetEqual returns the first item with exactly the same rectangle as the
+// provided one that matches, or false when there is no such item.
+func (tr *RTreeG[T]) GetEqual(min, max [2]float64, match func(data T) bool,
+) (T, bool) {
+	return tr.base.GetEqual(min, max, match)
+}
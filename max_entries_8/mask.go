@@ -0,0 +1,88 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "errors"
+
+var errNoMask = errors.New("rtree: no mask function")
+
+// SetMask sets the function that returns the category bitmask of an item,
+// such as a bit for each kind of point of interest.
+// The union and the intersection of the masks of every node are maintained
+// as items are inserted and deleted, which is used by SearchMask to skip
+// nodes that don't have any of the requested categories, or where every item
+// has an excluded category.
+// Passing nil removes the mask function.
+func (tr *RTreeGN[N, T]) SetMask(mask func(data T) uint64) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.mask != nil {
+		tr.removeAggregator(tr.mask.idx)
+		tr.mask = nil
+	}
+	if mask != nil {
+		ai := &aggIndex[N, T, [2]uint64]{agg: Aggregator[T, [2]uint64]{
+			Item: func(data T) [2]uint64 {
+				m := mask(data)
+				return [2]uint64{m, m}
+			},
+			Merge: func(a, b [2]uint64) [2]uint64 {
+				return [2]uint64{a[0] | b[0], a[1] & b[1]}
+			},
+		}}
+		tr.addAggregator(ai, &ai.idx)
+		tr.mask = ai
+	}
+}
+
+// maskMatch returns true when a mask, with the union or and the intersection
+// and of the masks of the items, may have a matching item.
+func maskMatch(or, and, include, exclude uint64) bool {
+	return (include == 0 || or&include != 0) && and&exclude == 0
+}
+
+// SearchMask searches for items that intersect the provided rectangle and
+// whose mask, from the function provided to SetMask, has any of the bits of
+// include and none of the bits of exclude. An include of zero matches every
+// mask.
+// Nodes without any of the included bits, and nodes where every item has an
+// excluded bit, are skipped, which makes searching for rare categories in a
+// large area much faster than filtering the results of Search.
+//
+// Panics if no mask function has been set.
+func (tr *RTreeGN[N, T]) SearchMask(min, max [2]N, include, exclude uint64,
+	iter func(min, max [2]N, data T) bool,
+) {
+	if tr.mask == nil {
+		panic(errNoMask)
+	}
+	mask := tr.mask.agg.Item
+	tr.mask.search(tr, &rect[N]{min, max},
+		func(m [2]uint64) bool {
+			return maskMatch(m[0], m[1], include, exclude)
+		},
+		func(data T) bool {
+			m := mask(data)
+			return maskMatch(m[0], m[1], include, exclude)
+		},
+		iter,
+	)
+}
+
+// SetMask sets the function that returns the category bitmask of an item.
+// Passing nil removes the mask function.
+func (tr *RTreeG[T]) SetMask(mask func(data T) uint64) {
+	tr.base.SetMask(mask)
+}
+
+// SearchMask searches for items that intersect the provided rectangle and
+// whose mask has any of the bits of include and none of the bits of exclude.
+// See RTreeGN.SearchMask.
+func (tr *RTreeG[T]) SearchMask(min, max [2]float64, include, exclude uint64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.SearchMask(min, max, include, exclude, iter)
+}
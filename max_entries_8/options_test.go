@@ -0,0 +1,217 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// testOptions fills a tree that uses the options and an expected tree with
+// the default options, deletes half of the items, and checks that both trees
+// return the same items.
+func testOptions(t *testing.T, opts ...Option[float64, int]) *RTreeG[int] {
+	t.Helper()
+	var tr, expect RTreeG[int]
+	tr.base = *New(opts...)
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+		expect.Insert(rects[i].min, rects[i].max, i)
+	}
+	for i := 0; i < len(rects); i += 2 {
+		tr.Delete(rects[i].min, rects[i].max, i)
+		expect.Delete(rects[i].min, rects[i].max, i)
+	}
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Len() != expect.Len() {
+		t.Fatalf("expected %d items, got %d", expect.Len(), tr.Len())
+	}
+	for j := 0; j < 50; j++ {
+		r := randRect('r')
+		r.max[0] += 10
+		r.max[1] += 10
+		if !slices.Equal(bulkSearch(&tr, r), bulkSearch(&expect, r)) {
+			t.Fatal("mismatch")
+		}
+	}
+	return &tr
+}
+
+func TestNew(t *testing.T) {
+	tr := New[float64, int]()
+	if tr.Len() != 0 || tr.unordered || tr.minFill != 0 || tr.eq != nil {
+		t.Fatal("expected the default options")
+	}
+	split := QuadraticSplitter[float64, int]()
+	alloc := NewPoolAllocator[float64, int]()
+	tr = New(WithSplitter(split), WithPoolAllocator(alloc),
+		WithChooseSubtree[float64, int](LeastOverlap))
+	if tr.split != split || tr.alloc != nodeAllocator[float64, int](alloc) ||
+		tr.choose != LeastOverlap {
+		t.Fatal("expected the options to be set")
+	}
+	testOptions(t, WithSplitter(split), WithPoolAllocator(alloc),
+		WithChooseSubtree[float64, int](LeastOverlap))
+}
+
+func TestWithOrdering(t *testing.T) {
+	tr := testOptions(t, WithOrdering[float64, int](false))
+	var unordered bool
+	var check func(n *node[float64, int])
+	check = func(n *node[float64, int]) {
+		if n.ordered() {
+			t.Fatal("expected an unordered node")
+		}
+		unordered = unordered || !n.issorted()
+		if n.leaf() {
+			return
+		}
+		for _, child := range n.children()[:n.count] {
+			check(child)
+		}
+	}
+	check(tr.base.root)
+	if !unordered {
+		t.Fatal("expected some nodes to not be sorted")
+	}
+	testOptions(t, WithOrdering[float64, int](true))
+}
+
+func TestWithMinFill(t *testing.T) {
+	tr := testOptions(t, WithMinFill[float64, int](40))
+	min := maxEntries * 40 / 100
+	if tr.base.minFill != min {
+		t.Fatalf("expected %d, got %d", min, tr.base.minFill)
+	}
+	// The nodes that were left underfilled by the deletes were removed, so
+	// the leaves are fuller than with the default.
+	def := testOptions(t)
+	if !(tr.Stats().LeafFill > def.Stats().LeafFill) {
+		t.Fatalf("expected a leaf fill above %v, got %v",
+			def.Stats().LeafFill, tr.Stats().LeafFill)
+	}
+	if New(WithMinFill[float64, int](80)).minFill != maxEntries/2 {
+		t.Fatal("expected the min fill to be clamped")
+	}
+	if New(WithMinFill[float64, int](-1)).minFill != 0 {
+		t.Fatal("expected the min fill to be clamped")
+	}
+}
+
+func TestWithFanout(t *testing.T) {
+	leafFanout, branchFanout := maxEntries, max(maxEntries/4, 4)
+	tr := testOptions(t, WithFanout[float64, int](leafFanout, branchFanout),
+		WithMinFill[float64, int](40))
+	check := func(tr *RTreeGN[float64, int], full bool) {
+		t.Helper()
+		if err := tr.SanityCheck(); err != nil {
+			t.Fatal(err)
+		}
+		var maxLeaf, maxBranch int
+		var visit func(n *node[float64, int])
+		visit = func(n *node[float64, int]) {
+			if n.leaf() {
+				maxLeaf = max(maxLeaf, int(n.count))
+				return
+			}
+			maxBranch = max(maxBranch, int(n.count))
+			for _, child := range n.children()[:n.count] {
+				visit(child)
+			}
+		}
+		visit(tr.root)
+		if maxLeaf > leafFanout || maxBranch > branchFanout ||
+			(full && (maxLeaf != leafFanout || maxBranch != branchFanout)) {
+			t.Fatalf("expected full nodes of %d/%d, got %d/%d", leafFanout,
+				branchFanout, maxLeaf, maxBranch)
+		}
+	}
+	check(&tr.base, false)
+	// bulk loading packs the nodes up to the fanout
+	tr2 := New(WithFanout[float64, int](leafFanout, branchFanout))
+	tr2.LoadBulk(tr.Items())
+	check(tr2, true)
+	if tr2.Stats().BranchFill <= 0.5 {
+		t.Fatalf("expected packed branches, got %v", tr2.Stats().BranchFill)
+	}
+	if New(WithFanout[float64, int](1, maxEntries+1)).fanout(true) != 4 ||
+		New(WithFanout[float64, int](1, maxEntries+1)).fanout(false) !=
+			maxEntries {
+		t.Fatal("expected the fanout to be clamped")
+	}
+}
+
+// appendNodes appends the structure of the node and its children.
+func appendNodes(dst []string, n *node[float64, int]) []string {
+	for i := 0; i < int(n.count); i++ {
+		dst = append(dst, fmt.Sprint(n.leaf(), n.rects.at(i)))
+		if n.leaf() {
+			dst = append(dst, fmt.Sprint(n.items()[i]))
+		} else {
+			dst = appendNodes(dst, n.children()[i])
+		}
+	}
+	return dst
+}
+
+func TestWithDeterministic(t *testing.T) {
+	// many items share the same center, whose order is otherwise not
+	// specified
+	var items []Item[float64, int]
+	for i := 0; i < 30000; i++ {
+		x, y := float64(rand.Intn(20)), float64(rand.Intn(20))
+		items = append(items, Item[float64, int]{[2]float64{x, y},
+			[2]float64{x, y}, i})
+	}
+	build := func(workers int) []string {
+		tr := New(WithDeterministic[float64, int](true))
+		tr.LoadBulkParallel(slices.Clone(items[:20000]), workers)
+		for i := 20000; i < len(items); i++ {
+			tr.Insert(items[i].Min, items[i].Max, items[i].Data)
+		}
+		for i := 0; i < len(items); i += 3 {
+			tr.Delete(items[i].Min, items[i].Max, items[i].Data)
+		}
+		tr.Compact(0.9)
+		return appendNodes(nil, tr.root)
+	}
+	expect := build(1)
+	for _, workers := range []int{2, 3, 8} {
+		if !slices.Equal(build(workers), expect) {
+			t.Fatalf("expected the same tree with %d workers", workers)
+		}
+	}
+}
+
+func TestWithComparator(t *testing.T) {
+	// slices can't be compared with ==, so they're compared by their first
+	// element
+	tr := New(WithComparator[float64, []int](func(a, b []int) bool {
+		return a[0] == b[0]
+	}))
+	for i := 0; i < 1000; i++ {
+		tr.Insert([2]float64{float64(i), 0}, [2]float64{float64(i), 0},
+			[]int{i, i * 2})
+	}
+	if !tr.Exists([2]float64{10, 0}, [2]float64{10, 0}, []int{10}) {
+		t.Fatal("expected the item to exist")
+	}
+	if tr.Exists([2]float64{10, 0}, [2]float64{10, 0}, []int{11}) {
+		t.Fatal("expected the item to not exist")
+	}
+	for i := 0; i < 1000; i += 2 {
+		tr.Delete([2]float64{float64(i), 0}, [2]float64{float64(i), 0},
+			[]int{i})
+	}
+	if tr.Len() != 500 {
+		t.Fatalf("expected 500 items, got %d", tr.Len())
+	}
+}
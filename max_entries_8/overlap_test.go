@@ -0,0 +1,69 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestChooseLeastOverlap(t *testing.T) {
+	// long and thin segments, like roads
+	rects := make([]rect[float64], 20000)
+	for i := range rects {
+		x, y := rand.Float64()*100, rand.Float64()*100
+		if i%2 == 0 {
+			rects[i] = rect[float64]{[2]float64{x, y}, [2]float64{x + 5, y}}
+		} else {
+			rects[i] = rect[float64]{[2]float64{x, y}, [2]float64{x, y + 5}}
+		}
+	}
+	var tr, expect RTreeG[int]
+	tr.SetChooseSubtree(LeastOverlap)
+	for i, r := range rects {
+		tr.Insert(r.min, r.max, i)
+		expect.Insert(r.min, r.max, i)
+	}
+	if err := rSane(&tr); err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; j < 50; j++ {
+		x, y := rand.Float64()*100, rand.Float64()*100
+		r := rect[float64]{[2]float64{x, y}, [2]float64{x + 2, y + 2}}
+		if !slices.Equal(bulkSearch(&tr, r), bulkSearch(&expect, r)) {
+			t.Fatal("mismatch")
+		}
+	}
+	// Least enlargement picks the first child, which then overlaps the
+	// second one. The second one needs more enlargement, but never overlaps.
+	var n node[float64, int]
+	n.rects.set(0, rect[float64]{[2]float64{0, 0}, [2]float64{10, 10}})
+	n.rects.set(1, rect[float64]{[2]float64{10.5, 0}, [2]float64{30, 4}})
+	n.rects.set(2, rect[float64]{[2]float64{11.5, 6}, [2]float64{12, 100}})
+	n.count = 3
+	ir := rect[float64]{[2]float64{11, 5}, [2]float64{11, 5}}
+	if i := n.chooseLeastEnlargement(&ir); i != 0 {
+		t.Fatalf("expected 0, got %d", i)
+	}
+	if i := n.chooseLeastOverlap(&ir); i != 1 {
+		t.Fatalf("expected 1, got %d", i)
+	}
+}
+
+func TestOverlapArea(t *testing.T) {
+	a := rect[float64]{[2]float64{0, 0}, [2]float64{10, 10}}
+	b := rect[float64]{[2]float64{5, 8}, [2]float64{20, 20}}
+	c := rect[float64]{[2]float64{10, 0}, [2]float64{20, 10}}
+	if v := a.overlapArea(&b); v != 10 {
+		t.Fatalf("expected 10, got %v", v)
+	}
+	if v := a.overlapArea(&c); v != 0 {
+		t.Fatalf("expected 0, got %v", v)
+	}
+	if v := b.overlapArea(&c); v != 20 {
+		t.Fatalf("expected 20, got %v", v)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSearchPage(t *testing.T) {
+	var tr RTreeG[int]
+	N := 2000
+	rects := make([]rect[float64], N)
+	for i := 0; i < N; i++ {
+		// points on a grid, so that many items have the same keys
+		p := [2]float64{float64(rand.Intn(50)), float64(rand.Intn(50))}
+		rects[i] = rect[float64]{p, p}
+		tr.Insert(p, p, i)
+	}
+	orders := []Order[float64, int]{
+		OrderByMinX[float64, int](),
+		OrderByDistance[float64, int]([2]float64{25, 25}),
+		OrderByCustom(func(a, b Item[float64, int]) bool {
+			return a.Min[1] < b.Min[1]
+		}),
+	}
+	for i := 0; i < 20; i++ {
+		q := rect[float64]{[2]float64{float64(rand.Intn(40)),
+			float64(rand.Intn(40))}, [2]float64{50, 50}}
+		for _, order := range orders {
+			var expect []int
+			tr.SearchOrdered(q.min, q.max, order,
+				func(min, max [2]float64, data int) bool {
+					expect = append(expect, data)
+					return true
+				})
+			limit := rand.Intn(100) + 1
+			var got []int
+			var token string
+			for {
+				items, next, err := tr.SearchPage(q.min, q.max, order, token,
+					limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(items) > limit || next != "" && len(items) != limit {
+					t.Fatalf("unexpected page of %d items", len(items))
+				}
+				for _, item := range items {
+					got = append(got, item.Data)
+				}
+				if next == "" {
+					break
+				}
+				token = next
+			}
+			if !slices.Equal(got, expect) {
+				t.Fatalf("expected %v, got %v", expect, got)
+			}
+		}
+	}
+
+	// all items at once
+	items, next, err := tr.SearchPage([2]float64{0, 0}, [2]float64{50, 50},
+		orders[0], "", 0)
+	if err != nil || next != "" || len(items) != N {
+		t.Fatalf("expected %d items, got %d %q %v", N, len(items), next, err)
+	}
+
+	// the next page starts after the same item, when items before it are
+	// deleted
+	var tr2 RTreeG[int]
+	for i := 0; i < 1000; i++ {
+		p := [2]float64{float64(i), rand.Float64() * 50}
+		tr2.Insert(p, p, i)
+	}
+	order := orders[0]
+	page1, token, _ := tr2.SearchPage([2]float64{0, 0}, [2]float64{1000, 50},
+		order, "", 100)
+	for _, item := range page1[:50] {
+		tr2.Delete(item.Min, item.Max, item.Data)
+	}
+	page2, _, _ := tr2.SearchPage([2]float64{0, 0}, [2]float64{1000, 50},
+		order, token, 100)
+	if len(page2) != 100 || page2[0].Data != 100 {
+		t.Fatalf("expected 100, got %v", page2[0].Data)
+	}
+
+	// invalid tokens
+	for _, token := range []string{"!", "AA", token + "A"} {
+		if _, _, err := tr.SearchPage([2]float64{0, 0},
+			[2]float64{50, 50}, order, token, 10); err == nil {
+			t.Fatalf("expected an error for %q", token)
+		}
+	}
+	if _, _, err := tr.SearchPage([2]float64{0, 0}, [2]float64{50, 50},
+		orders[1], token, 10); err == nil {
+		t.Fatal("expected an error for the token of another order")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSearchParallel(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 20000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	for _, workers := range []int{0, 1, 2, 8} {
+		for i := 0; i < 50; i++ {
+			q := randRect('r')
+			q.max[0] += 90
+			q.max[1] += 45
+			expect := bulkSearch(&tr, q)
+			var mu sync.Mutex
+			var res []int
+			tr.SearchParallel(q.min, q.max, workers,
+				func(min, max [2]float64, data int) bool {
+					mu.Lock()
+					res = append(res, data)
+					mu.Unlock()
+					return true
+				},
+			)
+			sort.Ints(res)
+			if len(res) != len(expect) {
+				t.Fatalf("expected %d, got %d", len(expect), len(res))
+			}
+			for j := range res {
+				if res[j] != expect[j] {
+					t.Fatalf("expected %d, got %d", expect[j], res[j])
+				}
+			}
+		}
+	}
+	// stop early
+	var count atomic.Int64
+	tr.SearchParallel([2]float64{-180, -90}, [2]float64{180, 90}, 8,
+		func(min, max [2]float64, data int) bool {
+			return count.Add(1) < 10
+		},
+	)
+	if n := count.Load(); n < 10 || n >= int64(tr.Len()) {
+		t.Fatalf("expected early stop, got %d calls", n)
+	}
+	// empty tree
+	var tr2 RTreeG[int]
+	tr2.SearchParallel([2]float64{-180, -90}, [2]float64{180, 90}, 8,
+		func(min, max [2]float64, data int) bool {
+			t.Fatal("unexpected item")
+			return true
+		},
+	)
+}
@@ -0,0 +1,158 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "math"
+
+// Projection converts between lat/lon points and planar coordinates, such
+// as meters in Web Mercator.
+// The X coordinate must increase with the longitude and the Y coordinate
+// with the latitude, so that the corners of a box can be converted on their
+// own.
+type Projection interface {
+	Project(p LatLon) (xy [2]float64)
+	Unproject(xy [2]float64) LatLon
+}
+
+// webMercatorRadius is the radius of the earth that is used by Web Mercator.
+const webMercatorRadius = 6378137
+
+// webMercatorMaxLat is the latitude at which Web Mercator is cut off, which
+// makes the projected world square.
+const webMercatorMaxLat = 85.0511287798066
+
+type webMercator struct{}
+
+// WebMercator returns the Web Mercator projection, EPSG:3857, which is used
+// by most web maps. The coordinates are in meters.
+// Latitudes beyond ±85.0511 are clamped.
+func WebMercator() Projection {
+	return webMercator{}
+}
+
+func (webMercator) Project(p LatLon) [2]float64 {
+	lat := math.Max(-webMercatorMaxLat, math.Min(webMercatorMaxLat, p.Lat))
+	return [2]float64{
+		webMercatorRadius * p.Lon * radians,
+		webMercatorRadius * math.Log(math.Tan(math.Pi/4+lat*radians/2)),
+	}
+}
+
+func (webMercator) Unproject(xy [2]float64) LatLon {
+	return LatLon{
+		Lat: (2*math.Atan(math.Exp(xy[1]/webMercatorRadius)) - math.Pi/2) *
+			degrees,
+		Lon: xy[0] / webMercatorRadius * degrees,
+	}
+}
+
+type equirectangular struct {
+	cos float64
+}
+
+// Equirectangular returns the equirectangular projection with the provided
+// standard parallel, in degrees, where the scale is true. The coordinates are
+// in meters.
+func Equirectangular(lat0 float64) Projection {
+	return equirectangular{cos: math.Cos(lat0 * radians)}
+}
+
+func (e equirectangular) Project(p LatLon) [2]float64 {
+	return [2]float64{
+		earthRadius * p.Lon * radians * e.cos,
+		earthRadius * p.Lat * radians,
+	}
+}
+
+func (e equirectangular) Unproject(xy [2]float64) LatLon {
+	return LatLon{
+		Lat: xy[1] / earthRadius * degrees,
+		Lon: xy[0] / (earthRadius * e.cos) * degrees,
+	}
+}
+
+// SetProjection sets the projection that is used by the functions that take
+// or return projected coordinates, such as InsertProjected. Items are always
+// stored as lat/lon, so radius searches in meters use the great-circle
+// distance no matter which projection the items were inserted with.
+// The default is WebMercator.
+func (tr *GeoTree[T]) SetProjection(proj Projection) {
+	tr.proj = proj
+}
+
+// Projection returns the projection of the tree.
+func (tr *GeoTree[T]) Projection() Projection {
+	if tr.proj == nil {
+		return WebMercator()
+	}
+	return tr.proj
+}
+
+// unproject returns the lat/lon box of the projected rect.
+func (tr *GeoTree[T]) unproject(min, max [2]float64) (sw, ne LatLon) {
+	proj := tr.Projection()
+	return snapLatLon(proj.Unproject(min)), snapLatLon(proj.Unproject(max))
+}
+
+// snapLatLon moves a point that is just outside of the lat/lon range onto
+// its edge, which happens when unprojecting the edge of the world due to
+// rounding.
+func snapLatLon(p LatLon) LatLon {
+	snap := func(v, lim float64) float64 {
+		if math.Abs(v) > lim && math.Abs(v) < lim+1e-9 {
+			return math.Copysign(lim, v)
+		}
+		return v
+	}
+	return LatLon{Lat: snap(p.Lat, 90), Lon: snap(p.Lon, 180)}
+}
+
+// project returns the projected rect of the lat/lon box.
+func (tr *GeoTree[T]) project(sw, ne LatLon) (min, max [2]float64) {
+	proj := tr.Projection()
+	return proj.Project(sw), proj.Project(ne)
+}
+
+// InsertProjected inserts data for a rect in projected coordinates into the
+// tree. A rect whose min X is greater than its max X spans the antimeridian.
+// Returns ErrInvalidLatLon if the rect is outside of the projected world.
+func (tr *GeoTree[T]) InsertProjected(min, max [2]float64, data T) error {
+	sw, ne := tr.unproject(min, max)
+	return tr.InsertBBox(sw, ne, data)
+}
+
+// DeleteProjected deletes data for a rect in projected coordinates from the
+// tree.
+func (tr *GeoTree[T]) DeleteProjected(min, max [2]float64, data T) {
+	sw, ne := tr.unproject(min, max)
+	tr.DeleteBBox(sw, ne, data)
+}
+
+// SearchProjected searches for items that intersect the rect in projected
+// coordinates. The rects that are sent to iter are projected too, which may
+// differ slightly from the inserted ones due to rounding.
+// Nothing is found when the rect is outside of the projected world.
+func (tr *GeoTree[T]) SearchProjected(min, max [2]float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	sw, ne := tr.unproject(min, max)
+	tr.SearchBBox(sw, ne, func(sw, ne LatLon, data T) bool {
+		min, max := tr.project(sw, ne)
+		return iter(min, max, data)
+	})
+}
+
+// SearchRadiusMetersProjected searches for items that are within the
+// provided distance, in meters, from the center in projected coordinates,
+// using the great-circle distance.
+func (tr *GeoTree[T]) SearchRadiusMetersProjected(center [2]float64,
+	meters float64, iter func(min, max [2]float64, data T) bool,
+) {
+	c := snapLatLon(tr.Projection().Unproject(center))
+	tr.SearchRadiusMeters(c, meters, func(sw, ne LatLon, data T) bool {
+		min, max := tr.project(sw, ne)
+		return iter(min, max, data)
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "math"
+
+type quadraticSplitter[N numeric, T any] struct{}
+
+// QuadraticSplitter returns a splitter that uses Guttman's quadratic split.
+// It starts both nodes with the pair of entries that would waste the most
+// area when put together, and then adds the remaining entries one at a time,
+// picking the entry with the strongest preference for one of the nodes.
+// It's slower than the default, but often produces far less overlap for
+// long and thin rectangles.
+func QuadraticSplitter[N numeric, T any]() Splitter[N, T] {
+	return quadraticSplitter[N, T]{}
+}
+
+func (quadraticSplitter[N, T]) Split(leaf bool, min, max [2]N,
+	entries []Item[N, T], right []bool,
+) {
+	rects := make([]rect[N], len(entries))
+	for i, e := range entries {
+		rects[i] = rect[N]{e.Min, e.Max}
+	}
+	// pick the seeds
+	s1, s2 := 0, 1
+	worst := math.Inf(-1)
+	for i := range rects {
+		for j := i + 1; j < len(rects); j++ {
+			u := rects[i]
+			u.expand(&rects[j])
+			d := u.area() - rects[i].area() - rects[j].area()
+			if d > worst {
+				s1, s2, worst = i, j, d
+			}
+		}
+	}
+	assigned := make([]bool, len(rects))
+	assigned[s1], assigned[s2] = true, true
+	right[s1], right[s2] = false, true
+	groups := [2]rect[N]{rects[s1], rects[s2]}
+	counts := [2]int{1, 1}
+	minFill := len(rects) * 2 / 5
+	if minFill < 2 {
+		minFill = 2
+	}
+	for remain := len(rects) - 2; remain > 0; remain-- {
+		// One of the groups needs all of the remaining entries to reach the
+		// minimum fill.
+		for g := 0; g < 2; g++ {
+			if counts[g]+remain <= minFill {
+				for i := range rects {
+					if !assigned[i] {
+						assigned[i] = true
+						right[i] = g == 1
+					}
+				}
+				return
+			}
+		}
+		// pick the next entry
+		next, nd1, nd2 := -1, 0.0, 0.0
+		for i := range rects {
+			if assigned[i] {
+				continue
+			}
+			d1 := groups[0].unionedArea(&rects[i]) - groups[0].area()
+			d2 := groups[1].unionedArea(&rects[i]) - groups[1].area()
+			if next == -1 || math.Abs(d1-d2) > math.Abs(nd1-nd2) {
+				next, nd1, nd2 = i, d1, d2
+			}
+		}
+		g := 0
+		switch {
+		case nd2 < nd1:
+			g = 1
+		case nd1 < nd2:
+		case groups[1].area() < groups[0].area():
+			g = 1
+		case groups[0].area() < groups[1].area():
+		case counts[1] < counts[0]:
+			g = 1
+		}
+		assigned[next] = true
+		right[next] = g == 1
+		groups[g].expand(&rects[next])
+		counts[g]++
+	}
+}
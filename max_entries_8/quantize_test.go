@@ -0,0 +1,90 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantizedRTree(t *testing.T) {
+	tr := NewQuantizedRTreeG[int](1e-7)
+	if tr.Precision() != 1e-7 {
+		t.Fatalf("expected %v, got %v", 1e-7, tr.Precision())
+	}
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	if tr.Len() != len(rects) {
+		t.Fatalf("expected %d, got %d", len(rects), tr.Len())
+	}
+	// Searching for the exact rect of an item always finds it, and every
+	// found item is at most the precision away from the searched rect.
+	for i, r := range rects {
+		var found bool
+		tr.Search(r.min, r.max, func(min, max [2]float64, data int) bool {
+			q := rects[data]
+			for axis := 0; axis < 2; axis++ {
+				if q.min[axis] > r.max[axis]+1e-7 ||
+					q.max[axis] < r.min[axis]-1e-7 {
+					t.Fatalf("item %d is too far away", data)
+				}
+				if min[axis] > q.min[axis] || max[axis] < q.max[axis] {
+					t.Fatalf("rect of item %d is not rounded outwards",
+						data)
+				}
+			}
+			found = found || data == i
+			return true
+		})
+		if !found {
+			t.Fatalf("item %d not found", i)
+		}
+	}
+	bounds := rects[0]
+	for i := range rects {
+		bounds.expand(&rects[i])
+	}
+	min, max := tr.Bounds()
+	for axis := 0; axis < 2; axis++ {
+		if min[axis] > bounds.min[axis] || max[axis] < bounds.max[axis] ||
+			min[axis] < bounds.min[axis]-1e-7 ||
+			max[axis] > bounds.max[axis]+1e-7 {
+			t.Fatalf("unexpected bounds %v %v", min, max)
+		}
+	}
+	for i := 0; i < len(rects); i += 2 {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+	var count int
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		if data%2 == 0 {
+			t.Fatalf("item %d was not deleted", data)
+		}
+		count++
+		return true
+	})
+	if count != len(rects)/2 || tr.Len() != count {
+		t.Fatalf("expected %d, got %d", len(rects)/2, count)
+	}
+	// out of range
+	expectPanic(t, func() {
+		tr.Insert([2]float64{0, 0}, [2]float64{300, 0}, 0)
+	})
+	tr.Delete([2]float64{0, 0}, [2]float64{300, 0}, 1)
+	count = 0
+	tr.Search([2]float64{-math.MaxFloat64, -math.MaxFloat64},
+		[2]float64{math.MaxFloat64, math.MaxFloat64},
+		func(min, max [2]float64, data int) bool {
+			count++
+			return true
+		})
+	if count != tr.Len() {
+		t.Fatalf("expected %d, got %d", tr.Len(), count)
+	}
+	expectPanic(t, func() { NewQuantizedRTreeG[int](0) })
+}
@@ -5,6 +5,8 @@
 package rtree
 
 import (
+	"iter"
+	"math"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -32,8 +34,6 @@ import (
 // node kind is a `leaf` or `branch`.
 
 const maxEntries = 8
-const orderBranches = true
-const orderLeaves = true
 
 // copy-on-write atomic incrementer
 var gcow uint64
@@ -47,10 +47,41 @@ type numeric interface {
 type RTreeGN[N numeric, T any] struct {
 	icow  uint64
 	count int
+	seq   uint64
+	gen   uint64 // incremented on every write
 	rect  rect[N]
 	root  *node[N, T]
 	empty T
 	qpool *sync.Pool
+
+	frozen  bool
+	strict  bool
+	ordered bool
+	eps     float64
+	writes  writeGuard
+	hooks   *Hooks
+	alloc   nodeAllocator[N, T]
+	aggs    []aggregator[N, T]
+	weight  *aggIndex[N, T, float64]
+	score   *aggIndex[N, T, float64]
+	hash    *hashAgg[N, T]
+	cluster *clusterAgg[N, T]
+	mask    *aggIndex[N, T, [2]uint64]
+	codec   ItemCodec[T]
+	split   Splitter[N, T]
+	choose  ChooseSubtree
+
+	unordered     bool
+	minFill       int
+	leafFanout    int
+	branchFanout  int
+	deterministic bool
+	eq            func(a, b T) bool
+	maxItems      int
+	maxMemory     int64
+	mem           memEstimate
+	logger        func(op Op, min, max [2]N, data T)
+	subs          *subscriptions[N, T]
 }
 
 type rect[N numeric] struct {
@@ -82,19 +113,94 @@ const (
 )
 
 type node[N numeric, T any] struct {
-	icow  uint64
-	kind  kind
-	count int16
-	rects [maxEntries]rect[N]
+	icow      uint64
+	kind      kind
+	unordered bool // rects are not ordered by their min x
+	count     int16
+	aggs      *nodeAggs // nil until the node is fixed by a tree aggregator
+	rects     rectArray[N]
+}
+
+// nodeAggs are the aggregate values of a node, which are kept out of the
+// node itself so that trees without aggregators don't pay for them.
+type nodeAggs struct {
+	dirty bool  // values need to be updated
+	vals  []any // one for each tree aggregator
+}
+
+// rectArray stores the rectangles of a node as a struct of arrays, where each
+// coordinate has its own array. Scans that look at a single coordinate, such
+// as the ordered early break in search, read contiguous memory.
+type rectArray[N numeric] struct {
+	min [2][maxEntries]N
+	max [2][maxEntries]N
+}
+
+// at returns the rectangle at index i.
+func (a *rectArray[N]) at(i int) rect[N] {
+	return rect[N]{
+		[2]N{a.min[0][i], a.min[1][i]},
+		[2]N{a.max[0][i], a.max[1][i]},
+	}
+}
+
+// set stores the rectangle at index i.
+func (a *rectArray[N]) set(i int, r rect[N]) {
+	a.min[0][i], a.min[1][i] = r.min[0], r.min[1]
+	a.max[0][i], a.max[1][i] = r.max[0], r.max[1]
+}
+
+// expand grows the rectangle at index i to include r.
+func (a *rectArray[N]) expand(i int, r *rect[N]) {
+	a.min[0][i] = fmin(a.min[0][i], r.min[0])
+	a.min[1][i] = fmin(a.min[1][i], r.min[1])
+	a.max[0][i] = fmax(a.max[0][i], r.max[0])
+	a.max[1][i] = fmax(a.max[1][i], r.max[1])
+}
+
+// contains returns true if the rectangle at index i contains r.
+func (a *rectArray[N]) contains(i int, r *rect[N]) bool {
+	return !(r.min[0] < a.min[0][i] || r.max[0] > a.max[0][i] ||
+		r.min[1] < a.min[1][i] || r.max[1] > a.max[1][i])
+}
+
+// inside returns true if the rectangle at index i is inside of r.
+func (a *rectArray[N]) inside(i int, r *rect[N]) bool {
+	return !(a.min[0][i] < r.min[0] || a.max[0][i] > r.max[0] ||
+		a.min[1][i] < r.min[1] || a.max[1][i] > r.max[1])
+}
+
+// swap exchanges the rectangles at index i and j.
+func (a *rectArray[N]) swap(i, j int) {
+	for axis := 0; axis < 2; axis++ {
+		a.min[axis][i], a.min[axis][j] = a.min[axis][j], a.min[axis][i]
+		a.max[axis][i], a.max[axis][j] = a.max[axis][j], a.max[axis][i]
+	}
+}
+
+// move copies n rectangles starting at index src to index dst. The ranges
+// may overlap.
+func (a *rectArray[N]) move(dst, src, n int) {
+	for axis := 0; axis < 2; axis++ {
+		copy(a.min[axis][dst:dst+n], a.min[axis][src:src+n])
+		copy(a.max[axis][dst:dst+n], a.max[axis][src:src+n])
+	}
 }
 
 func (n *node[N, T]) leaf() bool {
 	return n.kind == leaf
 }
 
+// ordered returns true if the rects of the node are ordered by their min x,
+// which allows for scans to stop early.
+func (n *node[N, T]) ordered() bool {
+	return !n.unordered
+}
+
 type leafNode[N numeric, T any] struct {
 	node[N, T]
 	items [maxEntries]T
+	seqs  *[maxEntries]uint64 // optional item sequence numbers
 }
 
 type branchNode[N numeric, T any] struct {
@@ -118,69 +224,151 @@ func (n *node[N, T]) items() []T {
 	return (*leafNode[N, T])(unsafe.Pointer(n)).items[:]
 }
 
+// seqs returns the item sequence numbers, or nil if the node is a branch or
+// if none of its items have a sequence number.
+// Unused sequence numbers, including those at or after the node count, are
+// always zero.
+func (n *node[N, T]) seqs() []uint64 {
+	if n.kind != leaf {
+		// not a leaf
+		return nil
+	}
+	seqs := (*leafNode[N, T])(unsafe.Pointer(n)).seqs
+	if seqs == nil {
+		return nil
+	}
+	return seqs[:]
+}
+
+// allocSeqs returns the item sequence numbers for a leaf, allocating them if
+// needed.
+func (n *node[N, T]) allocSeqs() []uint64 {
+	ln := (*leafNode[N, T])(unsafe.Pointer(n))
+	if ln.seqs == nil {
+		ln.seqs = new([maxEntries]uint64)
+	}
+	return ln.seqs[:]
+}
+
 func (tr *RTreeGN[N, T]) newNode(isleaf bool) *node[N, T] {
+	if tr.hooks != nil && tr.hooks.OnNodeAlloc != nil {
+		tr.hooks.OnNodeAlloc(isleaf)
+	}
+	if tr.alloc != nil {
+		n := tr.alloc.alloc(isleaf)
+		n.icow = tr.icow
+		n.unordered = tr.unordered
+		return n
+	}
 	if isleaf {
-		n := &leafNode[N, T]{node: node[N, T]{icow: tr.icow, kind: leaf}}
+		n := &leafNode[N, T]{node: node[N, T]{icow: tr.icow, kind: leaf,
+			unordered: tr.unordered}}
 		return (*node[N, T])(unsafe.Pointer(n))
 	} else {
-		n := &branchNode[N, T]{node: node[N, T]{icow: tr.icow, kind: branch}}
+		n := &branchNode[N, T]{node: node[N, T]{icow: tr.icow, kind: branch,
+			unordered: tr.unordered}}
 		return (*node[N, T])(unsafe.Pointer(n))
 	}
 }
 
 func (n *node[N, T]) rect() rect[N] {
-	rect := n.rects[0]
-	for i := 1; i < int(n.count); i++ {
-		rect.expand(&n.rects[i])
+	rect := n.rects.at(0)
+	for axis := 0; axis < 2 && n.count > 1; axis++ {
+		for _, v := range n.rects.min[axis][1:n.count] {
+			rect.min[axis] = fmin(rect.min[axis], v)
+		}
+		for _, v := range n.rects.max[axis][1:n.count] {
+			rect.max[axis] = fmax(rect.max[axis], v)
+		}
 	}
 	return rect
 }
 
+// initPool prepares the queue pool that is used by Nearby.
+func (tr *RTreeGN[N, T]) initPool() {
+	if tr.qpool == nil {
+		tr.qpool = &sync.Pool{
+			New: func() any { return &queue[N, T]{} },
+		}
+	}
+}
+
 // Insert data into tree
 func (tr *RTreeGN[N, T]) Insert(min, max [2]N, data T) {
+	tr.insert(min, max, data, 0)
+}
+
+// insert data into tree with an optional sequence number, where zero means
+// that the item has no sequence number.
+func (tr *RTreeGN[N, T]) insert(min, max [2]N, data T, seq uint64) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.strict && !validRect(min, max) {
+		panic(ErrInvalidRect)
+	}
+	if writeGuardEnabled {
+		tr.writes.enter()
+		defer tr.writes.exit()
+	}
+	if seq == 0 && tr.ordered {
+		tr.seq++
+		seq = tr.seq
+	}
+	tr.insertItem(min, max, data, seq)
+	if tr.logging() {
+		tr.log(OpInsert, min, max, data)
+	}
+}
+
+func (tr *RTreeGN[N, T]) insertItem(min, max [2]N, data T, seq uint64) {
+	tr.gen++
 	ir := rect[N]{min, max}
 	if tr.root == nil {
-		if tr.qpool == nil {
-			tr.qpool = &sync.Pool{
-				New: func() any { return &queue[N, T]{} },
-			}
-		}
+		tr.initPool()
 		tr.root = tr.newNode(true)
 		tr.rect = ir
 	}
 	tr.cow(&tr.root)
-	split, grown := tr.nodeInsert(&tr.rect, tr.root, &ir, data)
+	split, grown := tr.nodeInsert(&tr.rect, tr.root, &ir, data, seq)
 	if split {
 		left := tr.root
 		right := tr.splitNode(tr.rect, left)
 		tr.root = tr.newNode(false)
-		tr.root.rects[0] = left.rect()
-		tr.root.rects[1] = right.rect()
+		tr.root.rects.set(0, left.rect())
+		tr.root.rects.set(1, right.rect())
 		tr.root.children()[0] = left
 		tr.root.children()[1] = right
 		tr.root.count = 2
-		tr.Insert(min, max, data)
-		if orderBranches {
+		tr.insertItem(min, max, data, seq)
+		if tr.root.ordered() {
 			tr.root.sort()
 		}
 		return
 	}
 	if grown {
 		tr.rect.expand(&ir)
-		if orderBranches && !tr.root.leaf() {
+		if tr.root.ordered() && !tr.root.leaf() {
 			tr.root.sort()
 		}
 	}
 	tr.count++
+	tr.fixAggs()
 }
 
 func (tr *RTreeGN[N, T]) splitNode(r rect[N], left *node[N, T],
 ) (right *node[N, T]) {
+	if tr.hooks != nil && tr.hooks.OnSplit != nil {
+		tr.hooks.OnSplit(left.leaf())
+	}
+	if tr.split != nil {
+		return tr.splitNodeWith(r, left)
+	}
 	return tr.splitNodeLargestAxisEdgeSnap(r, left)
 }
 
 func (n *node[N, T]) orderToRight(idx int) int {
-	for idx < int(n.count)-1 && n.rects[idx+1].min[0] < n.rects[idx].min[0] {
+	for idx < int(n.count)-1 && n.rects.min[0][idx+1] < n.rects.min[0][idx] {
 		n.swap(idx+1, idx)
 		idx++
 	}
@@ -188,7 +376,7 @@ func (n *node[N, T]) orderToRight(idx int) int {
 }
 
 func (n *node[N, T]) orderToLeft(idx int) int {
-	for idx > 0 && n.rects[idx].min[0] < n.rects[idx-1].min[0] {
+	for idx > 0 && n.rects.min[0][idx] < n.rects.min[0][idx-1] {
 		n.swap(idx, idx-1)
 		idx--
 	}
@@ -200,10 +388,18 @@ func (n *node[N, T]) orderToLeft(idx int) int {
 // allows for the parent cowLoad to be inlined.
 // go:noinline
 func (tr *RTreeGN[N, T]) copy(n *node[N, T]) *node[N, T] {
+	if tr.hooks != nil && tr.hooks.OnCopyOnWrite != nil {
+		tr.hooks.OnCopyOnWrite(n.leaf())
+	}
 	n2 := tr.newNode(n.leaf())
 	*n2 = *n
+	n2.icow = tr.icow
+	n2.aggs = nil
 	if n2.leaf() {
 		copy(n2.items()[:n.count], n.items()[:n.count])
+		if seqs := n.seqs(); seqs != nil {
+			copy(n2.allocSeqs()[:n.count], seqs[:n.count])
+		}
 	} else {
 		copy(n2.children()[:n.count], n.children()[:n.count])
 	}
@@ -212,51 +408,107 @@ func (tr *RTreeGN[N, T]) copy(n *node[N, T]) *node[N, T] {
 
 // cow ensures the provided node is not being shared with other R-trees.
 // Performs a copy-on-write, if needed.
+// The node is expected to be modified, so its aggregates are marked as dirty.
+// A copied node has no aggregates, which is dirty too.
 func (tr *RTreeGN[N, T]) cow(n **node[N, T]) {
 	if (*n).icow != tr.icow {
 		*n = tr.copy(*n)
+	} else if (*n).aggs != nil {
+		(*n).aggs.dirty = true
 	}
 }
 
 func (n *node[N, T]) rsearch(key N) int {
-	rects := n.rects[:n.count]
-	for i := 0; i < len(rects); i++ {
-		if !(n.rects[i].min[0] < key) {
+	mins := n.rects.min[0][:n.count]
+	for i := 0; i < len(mins); i++ {
+		if !(mins[i] < key) {
 			return i
 		}
 	}
 	return int(n.count)
 }
 
+// nodeInsert inserts the item into n, whose rectangle is nr.
+// The path of chosen children is kept on an explicit stack rather than
+// recursing. When the leaf at the bottom of the path is full, the nearest
+// ancestor that has room splits its full child, and the insert starts over
+// from that ancestor.
+// Returns split when n is full and must be split by the caller, and grown
+// when nr must be expanded to include the item.
 func (tr *RTreeGN[N, T]) nodeInsert(nr *rect[N], n *node[N, T], ir *rect[N],
-	data T,
+	data T, seq uint64,
 ) (split, grown bool) {
-	if n.leaf() {
-		if n.count == maxEntries {
+	var buf [16]searchFrame[N, T]
+	path := buf[:0]
+	leaf := n
+	for {
+		for !leaf.leaf() {
+			index := tr.chooseSubtree(leaf, ir)
+			children := leaf.children()
+			tr.cow(&children[index])
+			path = append(path, searchFrame[N, T]{n: leaf, i: index})
+			leaf = children[index]
+		}
+		if int(leaf.count) < tr.fanout(true) {
+			break
+		}
+		for len(path) > 0 &&
+			int(path[len(path)-1].n.count) >= tr.fanout(false) {
+			path = path[:len(path)-1]
+		}
+		if len(path) == 0 {
 			return true, false
 		}
-		items := n.items()
-		index := int(n.count)
-		if orderLeaves {
-			index = n.rsearch(ir.min[0])
-			copy(n.rects[index+1:int(n.count)+1], n.rects[index:int(n.count)])
-			copy(items[index+1:int(n.count)+1], items[index:int(n.count)])
+		f := path[len(path)-1]
+		path = path[:len(path)-1]
+		tr.splitChild(f.n, f.i)
+		leaf = f.n
+	}
+
+	items := leaf.items()
+	index := int(leaf.count)
+	if leaf.ordered() {
+		index = leaf.rsearch(ir.min[0])
+		leaf.rects.move(index+1, index, int(leaf.count)-index)
+		copy(items[index+1:int(leaf.count)+1], items[index:int(leaf.count)])
+		if seqs := leaf.seqs(); seqs != nil {
+			copy(seqs[index+1:int(leaf.count)+1], seqs[index:int(leaf.count)])
 		}
-		n.rects[index] = *ir
-		items[index] = data
-		n.count++
-		grown = !nr.contains(ir)
-		return false, grown
 	}
+	leaf.rects.set(index, *ir)
+	items[index] = data
+	if seqs := leaf.seqs(); seqs != nil {
+		seqs[index] = seq
+	} else if seq != 0 {
+		leaf.allocSeqs()[index] = seq
+	}
+	leaf.count++
+
+	// The child rectangles along the path must expand to accomadate the new
+	// item, stopping at the first one that already contains it.
+	for i := len(path) - 1; i >= 0; i-- {
+		f := path[i]
+		if f.n.rects.contains(f.i, ir) {
+			return false, false
+		}
+		f.n.rects.expand(f.i, ir)
+		if f.n.ordered() {
+			f.n.orderToLeft(f.i)
+		}
+	}
+	return false, !nr.contains(ir)
+}
 
-	// choose a subtree
-	rects := n.rects[:n.count]
+// chooseSubtree returns the index of the child of the branch n that the
+// rect ir should be inserted into.
+func (tr *RTreeGN[N, T]) chooseSubtree(n *node[N, T], ir *rect[N]) int {
 	index := -1
-	var narea N
+	var narea float64
 	// take a quick look for any nodes that contain the rect
-	for i := 0; i < len(rects); i++ {
-		if rects[i].contains(ir) {
-			area := rects[i].area()
+	for i := 0; i < int(n.count); i++ {
+		if n.rects.contains(i, ir) {
+			r := n.rects.at(i)
+			area := r.area()
 			if index == -1 || area < narea {
 				index = i
 				narea = area
@@ -264,53 +516,52 @@ func (tr *RTreeGN[N, T]) nodeInsert(nr *rect[N], n *node[N, T], ir *rect[N],
 		}
 	}
 	if index == -1 {
-		index = n.chooseLeastEnlargement(ir)
-	}
-
-	children := n.children()
-	tr.cow(&children[index])
-	split, grown = tr.nodeInsert(&n.rects[index], children[index], ir, data)
-	if split {
-		if n.count == maxEntries {
-			return true, false
-		}
-		// split the child node
-		left := children[index]
-		right := tr.splitNode(n.rects[index], left)
-		n.rects[index] = left.rect()
-		if orderBranches {
-			copy(n.rects[index+2:int(n.count)+1],
-				n.rects[index+1:int(n.count)])
-			copy(children[index+2:int(n.count)+1],
-				children[index+1:int(n.count)])
-			n.rects[index+1] = right.rect()
-			children[index+1] = right
-			n.count++
-			if n.rects[index].min[0] > n.rects[index+1].min[0] {
-				n.swap(index+1, index)
-			}
-			index++
-			_ = n.orderToRight(index)
+		if tr.choose == LeastOverlap && n.children()[0].leaf() {
+			index = n.chooseLeastOverlap(ir)
 		} else {
-			n.rects[n.count] = right.rect()
-			children[n.count] = right
-			n.count++
+			index = n.chooseLeastEnlargement(ir)
 		}
-		return tr.nodeInsert(nr, n, ir, data)
 	}
-	if grown {
-		// The child rectangle must expand to accomadate the new item.
-		n.rects[index].expand(ir)
-		if orderBranches {
-			n.orderToLeft(index)
+	return index
+}
+
+// splitChild splits the child at index of the branch n, which must not be
+// full, and adds the new right node to n.
+func (tr *RTreeGN[N, T]) splitChild(n *node[N, T], index int) {
+	children := n.children()
+	left := children[index]
+	right := tr.splitNode(n.rects.at(index), left)
+	n.rects.set(index, left.rect())
+	if n.ordered() {
+		n.rects.move(index+2, index+1, int(n.count)-index-1)
+		copy(children[index+2:int(n.count)+1],
+			children[index+1:int(n.count)])
+		n.rects.set(index+1, right.rect())
+		children[index+1] = right
+		n.count++
+		if n.rects.min[0][index] > n.rects.min[0][index+1] {
+			n.swap(index+1, index)
 		}
-		grown = !nr.contains(ir)
+		index++
+		_ = n.orderToRight(index)
+	} else {
+		n.rects.set(int(n.count), right.rect())
+		children[n.count] = right
+		n.count++
 	}
-	return false, grown
 }
 
-func (r *rect[N]) area() N {
-	return (r.max[0] - r.min[0]) * (r.max[1] - r.min[1])
+// span returns the length of the range along the axis.
+// This is calculated as a float64 because the difference of two large
+// integers, such as -2e9 and 2e9 for an int32, may overflow.
+func (r *rect[N]) span(axis int) float64 {
+	return float64(r.max[axis]) - float64(r.min[axis])
+}
+
+// area returns the area of the rect as a float64, which does not overflow
+// for large integer coordinates.
+func (r *rect[N]) area() float64 {
+	return r.span(0) * r.span(1)
 }
 
 // contains return struct when b is fully contained inside of n
@@ -335,19 +586,37 @@ func (r *rect[N]) intersects(b *rect[N]) bool {
 	return true
 }
 
+// chooseLeastEnlargement returns the index of the child rect that needs the
+// least enlargement to include ir, with ties going to the smallest area.
+// The areas of four rects are calculated per iteration, straight from the
+// coordinate arrays, before any of them are compared, which lets the
+// calculations overlap.
 func (n *node[N, T]) chooseLeastEnlargement(ir *rect[N]) (index int) {
-	rects := n.rects[:int(n.count)]
-	var j = -1
-	var jenlargement N
-	var jarea N
-	for i := 0; i < len(rects); i++ {
-		// calculate the enlarged area
-		uarea := rects[i].unionedArea(ir)
-		area := rects[i].area()
-		enlargement := uarea - area
-		if j == -1 || enlargement < jenlargement ||
-			(!(enlargement > jenlargement) && area < jarea) {
-			j, jenlargement, jarea = i, enlargement, area
+	count := int(n.count)
+	minx, miny := n.rects.min[0][:count], n.rects.min[1][:count]
+	maxx, maxy := n.rects.max[0][:count], n.rects.max[1][:count]
+	j := 0
+	jenlargement, jarea := math.Inf(1), math.Inf(1)
+	var enlargements, areas [4]float64
+	for i := 0; i < count; i += 4 {
+		m := 4
+		if count-i < m {
+			m = count - i
+		}
+		for k := 0; k < m; k++ {
+			areas[k] = (float64(maxx[i+k]) - float64(minx[i+k])) *
+				(float64(maxy[i+k]) - float64(miny[i+k]))
+			enlargements[k] = (float64(fmax(maxx[i+k], ir.max[0]))-
+				float64(fmin(minx[i+k], ir.min[0])))*
+				(float64(fmax(maxy[i+k], ir.max[1]))-
+					float64(fmin(miny[i+k], ir.min[1]))) - areas[k]
+		}
+		for k := 0; k < m; k++ {
+			enlargement, area := enlargements[k], areas[k]
+			if enlargement < jenlargement ||
+				(!(enlargement > jenlargement) && area < jarea) {
+				j, jenlargement, jarea = i+k, enlargement, area
+			}
 		}
 	}
 	return j
@@ -367,13 +636,16 @@ func fmax[N numeric](a, b N) N {
 }
 
 // unionedArea returns the area of two rects expanded
-func (r *rect[N]) unionedArea(b *rect[N]) N {
-	return (fmax(r.max[0], b.max[0]) - fmin(r.min[0], b.min[0])) *
-		(fmax(r.max[1], b.max[1]) - fmin(r.min[1], b.min[1]))
+func (r *rect[N]) unionedArea(b *rect[N]) float64 {
+	u := rect[N]{
+		[2]N{fmin(r.min[0], b.min[0]), fmin(r.min[1], b.min[1])},
+		[2]N{fmax(r.max[0], b.max[0]), fmax(r.max[1], b.max[1])},
+	}
+	return u.area()
 }
 
 func (r rect[N]) largestAxis() (axis int) {
-	if r.max[1]-r.min[1] > r.max[0]-r.min[0] {
+	if r.span(1) > r.span(0) {
 		return 1
 	}
 	return 0
@@ -384,8 +656,8 @@ func (tr *RTreeGN[N, T]) splitNodeLargestAxisEdgeSnap(r rect[N], left *node[N, T
 	axis := r.largestAxis()
 	right = tr.newNode(left.leaf())
 	for i := 0; i < int(left.count); i++ {
-		minDist := left.rects[i].min[axis] - r.min[axis]
-		maxDist := r.max[axis] - left.rects[i].max[axis]
+		minDist := float64(left.rects.min[axis][i]) - float64(r.min[axis])
+		maxDist := float64(r.max[axis]) - float64(left.rects.max[axis][i])
 		if minDist < maxDist {
 			// stay left
 		} else {
@@ -410,7 +682,7 @@ func (tr *RTreeGN[N, T]) splitNodeLargestAxisEdgeSnap(r rect[N], left *node[N, T
 		}
 	}
 
-	if (orderBranches && !right.leaf()) || (orderLeaves && right.leaf()) {
+	if right.ordered() {
 		// It's not uncommon that the nodes to be already ordered.
 		if !right.issorted() {
 			right.sort()
@@ -425,12 +697,17 @@ func (tr *RTreeGN[N, T]) splitNodeLargestAxisEdgeSnap(r rect[N], left *node[N, T
 func (tr *RTreeGN[N, T]) moveRectAtIndexInto(from *node[N, T], index int,
 	into *node[N, T],
 ) {
-	into.rects[into.count] = from.rects[index]
-	from.rects[index] = from.rects[from.count-1]
+	into.rects.set(int(into.count), from.rects.at(index))
+	from.rects.set(index, from.rects.at(int(from.count)-1))
 	if from.leaf() {
 		into.items()[into.count] = from.items()[index]
 		from.items()[index] = from.items()[from.count-1]
 		from.items()[from.count-1] = tr.empty
+		if seqs := from.seqs(); seqs != nil {
+			into.allocSeqs()[into.count] = seqs[index]
+			seqs[index] = seqs[from.count-1]
+			seqs[from.count-1] = 0
+		}
 	} else {
 		into.children()[into.count] = from.children()[index]
 		from.children()[index] = from.children()[from.count-1]
@@ -440,30 +717,76 @@ func (tr *RTreeGN[N, T]) moveRectAtIndexInto(from *node[N, T], index int,
 	into.count++
 }
 
+// searchFrame is a node that is being visited by an iterative traversal,
+// along with the index of its next child or item.
+type searchFrame[N numeric, T any] struct {
+	n *node[N, T]
+	i int
+}
+
 func (n *node[N, T]) search(target rect[N],
 	iter func(min, max [2]N, data T) bool,
 ) bool {
-	rects := n.rects[:n.count]
-	if n.leaf() {
-		items := n.items()
-		for i := 0; i < len(rects); i++ {
-			if rects[i].intersects(&target) {
-				if !iter(rects[i].min, rects[i].max, items[i]) {
-					return false
+	var buf [16]searchFrame[N, T]
+	_, ok := searchStack(append(buf[:0], searchFrame[N, T]{n: n}), target,
+		iter)
+	return ok
+}
+
+// searchStack searches the nodes on the stack for items that intersect the
+// target. When iter returns false the search stops with the stack left in
+// place, and calling searchStack again with the stack resumes the search from
+// the next item.
+// Returns the stack, which may have grown, and false if the search stopped
+// early.
+func searchStack[N numeric, T any](stack []searchFrame[N, T], target rect[N],
+	iter func(min, max [2]N, data T) bool,
+) ([]searchFrame[N, T], bool) {
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		n := f.n
+		count := int(n.count)
+		minx, miny := n.rects.min[0][:count], n.rects.min[1][:count]
+		maxx, maxy := n.rects.max[0][:count], n.rects.max[1][:count]
+		ordered := n.ordered()
+		if n.leaf() {
+			items := n.items()
+			for i := f.i; i < count; i++ {
+				if ordered && minx[i] > target.max[0] {
+					// the remaining rects are all further to the right
+					break
+				}
+				if !(minx[i] > target.max[0] || maxx[i] < target.min[0] ||
+					miny[i] > target.max[1] || maxy[i] < target.min[1]) {
+					if !iter([2]N{minx[i], miny[i]}, [2]N{maxx[i], maxy[i]},
+						items[i]) {
+						f.i = i + 1
+						return stack, false
+					}
 				}
 			}
+			stack = stack[:len(stack)-1]
+			continue
 		}
-		return true
-	}
-	children := n.children()
-	for i := 0; i < len(rects); i++ {
-		if target.intersects(&rects[i]) {
-			if !children[i].search(target, iter) {
-				return false
+		i := f.i
+		for ; i < count; i++ {
+			if ordered && minx[i] > target.max[0] {
+				i = count
+				break
+			}
+			if !(minx[i] > target.max[0] || maxx[i] < target.min[0] ||
+				miny[i] > target.max[1] || maxy[i] < target.min[1]) {
+				break
 			}
 		}
+		if i == count {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		f.i = i + 1
+		stack = append(stack, searchFrame[N, T]{n: n.children()[i]})
 	}
-	return true
+	return stack, true
 }
 
 // Len returns the number of items in tree
@@ -480,30 +803,57 @@ func (tr *RTreeGN[N, T]) Search(min, max [2]N,
 		return
 	}
 	if target.intersects(&tr.rect) {
-		tr.root.search(target, iter)
+		tr.root.search(target, tr.guard(iter))
 	}
 }
 
+// SearchShape searches for items in tree that intersect the provided
+// rectangle, which is usually the bounding box of some other shape, such as a
+// polygon or circle.
+// The refine function is called for each item whose rectangle intersects the
+// bounding box and should return true when the item actually intersects the
+// shape. Only items that pass the refine function are sent to iter.
+func (tr *RTreeGN[N, T]) SearchShape(min, max [2]N,
+	refine func(min, max [2]N, data T) bool,
+	iter func(min, max [2]N, data T) bool,
+) {
+	tr.Search(min, max, func(min, max [2]N, data T) bool {
+		if !refine(min, max, data) {
+			return true
+		}
+		return iter(min, max, data)
+	})
+}
+
 // Scane all items in the tree
 func (tr *RTreeGN[N, T]) Scan(iter func(min, max [2]N, data T) bool) {
 	if tr.root != nil {
-		tr.root.scan(iter)
+		tr.root.scan(tr.guard(iter))
 	}
 }
 
 func (n *node[N, T]) scan(iter func(min, max [2]N, data T) bool) bool {
-	if n.leaf() {
-		for i := 0; i < int(n.count); i++ {
-			if !iter(n.rects[i].min, n.rects[i].max, n.items()[i]) {
-				return false
+	var buf [16]searchFrame[N, T]
+	stack := append(buf[:0], searchFrame[N, T]{n: n})
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.n.leaf() {
+			items := f.n.items()
+			for i := 0; i < int(f.n.count); i++ {
+				r := f.n.rects.at(i)
+				if !iter(r.min, r.max, items[i]) {
+					return false
+				}
 			}
+			stack = stack[:len(stack)-1]
+			continue
 		}
-	} else {
-		for i := 0; i < int(n.count); i++ {
-			if !n.children()[i].scan(iter) {
-				return false
-			}
+		if f.i == int(f.n.count) {
+			stack = stack[:len(stack)-1]
+			continue
 		}
+		f.i++
+		stack = append(stack, searchFrame[N, T]{n: f.n.children()[f.i-1]})
 	}
 	return true
 }
@@ -512,18 +862,30 @@ func (n *node[N, T]) scan(iter func(min, max [2]N, data T) bool) bool {
 // This is a copy-on-write operation and is very fast because it only performs
 // a shadowed copy.
 func (tr *RTreeGN[N, T]) Copy() *RTreeGN[N, T] {
+	if writeGuardEnabled && !tr.frozen {
+		tr.writes.enter()
+		defer tr.writes.exit()
+	}
 	tr2 := new(RTreeGN[N, T])
 	*tr2 = *tr
-	tr.icow = atomic.AddUint64(&gcow, 1)
+	tr2.writes = writeGuard{}
+	tr2.frozen = false
+	if !tr.frozen {
+		// A frozen tree never writes to its nodes, so it can keep them.
+		tr.icow = atomic.AddUint64(&gcow, 1)
+	}
 	tr2.icow = atomic.AddUint64(&gcow, 1)
 	return tr2
 }
 
 // swap two rectanlges
 func (n *node[N, T]) swap(i, j int) {
-	n.rects[i], n.rects[j] = n.rects[j], n.rects[i]
+	n.rects.swap(i, j)
 	if n.leaf() {
 		n.items()[i], n.items()[j] = n.items()[j], n.items()[i]
+		if seqs := n.seqs(); seqs != nil {
+			seqs[i], seqs[j] = seqs[j], seqs[i]
+		}
 	} else {
 		n.children()[i], n.children()[j] = n.children()[j], n.children()[i]
 	}
@@ -538,9 +900,9 @@ func (n *node[N, T]) sort() {
 }
 
 func (n *node[N, T]) issorted() bool {
-	rects := n.rects[:n.count]
-	for i := 1; i < len(rects); i++ {
-		if rects[i].min[0] < rects[i-1].min[0] {
+	mins := n.rects.min[0][:n.count]
+	for i := 1; i < len(mins); i++ {
+		if mins[i] < mins[i-1] {
 			return false
 		}
 	}
@@ -555,18 +917,19 @@ func (n *node[N, T]) qsort(s, e int, axis int, rev, max bool) {
 	left, right := 0, nrects-1
 	pivot := nrects / 2 // rand and mod not worth it
 	n.swap(s+pivot, s+right)
-	rects := n.rects[s:e]
+	mins := n.rects.min[axis][s:e]
+	maxs := n.rects.max[axis][s:e]
 	if !rev {
 		if !max {
-			for i := 0; i < len(rects); i++ {
-				if rects[i].min[axis] < rects[right].min[axis] {
+			for i := 0; i < len(mins); i++ {
+				if mins[i] < mins[right] {
 					n.swap(s+i, s+left)
 					left++
 				}
 			}
 		} else {
-			for i := 0; i < len(rects); i++ {
-				if rects[i].max[axis] < rects[right].max[axis] {
+			for i := 0; i < len(maxs); i++ {
+				if maxs[i] < maxs[right] {
 					n.swap(s+i, s+left)
 					left++
 				}
@@ -574,15 +937,15 @@ func (n *node[N, T]) qsort(s, e int, axis int, rev, max bool) {
 		}
 	} else {
 		if !max {
-			for i := 0; i < len(rects); i++ {
-				if rects[right].min[axis] < rects[i].min[axis] {
+			for i := 0; i < len(mins); i++ {
+				if mins[right] < mins[i] {
 					n.swap(s+i, s+left)
 					left++
 				}
 			}
 		} else {
-			for i := 0; i < len(rects); i++ {
-				if rects[right].max[axis] < rects[i].max[axis] {
+			for i := 0; i < len(maxs); i++ {
+				if maxs[right] < maxs[i] {
 					n.swap(s+i, s+left)
 					left++
 				}
@@ -596,20 +959,32 @@ func (n *node[N, T]) qsort(s, e int, axis int, rev, max bool) {
 
 // Delete data from tree
 func (tr *RTreeGN[N, T]) Delete(min, max [2]N, data T) {
-	tr.delete(min, max, data)
+	tr.delete(min, max, data, 0)
 }
 
-func (tr *RTreeGN[N, T]) delete(min, max [2]N, data T) bool {
+// delete data from tree. When seq is not zero then the item is matched by
+// its sequence number instead of its data.
+func (tr *RTreeGN[N, T]) delete(min, max [2]N, data T, seq uint64) bool {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if writeGuardEnabled {
+		tr.writes.enter()
+		defer tr.writes.exit()
+	}
 	ir := rect[N]{min, max}
-	if tr.root == nil || !tr.rect.contains(&ir) {
+	if tr.root == nil || !tr.containsItem(&tr.rect, &ir) {
 		return false
 	}
 	var reinsert []*node[N, T]
 	tr.cow(&tr.root)
-	removed, _ := tr.nodeDelete(&tr.rect, tr.root, &ir, data, &reinsert)
+	nr, removed, _ := tr.nodeDelete(tr.rect, tr.root, &ir, data, seq,
+		&reinsert)
 	if !removed {
 		return false
 	}
+	tr.rect = nr
+	tr.gen++
 	tr.count--
 	if len(reinsert) > 0 {
 		for _, n := range reinsert {
@@ -617,19 +992,25 @@ func (tr *RTreeGN[N, T]) delete(min, max [2]N, data T) bool {
 		}
 	}
 	if tr.count == 0 {
+		tr.release(tr.root)
 		tr.root = nil
 		tr.rect.min = [2]N{0, 0}
 		tr.rect.max = [2]N{0, 0}
 	} else {
 		for !tr.root.leaf() && tr.root.count == 1 {
+			root := tr.root
 			tr.root = tr.root.children()[0]
+			root.count = 0
+			tr.release(root)
 		}
 	}
 	if len(reinsert) > 0 {
 		for i := range reinsert {
 			tr.nodeReinsert(reinsert[i])
+			tr.release(reinsert[i])
 		}
 	}
+	tr.fixAggs()
 	return true
 }
 
@@ -637,71 +1018,115 @@ func compare[T any](a, b T) bool {
 	return (interface{})(a) == (interface{})(b)
 }
 
-func (tr *RTreeGN[N, T]) nodeDelete(nr *rect[N], n *node[N, T], ir *rect[N], data T,
-	reinsert *[]*node[N, T],
-) (removed, shrunk bool) {
-	rects := n.rects[:n.count]
+// nodeDelete deletes the item from n, whose rect is nr, and returns the rect
+// of n after the delete.
+func (tr *RTreeGN[N, T]) nodeDelete(nr rect[N], n *node[N, T], ir *rect[N],
+	data T, seq uint64, reinsert *[]*node[N, T],
+) (r rect[N], removed, shrunk bool) {
+	count := int(n.count)
 	if n.leaf() {
 		items := n.items()
-		for i := 0; i < len(rects); i++ {
-			if ir.contains(&rects[i]) && compare(items[i], data) {
+		seqs := n.seqs()
+		if seq != 0 && seqs == nil {
+			return nr, false, false
+		}
+		for i := 0; i < count; i++ {
+			if n.ordered() && tr.eps == 0 && n.rects.min[0][i] > ir.max[0] {
+				// the remaining rects are all further to the right
+				break
+			}
+			if !n.rects.inside(i, ir) {
+				if !(tr.eps > 0) {
+					continue
+				}
+				if r := n.rects.at(i); !r.near(ir, tr.eps) {
+					continue
+				}
+			}
+			if (seq == 0 && tr.equal(items[i], data)) ||
+				(seq != 0 && seqs[i] == seq) {
 				// found the target item to delete
-				if orderLeaves {
-					copy(n.rects[i:n.count], n.rects[i+1:n.count])
+				dr := n.rects.at(i)
+				if tr.logging() {
+					tr.log(OpDelete, dr.min, dr.max, items[i])
+				}
+				if n.ordered() {
+					n.rects.move(i, i+1, count-i-1)
 					copy(items[i:n.count], items[i+1:n.count])
+					if seqs != nil {
+						copy(seqs[i:n.count], seqs[i+1:n.count])
+					}
 				} else {
-					n.rects[i] = n.rects[n.count-1]
+					n.rects.set(i, n.rects.at(count-1))
 					items[i] = items[n.count-1]
+					if seqs != nil {
+						seqs[i] = seqs[n.count-1]
+					}
+				}
+				items[count-1] = tr.empty
+				if seqs != nil {
+					seqs[count-1] = 0
 				}
-				items[len(rects)-1] = tr.empty
 				n.count--
-				shrunk = ir.onedge(nr)
+				shrunk = dr.onedge(&nr)
 				if shrunk {
-					*nr = n.rect()
+					nr = n.rect()
 				}
-				return true, shrunk
+				return nr, true, shrunk
 			}
 		}
-		return false, false
+		return nr, false, false
 	}
 	children := n.children()
-	for i := 0; i < len(rects); i++ {
-		if !rects[i].contains(ir) {
-			continue
+	for i := 0; i < count; i++ {
+		if n.ordered() && tr.eps == 0 && n.rects.min[0][i] > ir.min[0] {
+			// the remaining children cannot contain the rect
+			break
+		}
+		if !n.rects.contains(i, ir) {
+			if !(tr.eps > 0) {
+				continue
+			}
+			if r := n.rects.at(i); !r.containsNear(ir, tr.eps) {
+				continue
+			}
 		}
-		crect := rects[i]
+		crect := n.rects.at(i)
 		tr.cow(&children[i])
-		removed, shrunk = tr.nodeDelete(&rects[i], children[i], ir, data,
-			reinsert)
+		r, removed, shrunk = tr.nodeDelete(crect, children[i], ir, data,
+			seq, reinsert)
 		if !removed {
 			continue
 		}
-		if children[i].count == 0 {
+		n.rects.set(i, r)
+		minFill := tr.minFill * tr.fanout(children[i].leaf()) / maxEntries
+		if int(children[i].count) < minFill || children[i].count == 0 {
+			// The child is underfilled, so it's removed and its items are
+			// inserted again.
 			*reinsert = append(*reinsert, children[i])
-			if orderBranches {
-				copy(n.rects[i:n.count], n.rects[i+1:n.count])
+			if n.ordered() {
+				n.rects.move(i, i+1, count-i-1)
 				copy(children[i:n.count], children[i+1:n.count])
 			} else {
-				n.rects[i] = n.rects[n.count-1]
+				n.rects.set(i, n.rects.at(count-1))
 				children[i] = children[n.count-1]
 			}
 			children[n.count-1] = nil
 			n.count--
-			*nr = n.rect()
-			return true, true
+			return n.rect(), true, true
 		}
 		if shrunk {
-			shrunk = !rects[i].equals(&crect)
+			shrunk = !r.equals(&crect)
 			if shrunk {
-				*nr = n.rect()
+				nr = n.rect()
 			}
-			if orderBranches {
+			if n.ordered() {
 				_ = n.orderToRight(i)
 			}
 		}
-		return true, shrunk
+		return nr, true, shrunk
 	}
-	return false, false
+	return nr, false, false
 }
 
 func (r *rect[N]) equals(b *rect[N]) bool {
@@ -725,10 +1150,18 @@ func (n *node[N, T]) deepCount() int {
 
 func (tr *RTreeGN[N, T]) nodeReinsert(n *node[N, T]) {
 	if n.leaf() {
-		rects := n.rects[:n.count]
 		items := n.items()[:n.count]
-		for i := range rects {
-			tr.Insert(rects[i].min, rects[i].max, items[i])
+		seqs := n.seqs()
+		if len(items) > 0 && tr.hooks != nil && tr.hooks.OnReinsert != nil {
+			tr.hooks.OnReinsert(len(items))
+		}
+		for i := range items {
+			var seq uint64
+			if seqs != nil {
+				seq = seqs[i]
+			}
+			r := n.rects.at(i)
+			tr.insertItem(r.min, r.max, items[i], seq)
 		}
 	} else {
 		children := n.children()[:n.count]
@@ -738,6 +1171,15 @@ func (tr *RTreeGN[N, T]) nodeReinsert(n *node[N, T]) {
 	}
 }
 
+// containsItem returns true if the node rectangle r may contain an item that
+// matches the rectangle ir, taking the epsilon into account.
+func (tr *RTreeGN[N, T]) containsItem(r, ir *rect[N]) bool {
+	if tr.eps > 0 {
+		return r.containsNear(ir, tr.eps)
+	}
+	return r.contains(ir)
+}
+
 // onedge returns true when r is on the edge of b
 func (r *rect[N]) onedge(b *rect[N]) bool {
 	return !(r.min[0] > b.min[0] && r.min[1] > b.min[1] &&
@@ -750,7 +1192,7 @@ func (tr *RTreeGN[N, T]) Replace(
 	oldMin, oldMax [2]N, oldData T,
 	newMin, newMax [2]N, newData T,
 ) {
-	if tr.delete(oldMin, oldMax, oldData) {
+	if tr.delete(oldMin, oldMax, oldData, 0) {
 		tr.Insert(newMin, newMax, newData)
 	}
 }
@@ -789,13 +1231,14 @@ func (tr *RTreeGN[N, T]) TopMost() (min, max [2]N, data T) {
 func (n *node[N, T]) minist(dim int) (min, max [2]N, data T) {
 	var j int
 	var m N
-	for i, r := range n.rects[:n.count] {
-		if i == 0 || r.min[dim] < m {
-			j, m = i, r.min[dim]
+	for i, v := range n.rects.min[dim][:n.count] {
+		if i == 0 || v < m {
+			j, m = i, v
 		}
 	}
 	if n.leaf() {
-		return n.rects[j].min, n.rects[j].max, n.items()[j]
+		r := n.rects.at(j)
+		return r.min, r.max, n.items()[j]
 	}
 	return n.children()[j].minist(dim)
 }
@@ -803,13 +1246,14 @@ func (n *node[N, T]) minist(dim int) (min, max [2]N, data T) {
 func (n *node[N, T]) maxist(dim int) (min, max [2]N, data T) {
 	var j int
 	var m N
-	for i, r := range n.rects[:n.count] {
-		if i == 0 || r.max[dim] > m {
-			j, m = i, r.max[dim]
+	for i, v := range n.rects.max[dim][:n.count] {
+		if i == 0 || v > m {
+			j, m = i, v
 		}
 	}
 	if n.leaf() {
-		return n.rects[j].min, n.rects[j].max, n.items()[j]
+		r := n.rects.at(j)
+		return r.min, r.max, n.items()[j]
 	}
 	return n.children()[j].maxist(dim)
 }
@@ -839,10 +1283,46 @@ func (tr *RTreeGN[N, T]) Nearby(
 	}
 	q := tr.qpool.Get().(*queue[N, T])
 	defer func() {
+		clear(*q)
 		*q = (*q)[:0]
 		tr.qpool.Put(q)
 	}()
+	tr.nearby(q, dist, iter)
+}
+
+// NNQueue is a priority queue for nearest neighbor searches, which can be
+// reused across searches with NearbyReuse.
+// The zero value is an empty queue. A queue must not be used by more than
+// one search at a time.
+type NNQueue[N numeric, T any] struct {
+	q queue[N, T]
+}
 
+// NearbyReuse is like Nearby, but it uses the provided queue instead of one
+// from a pool that is shared by all searches of the tree.
+// Once the queue has grown to the size that is needed, searches don't
+// allocate, even when the pool would have been emptied by the garbage
+// collector, which keeps the latency steady for services that perform many
+// searches per second.
+func (tr *RTreeGN[N, T]) NearbyReuse(q *NNQueue[N, T],
+	dist func(min, max [2]N, data T, item bool) N,
+	iter func(min, max [2]N, data T, dist N) bool,
+) {
+	if tr.root == nil {
+		return
+	}
+	defer func() {
+		clear(q.q)
+		q.q = q.q[:0]
+	}()
+	tr.nearby(&q.q, dist, iter)
+}
+
+func (tr *RTreeGN[N, T]) nearby(q *queue[N, T],
+	dist func(min, max [2]N, data T, item bool) N,
+	iter func(min, max [2]N, data T, dist N) bool,
+) {
+	gen := tr.gen
 	q.push(qnode[N, T]{
 		dist: 0,
 		rect: tr.rect,
@@ -857,23 +1337,28 @@ func (tr *RTreeGN[N, T]) Nearby(
 			if !iter(qn.rect.min, qn.rect.max, qn.data, qn.dist) {
 				return
 			}
+			if tr.gen != gen {
+				panic(errModified)
+			}
 		} else {
-			rects := qn.node.rects[:qn.node.count]
+			rects := &qn.node.rects
 			if qn.node.leaf() {
 				items := qn.node.items()[:qn.node.count]
 				for i := 0; i < len(items); i++ {
+					r := rects.at(i)
 					q.push(qnode[N, T]{
-						dist: dist(rects[i].min, rects[i].max, items[i], true),
-						rect: rects[i],
+						dist: dist(r.min, r.max, items[i], true),
+						rect: r,
 						data: items[i],
 					})
 				}
 			} else {
 				children := qn.node.children()[:qn.node.count]
 				for i := 0; i < len(children); i++ {
+					r := rects.at(i)
 					q.push(qnode[N, T]{
-						dist: dist(rects[i].min, rects[i].max, tr.empty, false),
-						rect: rects[i],
+						dist: dist(r.min, r.max, tr.empty, false),
+						rect: r,
 						node: children[i],
 					})
 				}
@@ -882,6 +1367,29 @@ func (tr *RTreeGN[N, T]) Nearby(
 	}
 }
 
+// NearestIter returns an iterator over all items in the tree ordered by their
+// distance from the point p, nearest first.
+// Items are produced lazily using an incremental nearest-neighbor search, so
+// the caller may stop iterating at any time without having to know up front
+// how many items are needed.
+// The distance is the same squared box distance that is used by BoxDist.
+//
+//	for data, dist := range tr.NearestIter([2]float64{10, 20}) {
+//		if dist > 100 {
+//			break
+//		}
+//		println(data)
+//	}
+func (tr *RTreeGN[N, T]) NearestIter(p [2]N) iter.Seq2[T, N] {
+	return func(yield func(data T, dist N) bool) {
+		tr.Nearby(BoxDist[N, T](p, p, nil),
+			func(min, max [2]N, data T, dist N) bool {
+				return yield(data, dist)
+			},
+		)
+	}
+}
+
 type qnode[N numeric, T any] struct {
 	dist N           // distance to
 	rect rect[N]     // item or node rect
@@ -960,9 +1468,20 @@ func (r *rect[N]) boxDist(b *rect[N]) N {
 
 // Clear will delete all items.
 func (tr *RTreeGN[N, T]) Clear() {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if writeGuardEnabled {
+		tr.writes.enter()
+		defer tr.writes.exit()
+	}
+	tr.gen++
 	tr.count = 0
 	tr.rect = rect[N]{}
 	tr.root = nil
+	if tr.logging() {
+		tr.log(OpClear, [2]N{}, [2]N{}, tr.empty)
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -990,6 +1509,15 @@ func (tr *RTreeG[T]) Search(min, max [2]float64,
 	tr.base.Search(min, max, iter)
 }
 
+// SearchShape searches for items in tree that intersect the provided
+// rectangle and that pass the refine function.
+func (tr *RTreeG[T]) SearchShape(min, max [2]float64,
+	refine func(min, max [2]float64, data T) bool,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.SearchShape(min, max, refine, iter)
+}
+
 // Scan all items in the tree
 func (tr *RTreeG[T]) Scan(iter func(min, max [2]float64, data T) bool) {
 	tr.base.Scan(iter)
@@ -1047,9 +1575,8 @@ func (tr *RTreeG[T]) children(parent interface{}, reuse []child.Child,
 		// fill with child items
 		n := parent.(*node[float64, T])
 		for i := 0; i < int(n.count); i++ {
-			c := child.Child{
-				Min: n.rects[i].min, Max: n.rects[i].max, Item: n.leaf(),
-			}
+			r := n.rects.at(i)
+			c := child.Child{Min: r.min, Max: r.max, Item: n.leaf()}
 			if c.Item {
 				c.Data = n.items()[i]
 			} else {
@@ -1084,6 +1611,21 @@ func (tr *RTreeG[T]) Nearby(
 	tr.base.Nearby(dist, iter)
 }
 
+// NearbyReuse is like Nearby, but it uses the provided queue, which can be
+// reused across searches to avoid allocations.
+func (tr *RTreeG[T]) NearbyReuse(q *NNQueue[float64, T],
+	dist func(min, max [2]float64, data T, item bool) float64,
+	iter func(min, max [2]float64, data T, dist float64) bool,
+) {
+	tr.base.NearbyReuse(q, dist, iter)
+}
+
+// NearestIter returns an iterator over all items in the tree ordered by their
+// distance from the point p, nearest first.
+func (tr *RTreeG[T]) NearestIter(p [2]float64) iter.Seq2[T, float64] {
+	return tr.base.NearestIter(p)
+}
+
 // Clear will delete all items.
 func (tr *RTreeG[T]) Clear() {
 	tr.base.Clear()
@@ -1180,6 +1722,12 @@ func (tr *RTree) Nearby(
 	tr.base.Nearby(algo, iter)
 }
 
+// NearestIter returns an iterator over all items in the tree ordered by their
+// distance from the point p, nearest first.
+func (tr *RTree) NearestIter(p [2]float64) iter.Seq2[interface{}, float64] {
+	return tr.base.NearestIter(p)
+}
+
 // Copy the tree.
 // This is a copy-on-write operation and is very fast because it only performs
 // a shadowed copy.
@@ -0,0 +1,81 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSearcher(t *testing.T) {
+	var tr RTreeG[int]
+	s := tr.NewSearcher()
+	s.Search([2]float64{-180, -90}, [2]float64{180, 90},
+		func(min, max [2]float64, data int) bool {
+			t.Fatal("expected no items")
+			return false
+		},
+	)
+	for i := 0; i < 10000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	for i := 0; i < 100; i++ {
+		target := randRect('r')
+		target.max[0] += 10
+		target.max[1] += 10
+		var expect, got []int
+		tr.Search(target.min, target.max,
+			func(min, max [2]float64, data int) bool {
+				expect = append(expect, data)
+				return true
+			},
+		)
+		s.Search(target.min, target.max,
+			func(min, max [2]float64, data int) bool {
+				got = append(got, data)
+				return true
+			},
+		)
+		if !slices.Equal(got, expect) {
+			t.Fatalf("expected %v, got %v", expect, got)
+		}
+		if len(got) > 1 {
+			got = got[:0]
+			s.Search(target.min, target.max,
+				func(min, max [2]float64, data int) bool {
+					got = append(got, data)
+					return len(got) < 2
+				},
+			)
+			if !slices.Equal(got, expect[:2]) {
+				t.Fatalf("expected %v, got %v", expect[:2], got)
+			}
+		}
+	}
+	if len(s.stack) != 0 {
+		t.Fatalf("expected an empty stack, got %d", len(s.stack))
+	}
+	var count int
+	iter := func(min, max [2]float64, data int) bool {
+		count++
+		return true
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		count = 0
+		s.Search([2]float64{-10, -10}, [2]float64{10, 10}, iter)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+	expectPanic(t, func() {
+		s.Search([2]float64{-180, -90}, [2]float64{180, 90},
+			func(min, max [2]float64, data int) bool {
+				tr.Delete(min, max, data)
+				return true
+			},
+		)
+	})
+}
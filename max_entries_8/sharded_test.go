@@ -0,0 +1,91 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestShardedRTree(t *testing.T) {
+	tr := NewShardedRTree[float64, int](8, nil)
+	var expect RTreeG[int]
+	const n = 10000
+	rects := make([]rect[float64], n)
+	for i := range rects {
+		rects[i] = randRect('r')
+		expect.Insert(rects[i].min, rects[i].max, i)
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < n; i += 4 {
+				tr.Insert(rects[i].min, rects[i].max, i)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if tr.Len() != n {
+		t.Fatalf("expected %d, got %d", n, tr.Len())
+	}
+	for i := range tr.shards {
+		if tr.shards[i].tr.Len() == 0 {
+			t.Fatalf("expected items in shard %d", i)
+		}
+	}
+	min, max := tr.Bounds()
+	emin, emax := expect.Bounds()
+	if min != emin || max != emax {
+		t.Fatalf("expected %v %v, got %v %v", emin, emax, min, max)
+	}
+	search := func(r rect[float64]) []int {
+		var res []int
+		tr.Search(r.min, r.max, func(min, max [2]float64, data int) bool {
+			res = append(res, data)
+			return true
+		})
+		slices.Sort(res)
+		return res
+	}
+	for j := 0; j < 50; j++ {
+		r := randRect('r')
+		r.max[0] += 10
+		r.max[1] += 10
+		if !slices.Equal(search(r), bulkSearch(&expect, r)) {
+			t.Fatal("mismatch")
+		}
+	}
+	var count int
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		t.Fatalf("expected %d, got %d", 10, count)
+	}
+	// move every item, which usually moves it to another shard
+	for i := range rects {
+		r := randRect('r')
+		if !tr.Replace(rects[i].min, rects[i].max, i, r.min, r.max, i) {
+			t.Fatalf("item %d not found", i)
+		}
+		rects[i] = r
+	}
+	if tr.Replace(rects[0].min, rects[0].max, -1, rects[0].min,
+		rects[0].max, -1) {
+		t.Fatal("expected false")
+	}
+	for i := range rects {
+		if !tr.Delete(rects[i].min, rects[i].max, i) {
+			t.Fatalf("item %d not found", i)
+		}
+	}
+	if tr.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, tr.Len())
+	}
+}
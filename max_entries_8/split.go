@@ -0,0 +1,124 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "errors"
+
+var errInvalidSplit = errors.New("rtree: split must leave at least two " +
+	"entries in each node")
+
+// Splitter divides the entries of a full node between the node and a new
+// node. The default splitter is EdgeSnapSplitter.
+type Splitter[N numeric, T any] interface {
+	// Split is called with the entries of a node that is too full and the
+	// rectangle from min to max that bounds all of them. It must set
+	// right[i] to true for each entry that moves to the new node, leaving at
+	// least two entries in each node.
+	// The entries of a branch node have no data.
+	Split(leaf bool, min, max [2]N, entries []Item[N, T], right []bool)
+}
+
+// SetSplitter sets the splitter that is used for dividing the entries of a
+// node that is too full. Passing nil restores the default, which is the same
+// as EdgeSnapSplitter but faster because it works on the node directly.
+// Copies of the tree share the same splitter.
+func (tr *RTreeGN[N, T]) SetSplitter(s Splitter[N, T]) {
+	tr.split = s
+}
+
+func (tr *RTreeGN[N, T]) splitNodeWith(r rect[N], left *node[N, T],
+) (right *node[N, T]) {
+	count := int(left.count)
+	entries := make([]Item[N, T], count)
+	moves := make([]bool, count)
+	items := left.items()
+	for i := range entries {
+		er := left.rects.at(i)
+		entries[i].Min, entries[i].Max = er.min, er.max
+		if items != nil {
+			entries[i].Data = items[i]
+		}
+	}
+	tr.split.Split(left.leaf(), r.min, r.max, entries, moves)
+	var nright int
+	for _, move := range moves {
+		if move {
+			nright++
+		}
+	}
+	if nright < 2 || count-nright < 2 {
+		panic(errInvalidSplit)
+	}
+	right = tr.newNode(left.leaf())
+	// Going backwards keeps the indexes of the remaining entries intact,
+	// because an entry is replaced by the last entry when it's moved.
+	for i := count - 1; i >= 0; i-- {
+		if moves[i] {
+			tr.moveRectAtIndexInto(left, i, right)
+		}
+	}
+	if right.ordered() {
+		if !right.issorted() {
+			right.sort()
+		}
+		if !left.issorted() {
+			left.sort()
+		}
+	}
+	return right
+}
+
+type edgeSnapSplitter[N numeric, T any] struct{}
+
+// EdgeSnapSplitter returns the default splitter, which divides the entries
+// along the largest axis of the node. Each entry goes to the side whose edge
+// it's closest to.
+func EdgeSnapSplitter[N numeric, T any]() Splitter[N, T] {
+	return edgeSnapSplitter[N, T]{}
+}
+
+func (edgeSnapSplitter[N, T]) Split(leaf bool, min, max [2]N,
+	entries []Item[N, T], right []bool,
+) {
+	axis := rect[N]{min, max}.largestAxis()
+	var nright int
+	for i, e := range entries {
+		minDist := float64(e.Min[axis]) - float64(min[axis])
+		maxDist := float64(max[axis]) - float64(e.Max[axis])
+		right[i] = !(minDist < maxDist)
+		if right[i] {
+			nright++
+		}
+	}
+	// Make sure that both sides have at least two entries by moving the
+	// entries that start first to the left, or the ones that end first to
+	// the right.
+	for nright > len(entries)-2 {
+		j := -1
+		for i, e := range entries {
+			if right[i] && (j == -1 || e.Min[axis] < entries[j].Min[axis]) {
+				j = i
+			}
+		}
+		right[j] = false
+		nright--
+	}
+	for nright < 2 {
+		j := -1
+		for i, e := range entries {
+			if !right[i] && (j == -1 || e.Max[axis] < entries[j].Max[axis]) {
+				j = i
+			}
+		}
+		right[j] = true
+		nright++
+	}
+}
+
+// SetSplitter sets the splitter that is used for dividing the entries of a
+// node that is too full. Passing nil restores the default.
+func (tr *RTreeG[T]) SetSplitter(s Splitter[float64, T]) {
+	tr.base.SetSplitter(s)
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"slices"
+)
+
+// compareRects orders rectangles by their min and then by their max.
+func compareRects[N numeric](a, b *rect[N]) int {
+	for i := 0; i < 2; i++ {
+		if c := cmp.Compare(a.min[i], b.min[i]); c != 0 {
+			return c
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if c := cmp.Compare(a.max[i], b.max[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// NearbyStable is like Nearby, but items that are at the same distance are
+// returned in a deterministic order, which does not depend on the shape of
+// the tree, so results are the same for trees with the same items no matter
+// how they were built.
+// Items at the same distance are ordered using the cmp function, and then by
+// their rectangles. A nil cmp orders them by their rectangles only.
+//
+// Items at the same distance are returned once the first item at a greater
+// distance has been found, so iter may be called slightly later than it
+// would be with Nearby.
+func (tr *RTreeGN[N, T]) NearbyStable(
+	dist func(min, max [2]N, data T, item bool) N,
+	cmp func(a, b T) int,
+	iter func(min, max [2]N, data T, dist N) bool,
+) {
+	var run []Item[N, T]
+	var runDist N
+	flush := func() bool {
+		slices.SortFunc(run, func(a, b Item[N, T]) int {
+			if cmp != nil {
+				if c := cmp(a.Data, b.Data); c != 0 {
+					return c
+				}
+			}
+			return compareRects(&rect[N]{a.Min, a.Max},
+				&rect[N]{b.Min, b.Max})
+		})
+		for i := range run {
+			if !iter(run[i].Min, run[i].Max, run[i].Data, runDist) {
+				return false
+			}
+		}
+		clear(run)
+		run = run[:0]
+		return true
+	}
+	stopped := false
+	tr.Nearby(dist, func(min, max [2]N, data T, dist N) bool {
+		if len(run) > 0 && dist != runDist {
+			if !flush() {
+				stopped = true
+				return false
+			}
+		}
+		runDist = dist
+		run = append(run, Item[N, T]{min, max, data})
+		return true
+	})
+	if !stopped && len(run) > 0 {
+		flush()
+	}
+}
+
+// NearbyStable is like Nearby, but items that are at the same distance are
+// returned in a deterministic order.
+func (tr *RTreeG[T]) NearbyStable(
+	dist func(min, max [2]float64, data T, item bool) float64,
+	cmp func(a, b T) int,
+	iter func(min, max [2]float64, data T, dist float64) bool,
+) {
+	tr.base.NearbyStable(dist, cmp, iter)
+}
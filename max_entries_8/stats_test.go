@@ -0,0 +1,32 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	var tr RTreeG[int]
+	s := tr.Stats()
+	if s.Height != 0 || s.Items != 0 || s.Leaves != 0 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	tr.Insert([2]float64{1, 1}, [2]float64{1, 1}, 1)
+	s = tr.Stats()
+	if s.Height != 1 || s.Items != 1 || s.Leaves != 1 || s.Branches != 0 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	items := make([]Item[float64, int], maxEntries*maxEntries*2)
+	for i := range items {
+		r := randRect('p')
+		items[i] = Item[float64, int]{r.min, r.max, i}
+	}
+	tr.Clear()
+	tr.LoadBulk(items)
+	s = tr.Stats()
+	if s.Height != 3 || s.Items != len(items) || s.Leaves != maxEntries*2 ||
+		s.LeafFill != 1 || s.MaxEntries != maxEntries {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+}
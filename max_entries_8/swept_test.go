@@ -0,0 +1,82 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func movedRect(min, max, delta [2]float64, t float64) rect[float64] {
+	return rect[float64]{
+		[2]float64{min[0] + delta[0]*t, min[1] + delta[1]*t},
+		[2]float64{max[0] + delta[0]*t, max[1] + delta[1]*t},
+	}
+}
+
+func TestSearchSwept(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for j := 0; j < 50; j++ {
+		min := [2]float64{rand.Float64()*360 - 180, rand.Float64()*180 - 90}
+		max := [2]float64{min[0] + rand.Float64()*5, min[1] + rand.Float64()*5}
+		delta := [2]float64{rand.Float64()*100 - 50, rand.Float64()*100 - 50}
+		found := make(map[int]float64)
+		tr.SearchSwept(min, max, delta,
+			func(rmin, rmax [2]float64, data int, toi float64) bool {
+				found[data] = toi
+				return true
+			},
+		)
+		for i := range rects {
+			toi, ok := found[i]
+			var hit bool
+			for k := 0; k <= 1000; k++ {
+				m := movedRect(min, max, delta, float64(k)/1000)
+				if m.intersects(&rects[i]) {
+					hit = true
+					break
+				}
+			}
+			if hit && !ok {
+				t.Fatalf("item %d not found", i)
+			}
+			if !ok {
+				continue
+			}
+			if toi < 0 || toi > 1 {
+				t.Fatalf("bad toi %v", toi)
+			}
+			m := movedRect(min, max, delta, toi+1e-9)
+			if !m.intersects(&rects[i]) {
+				t.Fatalf("item %d not touched at toi %v", i, toi)
+			}
+			if toi > 1e-6 {
+				m = movedRect(min, max, delta, toi-1e-6)
+				if m.intersects(&rects[i]) {
+					t.Fatalf("item %d touched before toi %v", i, toi)
+				}
+			}
+		}
+	}
+	// already overlapping
+	var tr2 RTreeG[int]
+	tr2.Insert([2]float64{0, 0}, [2]float64{10, 10}, 1)
+	tr2.Insert([2]float64{20, 0}, [2]float64{30, 10}, 2)
+	tois := make(map[int]float64)
+	tr2.SearchSwept([2]float64{5, 5}, [2]float64{6, 6}, [2]float64{20, 0},
+		func(min, max [2]float64, data int, toi float64) bool {
+			tois[data] = toi
+			return true
+		},
+	)
+	if len(tois) != 2 || tois[1] != 0 || tois[2] != 0.7 {
+		t.Fatalf("got %v", tois)
+	}
+}
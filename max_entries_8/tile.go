@@ -0,0 +1,71 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "math"
+
+// maxMercatorLat is the latitude, in degrees, where the Web Mercator
+// projection is cut off to make the world square.
+const maxMercatorLat = 85.05112877980659
+
+// Tile is a Web Mercator XYZ map tile, where X grows to the east and Y grows
+// to the south, like the tiles of most web maps.
+type Tile struct {
+	Z, X, Y uint32
+}
+
+// TileBounds returns the bounding rectangle of the tile, where X is
+// longitude and Y is latitude in degrees.
+func TileBounds(z, x, y uint32) (min, max [2]float64) {
+	n := math.Exp2(float64(z))
+	min[0] = float64(x)/n*360 - 180
+	max[0] = float64(x+1)/n*360 - 180
+	min[1] = tileLat(float64(y+1) / n)
+	max[1] = tileLat(float64(y) / n)
+	return min, max
+}
+
+// tileLat returns the latitude of a tile edge, where y is from 0 at the top
+// of the world to 1 at the bottom.
+func tileLat(y float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y))) * degrees
+}
+
+// tileXY returns the tile that contains the point at the zoom level.
+func tileXY(lon, lat float64, z uint32) (x, y uint32) {
+	n := math.Exp2(float64(z))
+	lat = math.Max(-maxMercatorLat, math.Min(maxMercatorLat, lat))
+	fx := (lon + 180) / 360 * n
+	fy := (1 - math.Asinh(math.Tan(lat*radians))/math.Pi) / 2 * n
+	fx = math.Max(0, math.Min(n-1, math.Floor(fx)))
+	fy = math.Max(0, math.Min(n-1, math.Floor(fy)))
+	return uint32(fx), uint32(fy)
+}
+
+// CoveringTiles returns the tiles at the zoom level that intersect the
+// rectangle, where X is longitude and Y is latitude in degrees.
+// Latitudes beyond the cut off of the Web Mercator projection, about 85.05
+// degrees, are clamped.
+func CoveringTiles(min, max [2]float64, zoom uint32) []Tile {
+	x1, y1 := tileXY(min[0], max[1], zoom)
+	x2, y2 := tileXY(max[0], min[1], zoom)
+	tiles := make([]Tile, 0, int(x2-x1+1)*int(y2-y1+1))
+	for y := y1; y <= y2; y++ {
+		for x := x1; x <= x2; x++ {
+			tiles = append(tiles, Tile{zoom, x, y})
+		}
+	}
+	return tiles
+}
+
+// SearchTile searches for items in the tree that intersect the Web Mercator
+// XYZ tile, where X is longitude and Y is latitude in degrees.
+// Items on the edge between two tiles are returned for both tiles.
+func (tr *RTreeG[T]) SearchTile(z, x, y uint32,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	min, max := TileBounds(z, x, y)
+	tr.Search(min, max, iter)
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTiles(t *testing.T) {
+	min, max := TileBounds(0, 0, 0)
+	if min[0] != -180 || max[0] != 180 ||
+		math.Abs(min[1]+maxMercatorLat) > 1e-9 ||
+		math.Abs(max[1]-maxMercatorLat) > 1e-9 {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	min, max = TileBounds(1, 1, 0)
+	if min != [2]float64{0, 0} || max[0] != 180 {
+		t.Fatalf("unexpected bounds %v %v", min, max)
+	}
+	tiles := CoveringTiles([2]float64{-10, -10}, [2]float64{10, 10}, 1)
+	if len(tiles) != 4 {
+		t.Fatalf("expected %d, got %v", 4, tiles)
+	}
+	tiles = CoveringTiles([2]float64{1, 1}, [2]float64{2, 2}, 1)
+	if len(tiles) != 1 || tiles[0] != (Tile{1, 1, 0}) {
+		t.Fatalf("expected %v, got %v", Tile{1, 1, 0}, tiles)
+	}
+	tiles = CoveringTiles([2]float64{-180, -90}, [2]float64{180, 90}, 3)
+	if len(tiles) != 64 {
+		t.Fatalf("expected %d, got %d", 64, len(tiles))
+	}
+	// every tile contains the points that it's the covering tile of
+	for i := 0; i < 1000; i++ {
+		r := randRect('p')
+		for z := uint32(0); z < 20; z += 3 {
+			tiles := CoveringTiles(r.min, r.max, z)
+			if len(tiles) != 1 {
+				t.Fatalf("expected %d, got %d", 1, len(tiles))
+			}
+			min, max := TileBounds(tiles[0].Z, tiles[0].X, tiles[0].Y)
+			b := rect[float64]{min, max}
+			if math.Abs(r.min[1]) < maxMercatorLat && !b.contains(&r) {
+				t.Fatalf("tile %v %v does not contain %v", tiles[0], b, r)
+			}
+		}
+	}
+
+	var tr RTreeG[int]
+	tr.Insert([2]float64{1, 1}, [2]float64{1, 1}, 1)
+	tr.Insert([2]float64{-1, 1}, [2]float64{-1, 1}, 2)
+	var found []int
+	tr.SearchTile(1, 1, 0, func(min, max [2]float64, data int) bool {
+		found = append(found, data)
+		return true
+	})
+	if len(found) != 1 || found[0] != 1 {
+		t.Fatalf("expected %v, got %v", []int{1}, found)
+	}
+}
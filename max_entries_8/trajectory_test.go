@@ -0,0 +1,98 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrajectory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var tr SpatioTemporalTree[string]
+	// a track that moves one unit east every minute, inserted out of order
+	var east []TimedPoint
+	for i := 60; i >= 0; i-- {
+		east = append(east, TimedPoint{[2]float64{float64(i), 0},
+			start.Add(time.Duration(i) * time.Minute)})
+	}
+	tr.InsertTrajectory(east, 10*time.Minute, "east")
+	if tr.Len() != 6 {
+		t.Fatalf("expected 6 segments, got %d", tr.Len())
+	}
+	// a track that moves one unit north every minute, with a gap of an hour
+	var north []TimedPoint
+	for i := 0; i <= 20; i++ {
+		ts := start.Add(time.Duration(i) * time.Minute)
+		if i > 10 {
+			ts = ts.Add(time.Hour)
+		}
+		north = append(north, TimedPoint{[2]float64{0, float64(i)}, ts})
+	}
+	tr.InsertTrajectory(north, 10*time.Minute, "north")
+	tr.InsertTrajectory([]TimedPoint{{[2]float64{5, 5}, start}}, time.Minute,
+		"still")
+	if tr.Len() != 6+3+1 {
+		t.Fatalf("expected 10 segments, got %d", tr.Len())
+	}
+	search := func(min, max [2]float64, from, to time.Time,
+	) map[string][]TrajectorySegment {
+		res := make(map[string][]TrajectorySegment)
+		tr.SearchTrajectory(min, max, from, to,
+			func(track string, segments []TrajectorySegment) bool {
+				if _, ok := res[track]; ok {
+					t.Fatalf("track %q was returned twice", track)
+				}
+				res[track] = segments
+				return true
+			})
+		return res
+	}
+
+	// everything at the start
+	res := search([2]float64{-1, -1}, [2]float64{100, 100}, start, start)
+	if len(res) != 3 || len(res["east"]) != 1 || len(res["north"]) != 1 ||
+		len(res["still"]) != 1 {
+		t.Fatalf("unexpected result %v", res)
+	}
+
+	// the segments share their end points
+	res = search([2]float64{-1, -1}, [2]float64{100, 0}, start,
+		start.Add(2*time.Hour))
+	segs := res["east"]
+	if len(segs) != 6 {
+		t.Fatalf("expected 6 segments, got %d", len(segs))
+	}
+	for i, seg := range segs {
+		from := start.Add(time.Duration(i*10) * time.Minute)
+		if !seg.From.Equal(from) || !seg.To.Equal(from.Add(10*time.Minute)) ||
+			seg.Min != [2]float64{float64(i * 10), 0} ||
+			seg.Max != [2]float64{float64(i*10 + 10), 0} {
+			t.Fatalf("unexpected segment %d: %v", i, seg)
+		}
+	}
+
+	// the gap is covered by a segment that spans an hour
+	res = search([2]float64{-1, 10.5}, [2]float64{1, 10.5},
+		start.Add(30*time.Minute), start.Add(31*time.Minute))
+	if len(res) != 1 || len(res["north"]) != 1 {
+		t.Fatalf("unexpected result %v", res)
+	}
+	seg := res["north"][0]
+	if seg.Min != [2]float64{0, 10} || seg.Max != [2]float64{0, 11} {
+		t.Fatalf("unexpected segment %v", seg)
+	}
+
+	// stop after the first track
+	var count int
+	tr.SearchTrajectory([2]float64{-1, -1}, [2]float64{100, 100}, start,
+		start, func(track string, segments []TrajectorySegment) bool {
+			count++
+			return false
+		})
+	if count != 1 {
+		t.Fatalf("expected 1, got %d", count)
+	}
+}
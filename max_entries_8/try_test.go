@@ -0,0 +1,54 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTry(t *testing.T) {
+	var tr RTreeG[int]
+	a, b := [2]float64{1, 1}, [2]float64{2, 2}
+	if err := tr.TryInsert(a, b, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.TryDelete(a, b, 2); err != ErrNotFound {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+	if err := tr.TryReplace(a, b, 2, a, b, 3); err != ErrNotFound {
+		t.Fatalf("expected %v, got %v", ErrNotFound, err)
+	}
+	err := tr.TryReplace(a, b, 1, [2]float64{math.NaN(), 0}, b, 3)
+	if err != ErrInvalidRect {
+		t.Fatalf("expected %v, got %v", ErrInvalidRect, err)
+	}
+	if !tr.Exists(a, b, 1) {
+		t.Fatal("expected item to be kept")
+	}
+	if err := tr.TryReplace(a, b, 1, b, b, 3); err != nil {
+		t.Fatal(err)
+	}
+	if tr.Exists(a, b, 1) || !tr.Exists(b, b, 3) {
+		t.Fatal("expected item to be replaced")
+	}
+	tr.Freeze()
+	for _, err := range []error{
+		tr.TryInsert(a, b, 4),
+		tr.TryDelete(b, b, 3),
+		tr.TryReplace(b, b, 3, a, b, 4),
+	} {
+		if err != ErrFrozen {
+			t.Fatalf("expected %v, got %v", ErrFrozen, err)
+		}
+	}
+	if tr.Len() != 1 {
+		t.Fatalf("expected %d, got %d", 1, tr.Len())
+	}
+	tr2 := tr.Copy()
+	if err := tr2.TryDelete(b, b, 3); err != nil || tr2.Len() != 0 {
+		t.Fatalf("expected delete, got %v", err)
+	}
+}
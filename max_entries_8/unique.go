@@ -0,0 +1,57 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// InsertUnique inserts data into the tree, unless an item with the same
+// rectangle and data already exists, in which case nothing is inserted and
+// false is returned.
+func (tr *RTreeGN[N, T]) InsertUnique(min, max [2]N, data T) bool {
+	if tr.Exists(min, max, data) {
+		return false
+	}
+	tr.insert(min, max, data, 0)
+	return true
+}
+
+// findEqual returns the first item with exactly the same rectangle as ir that
+// matches. Only children that contain ir are visited, like delete does, and
+// because nodes are ordered by their min x, the scan of each node can stop as
+// soon as a rectangle starts to the right of ir.
+func (n *node[N, T]) findEqual(ir *rect[N], match func(data T) bool,
+) (T, bool) {
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			if n.ordered() && n.rects.min[0][i] > ir.min[0] {
+				break
+			}
+			if r := n.rects.at(i); r.equals(ir) && match(items[i]) {
+				return items[i], true
+			}
+		}
+		var empty T
+		return empty, false
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		if n.ordered() && n.rects.min[0][i] > ir.min[0] {
+			break
+		}
+		if r := n.rects.at(i); r.contains(ir) {
+			if data, ok := children[i].findEqual(ir, match); ok {
+				return data, true
+			}
+		}
+	}
+	var empty T
+	return empty, false
+}
+
+// InsertUnique inserts data into the tree, unless an item with the same
+// rectangle and data already exists, in which case nothing is inserted and
+// false is returned.
+func (tr *RTreeG[T]) InsertUnique(min, max [2]float64, data T) bool {
+	return tr.base.InsertUnique(min, max, data)
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "testing"
+
+func TestInsertUnique(t *testing.T) {
+	var tr RTreeG[int]
+	rects := make([]rect[float64], 10000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		if !tr.InsertUnique(rects[i].min, rects[i].max, i) {
+			t.Fatalf("item %d not inserted", i)
+		}
+	}
+	for i := range rects {
+		if tr.InsertUnique(rects[i].min, rects[i].max, i) {
+			t.Fatalf("duplicate item %d inserted", i)
+		}
+	}
+	if tr.Len() != len(rects) {
+		t.Fatalf("expected %d, got %d", len(rects), tr.Len())
+	}
+	// same rect, different data
+	if !tr.InsertUnique(rects[0].min, rects[0].max, -1) {
+		t.Fatal("expected insert")
+	}
+	// same data, different rect
+	if !tr.InsertUnique(rects[1].min, rects[0].max, 0) {
+		t.Fatal("expected insert")
+	}
+	if tr.Len() != len(rects)+2 {
+		t.Fatalf("expected %d, got %d", len(rects)+2, tr.Len())
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// SetWeight sets the function that returns the numeric weight of an item,
+// such as the population of a city.
+// The total weight of every node is maintained as items are inserted and
+// deleted, which allows for SumWeight to answer quickly without visiting
+// every item in the searched area.
+// Passing nil removes the weight function.
+func (tr *RTreeGN[N, T]) SetWeight(weight func(data T) float64) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.weight != nil {
+		tr.removeAggregator(tr.weight.idx)
+		tr.weight = nil
+	}
+	if weight != nil {
+		ai := &aggIndex[N, T, float64]{agg: Aggregator[T, float64]{
+			Item:  weight,
+			Merge: func(a, b float64) float64 { return a + b },
+		}}
+		tr.addAggregator(ai, &ai.idx)
+		tr.weight = ai
+	}
+}
+
+// SumWeight returns the total weight of all items that intersect the provided
+// rectangle. The weight of an item is determined by the function provided to
+// SetWeight, or is 1 when no weight function has been set.
+// Nodes that are fully inside of the rectangle are not visited, instead their
+// maintained total weight is used.
+func (tr *RTreeGN[N, T]) SumWeight(min, max [2]N) float64 {
+	if tr.weight == nil {
+		var sum float64
+		tr.Search(min, max, func(min, max [2]N, data T) bool {
+			sum++
+			return true
+		})
+		return sum
+	}
+	sum, _ := tr.weight.query(tr, &rect[N]{min, max})
+	return sum
+}
+
+// SetWeight sets the function that returns the numeric weight of an item.
+// Passing nil removes the weight function.
+func (tr *RTreeG[T]) SetWeight(weight func(data T) float64) {
+	tr.base.SetWeight(weight)
+}
+
+// SumWeight returns the total weight of all items that intersect the provided
+// rectangle.
+func (tr *RTreeG[T]) SumWeight(min, max [2]float64) float64 {
+	return tr.base.SumWeight(min, max)
+}
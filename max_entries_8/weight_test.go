@@ -0,0 +1,73 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func sumWeightBrute(tr *RTreeG[int], min, max [2]float64) float64 {
+	var sum float64
+	tr.Search(min, max, func(min, max [2]float64, data int) bool {
+		sum += float64(data % 10)
+		return true
+	})
+	return sum
+}
+
+func TestSumWeight(t *testing.T) {
+	var tr RTreeG[int]
+	N := 10000
+	rects := make([]rect[float64], N)
+	for i := 0; i < N/2; i++ {
+		rects[i] = randRect('m')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	if tr.SumWeight([2]float64{-180, -90}, [2]float64{180, 90}) != float64(N/2) {
+		t.Fatal("expected item count without weight function")
+	}
+	// set the weight on a tree that is shared with a copy
+	snap := tr.Copy()
+	tr.SetWeight(func(data int) float64 { return float64(data % 10) })
+	for i := N / 2; i < N; i++ {
+		rects[i] = randRect('m')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	check := func() {
+		t.Helper()
+		for i := 0; i < 100; i++ {
+			q := randRect('r')
+			q.max[0] = q.min[0] + rand.Float64()*100
+			q.max[1] = q.min[1] + rand.Float64()*50
+			expect := sumWeightBrute(&tr, q.min, q.max)
+			got := tr.SumWeight(q.min, q.max)
+			if got != expect {
+				t.Fatalf("expected %v, got %v", expect, got)
+			}
+		}
+		var total float64
+		tr.Scan(func(min, max [2]float64, data int) bool {
+			total += float64(data % 10)
+			return true
+		})
+		got := tr.SumWeight([2]float64{-1000, -1000}, [2]float64{1000, 1000})
+		if got != total {
+			t.Fatalf("expected %v, got %v", total, got)
+		}
+	}
+	check()
+	for _, i := range rand.Perm(N)[:N/2] {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+	check()
+	tr.ScanDelete(func(min, max [2]float64, data int) bool {
+		return data%3 == 0
+	})
+	check()
+	if snap.base.root.aggs != nil {
+		t.Fatal("shared nodes were modified")
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build rtreedebug
+
+package rtree
+
+import "testing"
+
+func TestWriteGuard(t *testing.T) {
+	var tr RTreeG[int]
+	for i := 0; i < 1000; i++ {
+		r := randRect('r')
+		tr.Insert(r.min, r.max, i)
+	}
+	tr2 := tr.Copy()
+	// simulate a write that is still in progress on another goroutine
+	tr.base.writes.enter()
+	r := randRect('r')
+	expectPanic(t, func() { tr.Insert(r.min, r.max, -1) })
+	expectPanic(t, func() { tr.Delete(r.min, r.max, -1) })
+	expectPanic(t, func() { tr.Copy() })
+	expectPanic(t, func() { tr.Clear() })
+	// the copy has its own guard
+	tr2.Insert(r.min, r.max, -1)
+	tr.base.writes.exit()
+	tr.Insert(r.min, r.max, -1)
+	if tr.Len() != 1001 || tr2.Len() != 1001 {
+		t.Fatal("expected 1001 items")
+	}
+	// frozen trees are never written, so they may be copied concurrently
+	tr.Freeze()
+	tr.base.writes.enter()
+	tr.Copy()
+	tr.base.writes.exit()
+}
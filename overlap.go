@@ -0,0 +1,79 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// ChooseSubtree is the strategy for picking the subtree that a new item is
+// inserted into.
+type ChooseSubtree int
+
+const (
+	// LeastEnlargement picks the subtree whose rectangle needs the least
+	// area enlargement to include the item. This is the default.
+	LeastEnlargement ChooseSubtree = iota
+	// LeastOverlap picks the subtree whose rectangle has the least increase
+	// in overlap with its siblings when the item is included, like the
+	// R*-tree. It's only used in the nodes just above the leaves, where it
+	// matters most, and the other nodes use LeastEnlargement.
+	// Inserts are slower, but searches may be much faster for heavily
+	// overlapping items, such as road segments.
+	LeastOverlap
+)
+
+// SetChooseSubtree sets the strategy for picking the subtree that a new item
+// is inserted into.
+// Changing the strategy does not change the items that are already in the
+// tree.
+func (tr *RTreeGN[N, T]) SetChooseSubtree(choose ChooseSubtree) {
+	tr.choose = choose
+}
+
+// chooseLeastOverlap returns the index of the child whose rect has the least
+// increase in overlap with the other children when it's expanded to include
+// ir. Ties go to the least enlargement, and then to the smallest area.
+func (n *node[N, T]) chooseLeastOverlap(ir *rect[N]) (index int) {
+	count := int(n.count)
+	j := -1
+	var joverlap, jenlargement, jarea float64
+	for i := 0; i < count; i++ {
+		r := n.rects.at(i)
+		u := r
+		u.expand(ir)
+		var overlap float64
+		for k := 0; k < count; k++ {
+			if k != i {
+				kr := n.rects.at(k)
+				overlap += u.overlapArea(&kr) - r.overlapArea(&kr)
+			}
+		}
+		area := r.area()
+		enlargement := u.area() - area
+		if j == -1 || overlap < joverlap ||
+			(!(overlap > joverlap) && (enlargement < jenlargement ||
+				(!(enlargement > jenlargement) && area < jarea))) {
+			j, joverlap, jenlargement, jarea = i, overlap, enlargement, area
+		}
+	}
+	return j
+}
+
+// overlapArea returns the area of the intersection of r and b.
+func (r *rect[N]) overlapArea(b *rect[N]) float64 {
+	area := 1.0
+	for i := 0; i < 2; i++ {
+		span := float64(fmin(r.max[i], b.max[i])) -
+			float64(fmax(r.min[i], b.min[i]))
+		if !(span > 0) {
+			return 0
+		}
+		area *= span
+	}
+	return area
+}
+
+// SetChooseSubtree sets the strategy for picking the subtree that a new item
+// is inserted into.
+func (tr *RTreeG[T]) SetChooseSubtree(choose ChooseSubtree) {
+	tr.base.SetChooseSubtree(choose)
+}
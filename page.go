@@ -0,0 +1,179 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math"
+	"slices"
+)
+
+var errPageToken = errors.New("rtree: invalid page token")
+
+const pageTokenVersion = 1
+
+// pageToken is the position after the last item of a page. For OrderByMinX
+// and OrderByDistance it's the key of the last item, along with the number
+// of items with that key that have been returned. For OrderByCustom it's the
+// number of items that have been returned.
+type pageToken struct {
+	key  float64
+	skip uint64
+}
+
+func (o *Order[N, T]) encodeToken(t pageToken) string {
+	b := []byte{pageTokenVersion, byte(o.kind)}
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(t.key))
+	b = binary.AppendUvarint(b, t.skip)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (o *Order[N, T]) decodeToken(s string) (pageToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) < 10 || b[0] != pageTokenVersion ||
+		b[1] != byte(o.kind) {
+		return pageToken{}, errPageToken
+	}
+	key := math.Float64frombits(binary.LittleEndian.Uint64(b[2:]))
+	skip, n := binary.Uvarint(b[10:])
+	if n <= 0 || 10+n != len(b) {
+		return pageToken{}, errPageToken
+	}
+	return pageToken{key, skip}, nil
+}
+
+// maxKey returns the largest key that the items inside of a rectangle can
+// have.
+func (o *Order[N, T]) maxKey(r *rect[N]) float64 {
+	if o.kind == orderDistance {
+		var dist float64
+		for axis := 0; axis < 2; axis++ {
+			p := float64(o.point.min[axis])
+			d := max(math.Abs(p-float64(r.min[axis])),
+				math.Abs(p-float64(r.max[axis])))
+			dist += d * d
+		}
+		return dist
+	}
+	return float64(r.max[0])
+}
+
+// SearchPage returns a page of at most limit items that intersect the
+// provided rectangle, in the provided order, like SearchOrdered, along with
+// the token for the next page. Pass an empty token for the first page. The
+// next token is empty when there are no more items.
+//
+// The tokens are opaque strings that are safe to use in URLs. A token holds
+// the position after the last item of its page, rather than the number of
+// items before it, so for OrderByMinX and OrderByDistance the next page
+// starts after the same item even when items were inserted or deleted
+// before it, unless they have the same key as that item, and the parts of
+// the tree that are before the position are skipped. The pages of an
+// unchanged tree never repeat or miss an item.
+// Tokens of OrderByCustom hold the number of items before the position.
+//
+// A limit of zero or less returns all remaining items.
+// Returns an error when the token is invalid, or was returned for another
+// kind of order.
+func (tr *RTreeGN[N, T]) SearchPage(min, max [2]N, order Order[N, T],
+	token string, limit int,
+) (items []Item[N, T], next string, err error) {
+	var pos pageToken
+	hasPos := token != ""
+	if hasPos {
+		if pos, err = order.decodeToken(token); err != nil {
+			return nil, "", err
+		}
+	}
+	if limit <= 0 {
+		limit = math.MaxInt
+	}
+	if order.kind == orderCustom {
+		tr.SearchOrdered(min, max, order,
+			func(min, max [2]N, data T) bool {
+				items = append(items, Item[N, T]{min, max, data})
+				return true
+			})
+		if pos.skip >= uint64(len(items)) {
+			return nil, "", nil
+		}
+		items = items[pos.skip:]
+		if len(items) > limit {
+			items = items[:limit]
+			next = order.encodeToken(pageToken{skip: pos.skip +
+				uint64(limit)})
+		}
+		return slices.Clip(items), next, nil
+	}
+	target := rect[N]{min, max}
+	if tr.root == nil || !target.intersects(&tr.rect) {
+		return nil, "", nil
+	}
+	var last pageToken
+	var skipped uint64
+	var q pqueue[topkElem[N, T]]
+	q.push(order.key(&tr.rect),
+		topkElem[N, T]{rect: tr.rect, node: tr.root})
+	for {
+		key, e, ok := q.pop()
+		if !ok {
+			return items, "", nil
+		}
+		if e.node == nil {
+			if hasPos && (key < pos.key ||
+				key == pos.key && skipped < pos.skip) {
+				if key == pos.key {
+					skipped++
+				}
+				continue
+			}
+			if len(items) == limit {
+				return items, order.encodeToken(last), nil
+			}
+			items = append(items,
+				Item[N, T]{e.rect.min, e.rect.max, e.data})
+			if len(items) > 1 && key == last.key {
+				last.skip++
+			} else if hasPos && key == pos.key {
+				last = pageToken{key, pos.skip + 1}
+			} else {
+				last = pageToken{key, 1}
+			}
+			continue
+		}
+		n := e.node
+		ordered := n.ordered()
+		leaf := n.leaf()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if ordered && r.min[0] > target.max[0] {
+				// the remaining rects are all further to the right
+				break
+			}
+			if !target.intersects(&r) ||
+				hasPos && order.maxKey(&r) < pos.key {
+				continue
+			}
+			if leaf {
+				q.push(order.key(&r),
+					topkElem[N, T]{rect: r, data: n.items()[i]})
+			} else {
+				q.push(order.key(&r),
+					topkElem[N, T]{rect: r, node: n.children()[i]})
+			}
+		}
+	}
+}
+
+// SearchPage returns a page of at most limit items that intersect the
+// provided rectangle, in the provided order, along with the token for the
+// next page. See RTreeGN.SearchPage.
+func (tr *RTreeG[T]) SearchPage(min, max [2]float64, order Order[float64, T],
+	token string, limit int,
+) (items []Item[float64, T], next string, err error) {
+	return tr.base.SearchPage(min, max, order, token, limit)
+}
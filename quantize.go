@@ -0,0 +1,120 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"errors"
+	"math"
+)
+
+var errQuantizeRange = errors.New("rtree: coordinate out of quantized range")
+
+// QuantizedRTreeG is an R-tree for float64 coordinates that are stored as
+// int32 multiples of a fixed precision, which halves the memory that is used
+// by the rects of the nodes.
+//
+// The min of every rect is rounded down and the max is rounded up, both for
+// the items and for the searches, so a search never misses an item that it
+// would have found with the exact coordinates. It may however also find
+// items that are less than the precision away from the searched rect, and the
+// rects that are sent to iter are the rounded ones.
+type QuantizedRTreeG[T any] struct {
+	scale float64
+	base  RTreeGN[int32, T]
+}
+
+// NewQuantizedRTreeG returns a new tree that stores coordinates with the
+// provided precision, such as 1e-7 for degrees, which is about a centimeter.
+// The coordinates of the items must be within the range of an int32 times
+// the precision, such as ±214 for 1e-7.
+func NewQuantizedRTreeG[T any](precision float64) *QuantizedRTreeG[T] {
+	if !(precision > 0) {
+		panic("rtree: precision must be greater than zero")
+	}
+	return &QuantizedRTreeG[T]{scale: 1 / precision}
+}
+
+// Precision returns the precision of the coordinates.
+func (tr *QuantizedRTreeG[T]) Precision() float64 {
+	return 1 / tr.scale
+}
+
+// quantize returns the rounded rect, or false if it's outside of the range of
+// an int32. The min is rounded down and the max is rounded up.
+func (tr *QuantizedRTreeG[T]) quantize(min, max [2]float64,
+) (r rect[int32], ok bool) {
+	ok = true
+	for axis := 0; axis < 2; axis++ {
+		lo := math.Floor(min[axis] * tr.scale)
+		hi := math.Ceil(max[axis] * tr.scale)
+		if !(lo >= math.MinInt32 && hi <= math.MaxInt32) {
+			ok = false
+		}
+		r.min[axis] = clampInt32(lo)
+		r.max[axis] = clampInt32(hi)
+	}
+	return r, ok
+}
+
+func clampInt32(v float64) int32 {
+	return int32(math.Min(math.Max(v, math.MinInt32), math.MaxInt32))
+}
+
+// dequantize returns the float64 coordinates of the rounded point.
+func (tr *QuantizedRTreeG[T]) dequantize(p [2]int32) [2]float64 {
+	return [2]float64{float64(p[0]) / tr.scale, float64(p[1]) / tr.scale}
+}
+
+// Insert data into tree.
+// Panics if the rect is outside of the range of the precision.
+func (tr *QuantizedRTreeG[T]) Insert(min, max [2]float64, data T) {
+	r, ok := tr.quantize(min, max)
+	if !ok {
+		panic(errQuantizeRange)
+	}
+	tr.base.Insert(r.min, r.max, data)
+}
+
+// Delete data from tree.
+func (tr *QuantizedRTreeG[T]) Delete(min, max [2]float64, data T) {
+	r, ok := tr.quantize(min, max)
+	if !ok {
+		return
+	}
+	tr.base.Delete(r.min, r.max, data)
+}
+
+// Len returns the number of items in tree.
+func (tr *QuantizedRTreeG[T]) Len() int {
+	return tr.base.Len()
+}
+
+// Bounds returns the rounded minimum bounding rect of all items in the tree.
+func (tr *QuantizedRTreeG[T]) Bounds() (min, max [2]float64) {
+	qmin, qmax := tr.base.Bounds()
+	return tr.dequantize(qmin), tr.dequantize(qmax)
+}
+
+// Search for items in tree that intersect the provided rectangle, including
+// items that are less than the precision away from it.
+func (tr *QuantizedRTreeG[T]) Search(min, max [2]float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	// Coordinates outside of the range are clamped, which never excludes
+	// any item because all items are within the range.
+	r, _ := tr.quantize(min, max)
+	tr.base.Search(r.min, r.max, func(min, max [2]int32, data T) bool {
+		return iter(tr.dequantize(min), tr.dequantize(max), data)
+	})
+}
+
+// Scan all items in the tree.
+func (tr *QuantizedRTreeG[T]) Scan(
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.Scan(func(min, max [2]int32, data T) bool {
+		return iter(tr.dequantize(min), tr.dequantize(max), data)
+	})
+}
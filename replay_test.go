@@ -0,0 +1,111 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"testing"
+)
+
+func encodeTestOp(op Op, min, max [2]float64, data int) []byte {
+	rec := []byte{byte(op)}
+	for _, v := range [4]float64{min[0], min[1], max[0], max[1]} {
+		rec = binary.LittleEndian.AppendUint64(rec, math.Float64bits(v))
+	}
+	return binary.AppendVarint(rec, int64(data))
+}
+
+func decodeTestOp(rec []byte) (op Op, min, max [2]float64, data int,
+	err error,
+) {
+	if len(rec) < 33 {
+		return 0, min, max, 0, errors.New("short record")
+	}
+	var vals [4]float64
+	for i := range vals {
+		vals[i] = math.Float64frombits(
+			binary.LittleEndian.Uint64(rec[1+i*8:]))
+	}
+	v, _ := binary.Varint(rec[33:])
+	return Op(rec[0]), [2]float64{vals[0], vals[1]},
+		[2]float64{vals[2], vals[3]}, int(v), nil
+}
+
+func TestReplay(t *testing.T) {
+	var wal bytes.Buffer
+	var tr RTreeG[int]
+	tr.SetLogger(func(op Op, min, max [2]float64, data int) {
+		if err := WriteLogRecord(&wal, encodeTestOp(op, min, max,
+			data)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	rects := make([]rect[float64], 5000)
+	for i := range rects {
+		rects[i] = randRect('r')
+		tr.Insert(rects[i].min, rects[i].max, i)
+	}
+	for i := 0; i < len(rects); i += 3 {
+		tr.Delete(rects[i].min, rects[i].max, i)
+	}
+	for i := 1; i < len(rects); i += 3 {
+		r := randRect('r')
+		tr.Replace(rects[i].min, rects[i].max, i, r.min, r.max, i)
+	}
+	items := func(tr *RTreeG[int]) []int {
+		var res []int
+		tr.Scan(func(min, max [2]float64, data int) bool {
+			res = append(res, data)
+			return true
+		})
+		slices.Sort(res)
+		return res
+	}
+
+	var tr2 RTreeG[int]
+	if err := tr2.Replay(bytes.NewReader(wal.Bytes()),
+		decodeTestOp); err != nil {
+		t.Fatal(err)
+	}
+	if err := rSane(&tr2); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(items(&tr), items(&tr2)) {
+		t.Fatal("mismatch")
+	}
+
+	// an incomplete last record is reported, but the others are applied
+	tr.Clear()
+	tr.Insert(rects[0].min, rects[0].max, -1)
+	var tr3 RTreeG[int]
+	err := tr3.Replay(bytes.NewReader(wal.Bytes()[:wal.Len()-1]),
+		decodeTestOp)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+	if tr3.Len() != 0 {
+		t.Fatalf("expected %d, got %d", 0, tr3.Len())
+	}
+
+	// decode errors stop the replay
+	var bad bytes.Buffer
+	WriteLogRecord(&bad, encodeTestOp(OpInsert, rects[0].min, rects[0].max,
+		0))
+	WriteLogRecord(&bad, []byte{1, 2, 3})
+	WriteLogRecord(&bad, encodeTestOp(OpInsert, rects[1].min, rects[1].max,
+		1))
+	var tr4 RTreeG[int]
+	if err := tr4.Replay(&bad, decodeTestOp); err == nil {
+		t.Fatal("expected an error")
+	}
+	if !slices.Equal(items(&tr4), []int{0}) {
+		t.Fatalf("expected %v, got %v", []int{0}, items(&tr4))
+	}
+}
@@ -0,0 +1,842 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package rtreef64 provides an R-tree with float64 coordinates and
+// interface{} data that does not use generics.
+//
+// It's a hand-specialized copy of the core of the rtree package, using the
+// same node layout and the same insert, split, and delete algorithms, and it
+// has the same API as rtree.RTree. Without the numeric type constraint the
+// compiler is able to inline more of the hot paths, which makes it somewhat
+// faster for applications that only need float64 coordinates and the basic
+// operations. Everything else, such as bulk loading, aggregates, and the
+// specialized queries, is only available in the rtree package.
+//
+// Changes to the insert, split, search, or delete algorithms in the rtree
+// package have to be ported here by hand. TestSameAsRTree checks that both
+// trees give the same results, and the benchmarks compare their speed.
+package rtreef64
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// SAFETY: The unsafe package is used in the same way as the rtree package.
+// All references to nodes are to `*node`, which is the header of either a
+// `*leafNode` or a `*branchNode`, as indicated by its kind. The only way to
+// create a node is with `RTree.newNode`.
+
+const maxEntries = 64
+
+// copy-on-write atomic incrementer
+var gcow uint64
+
+// RTree is an R-tree with float64 coordinates and interface{} data.
+// The zero value is an empty tree that is ready to use.
+type RTree struct {
+	icow  uint64
+	count int
+	rect  rect
+	root  *node
+	qpool *sync.Pool
+}
+
+type rect struct {
+	min [2]float64
+	max [2]float64
+}
+
+func (r *rect) expand(b *rect) {
+	if b.min[0] < r.min[0] {
+		r.min[0] = b.min[0]
+	}
+	if b.max[0] > r.max[0] {
+		r.max[0] = b.max[0]
+	}
+	if b.min[1] < r.min[1] {
+		r.min[1] = b.min[1]
+	}
+	if b.max[1] > r.max[1] {
+		r.max[1] = b.max[1]
+	}
+}
+
+type kind int8
+
+const (
+	none kind = iota
+	leaf
+	branch
+)
+
+type node struct {
+	icow  uint64
+	kind  kind
+	count int16
+	rects [maxEntries]rect
+}
+
+func (n *node) leaf() bool {
+	return n.kind == leaf
+}
+
+type leafNode struct {
+	node
+	items [maxEntries]interface{}
+}
+
+type branchNode struct {
+	node
+	children [maxEntries]*node
+}
+
+func (n *node) children() []*node {
+	if n.kind != branch {
+		// not a branch
+		return nil
+	}
+	return (*branchNode)(unsafe.Pointer(n)).children[:]
+}
+
+func (n *node) items() []interface{} {
+	if n.kind != leaf {
+		// not a leaf
+		return nil
+	}
+	return (*leafNode)(unsafe.Pointer(n)).items[:]
+}
+
+func (tr *RTree) newNode(isleaf bool) *node {
+	if isleaf {
+		n := &leafNode{node: node{icow: tr.icow, kind: leaf}}
+		return (*node)(unsafe.Pointer(n))
+	}
+	n := &branchNode{node: node{icow: tr.icow, kind: branch}}
+	return (*node)(unsafe.Pointer(n))
+}
+
+func (n *node) rect() rect {
+	rect := n.rects[0]
+	for i := 1; i < int(n.count); i++ {
+		rect.expand(&n.rects[i])
+	}
+	return rect
+}
+
+// Insert data into tree
+func (tr *RTree) Insert(min, max [2]float64, data interface{}) {
+	ir := rect{min, max}
+	if tr.root == nil {
+		if tr.qpool == nil {
+			tr.qpool = &sync.Pool{New: func() any { return &queue{} }}
+		}
+		tr.root = tr.newNode(true)
+		tr.rect = ir
+	}
+	tr.cow(&tr.root)
+	split, grown := tr.nodeInsert(&tr.rect, tr.root, &ir, data)
+	if split {
+		left := tr.root
+		right := tr.splitNode(tr.rect, left)
+		tr.root = tr.newNode(false)
+		tr.root.rects[0] = left.rect()
+		tr.root.rects[1] = right.rect()
+		tr.root.children()[0] = left
+		tr.root.children()[1] = right
+		tr.root.count = 2
+		tr.Insert(min, max, data)
+		tr.root.sort()
+		return
+	}
+	if grown {
+		tr.rect.expand(&ir)
+		if !tr.root.leaf() {
+			tr.root.sort()
+		}
+	}
+	tr.count++
+}
+
+func (n *node) orderToRight(idx int) int {
+	for idx < int(n.count)-1 && n.rects[idx+1].min[0] < n.rects[idx].min[0] {
+		n.swap(idx+1, idx)
+		idx++
+	}
+	return idx
+}
+
+func (n *node) orderToLeft(idx int) int {
+	for idx > 0 && n.rects[idx].min[0] < n.rects[idx-1].min[0] {
+		n.swap(idx, idx-1)
+		idx--
+	}
+	return idx
+}
+
+//go:noinline
+func (tr *RTree) copy(n *node) *node {
+	n2 := tr.newNode(n.leaf())
+	*n2 = *n
+	n2.icow = tr.icow
+	if n2.leaf() {
+		copy(n2.items()[:n.count], n.items()[:n.count])
+	} else {
+		copy(n2.children()[:n.count], n.children()[:n.count])
+	}
+	return n2
+}
+
+// cow ensures the provided node is not being shared with other R-trees.
+// Performs a copy-on-write, if needed.
+func (tr *RTree) cow(n **node) {
+	if (*n).icow != tr.icow {
+		*n = tr.copy(*n)
+	}
+}
+
+func (n *node) rsearch(key float64) int {
+	rects := n.rects[:n.count]
+	for i := 0; i < len(rects); i++ {
+		if !(n.rects[i].min[0] < key) {
+			return i
+		}
+	}
+	return int(n.count)
+}
+
+func (tr *RTree) nodeInsert(nr *rect, n *node, ir *rect, data interface{},
+) (split, grown bool) {
+	if n.leaf() {
+		if n.count == maxEntries {
+			return true, false
+		}
+		items := n.items()
+		index := n.rsearch(ir.min[0])
+		copy(n.rects[index+1:int(n.count)+1], n.rects[index:int(n.count)])
+		copy(items[index+1:int(n.count)+1], items[index:int(n.count)])
+		n.rects[index] = *ir
+		items[index] = data
+		n.count++
+		grown = !nr.contains(ir)
+		return false, grown
+	}
+
+	// choose a subtree
+	rects := n.rects[:n.count]
+	index := -1
+	var narea float64
+	// take a quick look for any nodes that contain the rect
+	for i := 0; i < len(rects); i++ {
+		if rects[i].contains(ir) {
+			area := rects[i].area()
+			if index == -1 || area < narea {
+				index = i
+				narea = area
+			}
+		}
+	}
+	if index == -1 {
+		index = n.chooseLeastEnlargement(ir)
+	}
+
+	children := n.children()
+	tr.cow(&children[index])
+	split, grown = tr.nodeInsert(&n.rects[index], children[index], ir, data)
+	if split {
+		if n.count == maxEntries {
+			return true, false
+		}
+		// split the child node
+		left := children[index]
+		right := tr.splitNode(n.rects[index], left)
+		n.rects[index] = left.rect()
+		copy(n.rects[index+2:int(n.count)+1], n.rects[index+1:int(n.count)])
+		copy(children[index+2:int(n.count)+1], children[index+1:int(n.count)])
+		n.rects[index+1] = right.rect()
+		children[index+1] = right
+		n.count++
+		if n.rects[index].min[0] > n.rects[index+1].min[0] {
+			n.swap(index+1, index)
+		}
+		index++
+		_ = n.orderToRight(index)
+		return tr.nodeInsert(nr, n, ir, data)
+	}
+	if grown {
+		// The child rectangle must expand to accomadate the new item.
+		n.rects[index].expand(ir)
+		n.orderToLeft(index)
+		grown = !nr.contains(ir)
+	}
+	return false, grown
+}
+
+func (r *rect) area() float64 {
+	return (r.max[0] - r.min[0]) * (r.max[1] - r.min[1])
+}
+
+// contains return struct when b is fully contained inside of n
+func (r *rect) contains(b *rect) bool {
+	if b.min[0] < r.min[0] || b.max[0] > r.max[0] {
+		return false
+	}
+	if b.min[1] < r.min[1] || b.max[1] > r.max[1] {
+		return false
+	}
+	return true
+}
+
+// intersects returns true if both rects intersect each other.
+func (r *rect) intersects(b *rect) bool {
+	if b.min[0] > r.max[0] || b.max[0] < r.min[0] {
+		return false
+	}
+	if b.min[1] > r.max[1] || b.max[1] < r.min[1] {
+		return false
+	}
+	return true
+}
+
+func (n *node) chooseLeastEnlargement(ir *rect) (index int) {
+	rects := n.rects[:int(n.count)]
+	var j = -1
+	var jenlargement float64
+	var jarea float64
+	for i := 0; i < len(rects); i++ {
+		// calculate the enlarged area
+		uarea := rects[i].unionedArea(ir)
+		area := rects[i].area()
+		enlargement := uarea - area
+		if j == -1 || enlargement < jenlargement ||
+			(!(enlargement > jenlargement) && area < jarea) {
+			j, jenlargement, jarea = i, enlargement, area
+		}
+	}
+	return j
+}
+
+func fmin(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func fmax(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// unionedArea returns the area of two rects expanded
+func (r *rect) unionedArea(b *rect) float64 {
+	return (fmax(r.max[0], b.max[0]) - fmin(r.min[0], b.min[0])) *
+		(fmax(r.max[1], b.max[1]) - fmin(r.min[1], b.min[1]))
+}
+
+func (r rect) largestAxis() (axis int) {
+	if r.max[1]-r.min[1] > r.max[0]-r.min[0] {
+		return 1
+	}
+	return 0
+}
+
+func (tr *RTree) splitNode(r rect, left *node) (right *node) {
+	axis := r.largestAxis()
+	right = tr.newNode(left.leaf())
+	for i := 0; i < int(left.count); i++ {
+		minDist := left.rects[i].min[axis] - r.min[axis]
+		maxDist := r.max[axis] - left.rects[i].max[axis]
+		if minDist < maxDist {
+			// stay left
+		} else {
+			// move to right
+			moveRectAtIndexInto(left, i, right)
+			i--
+		}
+	}
+	// Make sure that both left and right nodes have at least
+	// two by moving items into underflowed nodes.
+	if left.count < 2 {
+		// reverse sort by min axis
+		right.sortByAxis(axis, true, false)
+		for left.count < 2 {
+			moveRectAtIndexInto(right, int(right.count)-1, left)
+		}
+	} else if right.count < 2 {
+		// reverse sort by max axis
+		left.sortByAxis(axis, true, true)
+		for right.count < 2 {
+			moveRectAtIndexInto(left, int(left.count)-1, right)
+		}
+	}
+	// It's not uncommon that the nodes to be already ordered.
+	if !right.issorted() {
+		right.sort()
+	}
+	if !left.issorted() {
+		left.sort()
+	}
+	return right
+}
+
+func moveRectAtIndexInto(from *node, index int, into *node) {
+	into.rects[into.count] = from.rects[index]
+	from.rects[index] = from.rects[from.count-1]
+	if from.leaf() {
+		into.items()[into.count] = from.items()[index]
+		from.items()[index] = from.items()[from.count-1]
+		from.items()[from.count-1] = nil
+	} else {
+		into.children()[into.count] = from.children()[index]
+		from.children()[index] = from.children()[from.count-1]
+		from.children()[from.count-1] = nil
+	}
+	from.count--
+	into.count++
+}
+
+func (n *node) search(target rect,
+	iter func(min, max [2]float64, data interface{}) bool,
+) bool {
+	rects := n.rects[:n.count]
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < len(rects); i++ {
+			if rects[i].intersects(&target) {
+				if !iter(rects[i].min, rects[i].max, items[i]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < len(rects); i++ {
+		if target.intersects(&rects[i]) {
+			if !children[i].search(target, iter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Len returns the number of items in tree
+func (tr *RTree) Len() int {
+	return tr.count
+}
+
+// Search for items in tree that intersect the provided rectangle
+func (tr *RTree) Search(min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool,
+) {
+	target := rect{min, max}
+	if tr.root == nil {
+		return
+	}
+	if target.intersects(&tr.rect) {
+		tr.root.search(target, iter)
+	}
+}
+
+// Scan all items in the tree
+func (tr *RTree) Scan(iter func(min, max [2]float64, data interface{}) bool) {
+	if tr.root != nil {
+		tr.root.scan(iter)
+	}
+}
+
+func (n *node) scan(iter func(min, max [2]float64, data interface{}) bool,
+) bool {
+	if n.leaf() {
+		for i := 0; i < int(n.count); i++ {
+			if !iter(n.rects[i].min, n.rects[i].max, n.items()[i]) {
+				return false
+			}
+		}
+	} else {
+		for i := 0; i < int(n.count); i++ {
+			if !n.children()[i].scan(iter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Copy the tree.
+// This is a copy-on-write operation and is very fast because it only performs
+// a shadowed copy.
+func (tr *RTree) Copy() *RTree {
+	tr2 := new(RTree)
+	*tr2 = *tr
+	tr.icow = atomic.AddUint64(&gcow, 1)
+	tr2.icow = atomic.AddUint64(&gcow, 1)
+	return tr2
+}
+
+// swap two rectanlges
+func (n *node) swap(i, j int) {
+	n.rects[i], n.rects[j] = n.rects[j], n.rects[i]
+	if n.leaf() {
+		n.items()[i], n.items()[j] = n.items()[j], n.items()[i]
+	} else {
+		n.children()[i], n.children()[j] = n.children()[j], n.children()[i]
+	}
+}
+
+func (n *node) sortByAxis(axis int, rev, max bool) {
+	n.qsort(0, int(n.count), axis, rev, max)
+}
+
+func (n *node) sort() {
+	n.qsort(0, int(n.count), 0, false, false)
+}
+
+func (n *node) issorted() bool {
+	rects := n.rects[:n.count]
+	for i := 1; i < len(rects); i++ {
+		if rects[i].min[0] < rects[i-1].min[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *node) qsort(s, e int, axis int, rev, max bool) {
+	nrects := e - s
+	if nrects < 2 {
+		return
+	}
+	left, right := 0, nrects-1
+	pivot := nrects / 2 // rand and mod not worth it
+	n.swap(s+pivot, s+right)
+	rects := n.rects[s:e]
+	if !rev {
+		if !max {
+			for i := 0; i < len(rects); i++ {
+				if rects[i].min[axis] < rects[right].min[axis] {
+					n.swap(s+i, s+left)
+					left++
+				}
+			}
+		} else {
+			for i := 0; i < len(rects); i++ {
+				if rects[i].max[axis] < rects[right].max[axis] {
+					n.swap(s+i, s+left)
+					left++
+				}
+			}
+		}
+	} else {
+		if !max {
+			for i := 0; i < len(rects); i++ {
+				if rects[right].min[axis] < rects[i].min[axis] {
+					n.swap(s+i, s+left)
+					left++
+				}
+			}
+		} else {
+			for i := 0; i < len(rects); i++ {
+				if rects[right].max[axis] < rects[i].max[axis] {
+					n.swap(s+i, s+left)
+					left++
+				}
+			}
+		}
+	}
+	n.swap(s+left, s+right)
+	n.qsort(s, s+left, axis, rev, max)
+	n.qsort(s+left+1, e, axis, rev, max)
+}
+
+// Delete data from tree
+func (tr *RTree) Delete(min, max [2]float64, data interface{}) {
+	tr.delete(min, max, data)
+}
+
+func (tr *RTree) delete(min, max [2]float64, data interface{}) bool {
+	ir := rect{min, max}
+	if tr.root == nil || !tr.rect.contains(&ir) {
+		return false
+	}
+	var reinsert []*node
+	tr.cow(&tr.root)
+	removed, _ := tr.nodeDelete(&tr.rect, tr.root, &ir, data, &reinsert)
+	if !removed {
+		return false
+	}
+	tr.count--
+	for _, n := range reinsert {
+		tr.count -= n.deepCount()
+	}
+	if tr.count == 0 {
+		tr.root = nil
+		tr.rect = rect{}
+	} else {
+		for !tr.root.leaf() && tr.root.count == 1 {
+			tr.root = tr.root.children()[0]
+		}
+	}
+	for i := range reinsert {
+		tr.nodeReinsert(reinsert[i])
+	}
+	return true
+}
+
+func (tr *RTree) nodeDelete(nr *rect, n *node, ir *rect, data interface{},
+	reinsert *[]*node,
+) (removed, shrunk bool) {
+	rects := n.rects[:n.count]
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < len(rects); i++ {
+			if !ir.contains(&rects[i]) || items[i] != data {
+				continue
+			}
+			// found the target item to delete
+			dr := rects[i]
+			copy(n.rects[i:n.count], n.rects[i+1:n.count])
+			copy(items[i:n.count], items[i+1:n.count])
+			items[len(rects)-1] = nil
+			n.count--
+			shrunk = dr.onedge(nr)
+			if shrunk {
+				*nr = n.rect()
+			}
+			return true, shrunk
+		}
+		return false, false
+	}
+	children := n.children()
+	for i := 0; i < len(rects); i++ {
+		if !rects[i].contains(ir) {
+			continue
+		}
+		crect := rects[i]
+		tr.cow(&children[i])
+		removed, shrunk = tr.nodeDelete(&rects[i], children[i], ir, data,
+			reinsert)
+		if !removed {
+			continue
+		}
+		if children[i].count == 0 {
+			*reinsert = append(*reinsert, children[i])
+			copy(n.rects[i:n.count], n.rects[i+1:n.count])
+			copy(children[i:n.count], children[i+1:n.count])
+			children[n.count-1] = nil
+			n.count--
+			*nr = n.rect()
+			return true, true
+		}
+		if shrunk {
+			shrunk = !rects[i].equals(&crect)
+			if shrunk {
+				*nr = n.rect()
+			}
+			_ = n.orderToRight(i)
+		}
+		return true, shrunk
+	}
+	return false, false
+}
+
+func (r *rect) equals(b *rect) bool {
+	return !(r.min[0] < b.min[0] || r.min[0] > b.min[0] ||
+		r.min[1] < b.min[1] || r.min[1] > b.min[1] ||
+		r.max[0] < b.max[0] || r.max[0] > b.max[0] ||
+		r.max[1] < b.max[1] || r.max[1] > b.max[1])
+}
+
+func (n *node) deepCount() int {
+	if n.leaf() {
+		return int(n.count)
+	}
+	var count int
+	children := n.children()[:n.count]
+	for i := 0; i < len(children); i++ {
+		count += children[i].deepCount()
+	}
+	return count
+}
+
+func (tr *RTree) nodeReinsert(n *node) {
+	if n.leaf() {
+		rects := n.rects[:n.count]
+		items := n.items()[:n.count]
+		for i := range rects {
+			tr.Insert(rects[i].min, rects[i].max, items[i])
+		}
+	} else {
+		children := n.children()[:n.count]
+		for i := 0; i < len(children); i++ {
+			tr.nodeReinsert(children[i])
+		}
+	}
+}
+
+// onedge returns true when r is on the edge of b
+func (r *rect) onedge(b *rect) bool {
+	return !(r.min[0] > b.min[0] && r.min[1] > b.min[1] &&
+		r.max[0] < b.max[0] && r.max[1] < b.max[1])
+}
+
+// Replace an item.
+// If the old item does not exist then the new item is not inserted.
+func (tr *RTree) Replace(
+	oldMin, oldMax [2]float64, oldData interface{},
+	newMin, newMax [2]float64, newData interface{},
+) {
+	if tr.delete(oldMin, oldMax, oldData) {
+		tr.Insert(newMin, newMax, newData)
+	}
+}
+
+// Bounds returns the minimum bounding rect
+func (tr *RTree) Bounds() (min, max [2]float64) {
+	return tr.rect.min, tr.rect.max
+}
+
+// Nearby performs a kNN-type operation on the index.
+// It's expected that the caller provides its own the `dist` function, which
+// is used to calculate a distance to rectangles and data.
+// The `iter` function will return all items from the smallest distance to the
+// largest distance.
+//
+// BoxDist is included with this package for simple box-distance
+// calculations.
+func (tr *RTree) Nearby(
+	dist func(min, max [2]float64, data interface{}, item bool) float64,
+	iter func(min, max [2]float64, data interface{}, dist float64) bool,
+) {
+	if tr.root == nil {
+		return
+	}
+	q := tr.qpool.Get().(*queue)
+	defer func() {
+		clear(*q)
+		*q = (*q)[:0]
+		tr.qpool.Put(q)
+	}()
+	q.push(qnode{rect: tr.rect, node: tr.root})
+	for {
+		qn, ok := q.pop()
+		if !ok {
+			return
+		}
+		if qn.node == nil {
+			if !iter(qn.rect.min, qn.rect.max, qn.data, qn.dist) {
+				return
+			}
+			continue
+		}
+		rects := qn.node.rects[:qn.node.count]
+		if qn.node.leaf() {
+			items := qn.node.items()[:qn.node.count]
+			for i := 0; i < len(items); i++ {
+				q.push(qnode{
+					dist: dist(rects[i].min, rects[i].max, items[i], true),
+					rect: rects[i],
+					data: items[i],
+				})
+			}
+		} else {
+			children := qn.node.children()[:qn.node.count]
+			for i := 0; i < len(children); i++ {
+				q.push(qnode{
+					dist: dist(rects[i].min, rects[i].max, nil, false),
+					rect: rects[i],
+					node: children[i],
+				})
+			}
+		}
+	}
+}
+
+type qnode struct {
+	dist float64     // distance to
+	rect rect        // item or node rect
+	data interface{} // item data (or nil for node)
+	node *node       // node (or nil for leaf data)
+}
+
+type queue []qnode
+
+func (q *queue) push(node qnode) {
+	*q = append(*q, node)
+	nodes := *q
+	i := len(nodes) - 1
+	parent := (i - 1) / 2
+	for ; i != 0 && nodes[parent].dist > nodes[i].dist; parent = (i - 1) / 2 {
+		nodes[parent], nodes[i] = nodes[i], nodes[parent]
+		i = parent
+	}
+}
+
+func (q *queue) pop() (qnode, bool) {
+	nodes := *q
+	if len(nodes) == 0 {
+		return qnode{}, false
+	}
+	var n qnode
+	n, nodes[0] = nodes[0], nodes[len(*q)-1]
+	nodes = nodes[:len(nodes)-1]
+	*q = nodes
+	i := 0
+	for {
+		smallest := i
+		left := i*2 + 1
+		right := i*2 + 2
+		if left < len(nodes) && nodes[left].dist <= nodes[smallest].dist {
+			smallest = left
+		}
+		if right < len(nodes) && nodes[right].dist <= nodes[smallest].dist {
+			smallest = right
+		}
+		if smallest == i {
+			break
+		}
+		nodes[smallest], nodes[i] = nodes[i], nodes[smallest]
+		i = smallest
+	}
+	return n, true
+}
+
+// BoxDist performs simple box-distance algorithm on rectangles.
+// This is the default algorithm for Nearby.
+func BoxDist(targetMin, targetMax [2]float64,
+	itemDist func(min, max [2]float64, data interface{}) float64,
+) (dist func(min, max [2]float64, data interface{}, item bool) float64) {
+	targ := rect{targetMin, targetMax}
+	return func(min, max [2]float64, data interface{}, item bool) float64 {
+		if item && itemDist != nil {
+			return itemDist(min, max, data)
+		}
+		return targ.boxDist(&rect{min, max})
+	}
+}
+
+func (r *rect) boxDist(b *rect) float64 {
+	var dist float64
+	squared := fmax(r.min[0], b.min[0]) - fmin(r.max[0], b.max[0])
+	if squared > 0 {
+		dist += squared * squared
+	}
+	squared = fmax(r.min[1], b.min[1]) - fmin(r.max[1], b.max[1])
+	if squared > 0 {
+		dist += squared * squared
+	}
+	return dist
+}
+
+// Clear will delete all items.
+func (tr *RTree) Clear() {
+	tr.count = 0
+	tr.rect = rect{}
+	tr.root = nil
+}
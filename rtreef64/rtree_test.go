@@ -0,0 +1,174 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtreef64
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/buivuanh/rtree"
+)
+
+func randRect() (min, max [2]float64) {
+	min[0] = rand.Float64()*360 - 180
+	min[1] = rand.Float64()*180 - 90
+	max[0] = min[0] + rand.Float64()*2
+	max[1] = min[1] + rand.Float64()*2
+	return min, max
+}
+
+func collect(search func(iter func(min, max [2]float64, data interface{}) bool),
+) []int {
+	var res []int
+	search(func(min, max [2]float64, data interface{}) bool {
+		res = append(res, data.(int))
+		return true
+	})
+	slices.Sort(res)
+	return res
+}
+
+// TestSameAsRTree checks that the tree behaves exactly like rtree.RTree.
+func TestSameAsRTree(t *testing.T) {
+	var tr RTree
+	var base rtree.RTree
+	N := 20000
+	mins := make([][2]float64, N)
+	maxs := make([][2]float64, N)
+	for i := 0; i < N; i++ {
+		mins[i], maxs[i] = randRect()
+		tr.Insert(mins[i], maxs[i], i)
+		base.Insert(mins[i], maxs[i], i)
+	}
+	check := func() {
+		t.Helper()
+		if tr.Len() != base.Len() {
+			t.Fatalf("expected %d, got %d", base.Len(), tr.Len())
+		}
+		min, max := tr.Bounds()
+		bmin, bmax := base.Bounds()
+		if min != bmin || max != bmax {
+			t.Fatal("bounds mismatch")
+		}
+		if !slices.Equal(collect(tr.Scan), collect(base.Scan)) {
+			t.Fatal("scan mismatch")
+		}
+		for j := 0; j < 10; j++ {
+			min, max := randRect()
+			max[0] += 20
+			max[1] += 20
+			search := func(iter func(min, max [2]float64,
+				data interface{}) bool) {
+				tr.Search(min, max, iter)
+			}
+			bsearch := func(iter func(min, max [2]float64,
+				data interface{}) bool) {
+				base.Search(min, max, iter)
+			}
+			if !slices.Equal(collect(search), collect(bsearch)) {
+				t.Fatal("search mismatch")
+			}
+		}
+	}
+	check()
+	snap := tr.Copy()
+	for _, i := range rand.Perm(N)[:N/2] {
+		tr.Delete(mins[i], maxs[i], i)
+		base.Delete(mins[i], maxs[i], i)
+	}
+	check()
+	for i := 0; i < N; i += 7 {
+		min, max := randRect()
+		tr.Replace(mins[i], maxs[i], i, min, max, i)
+		base.Replace(mins[i], maxs[i], i, min, max, i)
+	}
+	check()
+	if snap.Len() != N || len(collect(snap.Scan)) != N {
+		t.Fatal("expected the copy to be unchanged")
+	}
+
+	// nearest items
+	p := [2]float64{10, 20}
+	var dists, bdists []float64
+	tr.Nearby(BoxDist(p, p, nil), func(min, max [2]float64,
+		data interface{}, dist float64) bool {
+		dists = append(dists, dist)
+		return len(dists) < 100
+	})
+	base.Nearby(rtree.BoxDist[float64, interface{}](p, p, nil),
+		func(min, max [2]float64, data interface{}, dist float64) bool {
+			bdists = append(bdists, dist)
+			return len(bdists) < 100
+		},
+	)
+	if !slices.Equal(dists, bdists) {
+		t.Fatal("nearby mismatch")
+	}
+
+	for i := 0; i < N; i++ {
+		tr.Delete(mins[i], maxs[i], i)
+	}
+	tr.Clear()
+	if tr.Len() != 0 || len(collect(tr.Scan)) != 0 {
+		t.Fatal("expected an empty tree")
+	}
+}
+
+func benchTrees(b *testing.B, fn func(b *testing.B, insert func(min,
+	max [2]float64, data interface{}), search func(min, max [2]float64,
+	iter func(min, max [2]float64, data interface{}) bool))) {
+	b.Run("rtreef64", func(b *testing.B) {
+		var tr RTree
+		fn(b, tr.Insert, tr.Search)
+	})
+	b.Run("rtree", func(b *testing.B) {
+		var tr rtree.RTree
+		fn(b, tr.Insert, tr.Search)
+	})
+	b.Run("rtreeg", func(b *testing.B) {
+		var tr rtree.RTreeG[interface{}]
+		fn(b, tr.Insert, tr.Search)
+	})
+}
+
+func BenchmarkInsert(b *testing.B) {
+	benchTrees(b, func(b *testing.B, insert func(min, max [2]float64,
+		data interface{}), _ func(min, max [2]float64,
+		iter func(min, max [2]float64, data interface{}) bool)) {
+		mins := make([][2]float64, b.N)
+		maxs := make([][2]float64, b.N)
+		for i := range mins {
+			mins[i], maxs[i] = randRect()
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			insert(mins[i], maxs[i], nil)
+		}
+	})
+}
+
+func BenchmarkSearch(b *testing.B) {
+	benchTrees(b, func(b *testing.B, insert func(min, max [2]float64,
+		data interface{}), search func(min, max [2]float64,
+		iter func(min, max [2]float64, data interface{}) bool)) {
+		for i := 0; i < 100000; i++ {
+			min, max := randRect()
+			insert(min, max, nil)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			min, max := randRect()
+			max[0] += 5
+			max[1] += 5
+			search(min, max, func(min, max [2]float64,
+				data interface{}) bool {
+				return true
+			})
+		}
+	})
+}
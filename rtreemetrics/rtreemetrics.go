@@ -0,0 +1,184 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package rtreemetrics wraps an rtree and publishes metrics about its
+// operations to expvar, such as the number of operations, the time spent in
+// them, the depths and nodes visited by searches, and how often nodes are
+// split or copied.
+//
+// The metrics can also be read using Each, which allows for exporting them to
+// other systems, such as a Prometheus registry using a CounterFunc for each
+// metric.
+package rtreemetrics
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/buivuanh/rtree"
+)
+
+// Names of the published metrics. Durations are the total nanoseconds spent.
+// SearchDepths and SearchNodes are the totals of the levels and the nodes
+// that were visited by searches, which divided by Searches are the averages
+// per query.
+const (
+	Inserts      = "inserts"
+	InsertNanos  = "insert_ns"
+	Deletes      = "deletes"
+	DeleteNanos  = "delete_ns"
+	Replaces     = "replaces"
+	ReplaceNanos = "replace_ns"
+	Searches     = "searches"
+	SearchNanos  = "search_ns"
+	SearchItems  = "search_items"
+	SearchDepths = "search_depths"
+	SearchNodes  = "search_nodes"
+	Nearbys      = "nearbys"
+	NearbyNanos  = "nearby_ns"
+	NearbyItems  = "nearby_items"
+	Scans        = "scans"
+	ScanNanos    = "scan_ns"
+	ScanItems    = "scan_items"
+	Splits       = "splits"
+	Reinserts    = "reinserts"
+	Copies       = "copies"
+	NodeAllocs   = "node_allocs"
+)
+
+var names = []string{
+	Inserts, InsertNanos, Deletes, DeleteNanos, Replaces, ReplaceNanos,
+	Searches, SearchNanos, SearchItems, SearchDepths, SearchNodes, Nearbys,
+	NearbyNanos, NearbyItems, Scans, ScanNanos, ScanItems, Splits, Reinserts,
+	Copies, NodeAllocs,
+}
+
+// Tree is an rtree.RTreeG that records metrics for its operations.
+// Like the tree, it's not safe for concurrent use, but the metrics may be
+// read concurrently.
+type Tree[T any] struct {
+	tr   rtree.RTreeG[T]
+	vars *expvar.Map
+	ints map[string]*expvar.Int
+}
+
+// New returns a new tree that publishes its metrics as an expvar.Map with the
+// provided name, which must be unique. Use an empty name to not publish the
+// metrics.
+func New[T any](name string) *Tree[T] {
+	t := &Tree[T]{ints: make(map[string]*expvar.Int)}
+	if name == "" {
+		t.vars = new(expvar.Map).Init()
+	} else {
+		t.vars = expvar.NewMap(name)
+	}
+	for _, name := range names {
+		v := new(expvar.Int)
+		t.ints[name] = v
+		t.vars.Set(name, v)
+	}
+	t.tr.SetHooks(&rtree.Hooks{
+		OnSplit:       func(bool) { t.ints[Splits].Add(1) },
+		OnReinsert:    func(n int) { t.ints[Reinserts].Add(int64(n)) },
+		OnCopyOnWrite: func(bool) { t.ints[Copies].Add(1) },
+		OnNodeAlloc:   func(bool) { t.ints[NodeAllocs].Add(1) },
+	})
+	return t
+}
+
+// Tree returns the underlying tree, which can be used for operations that
+// are not recorded. Hooks that are set on the tree replace the ones that
+// record the splits, reinserts, copies, and node allocations.
+func (t *Tree[T]) Tree() *rtree.RTreeG[T] {
+	return &t.tr
+}
+
+// Vars returns the metrics as an expvar.Map.
+func (t *Tree[T]) Vars() *expvar.Map {
+	return t.vars
+}
+
+// Each calls the function for each metric.
+func (t *Tree[T]) Each(fn func(name string, value int64)) {
+	for _, name := range names {
+		fn(name, t.ints[name].Value())
+	}
+}
+
+func (t *Tree[T]) record(count, nanos string, start time.Time) {
+	t.ints[count].Add(1)
+	t.ints[nanos].Add(int64(time.Since(start)))
+}
+
+// Insert data into tree.
+func (t *Tree[T]) Insert(min, max [2]float64, data T) {
+	defer t.record(Inserts, InsertNanos, time.Now())
+	t.tr.Insert(min, max, data)
+}
+
+// Delete data from tree.
+func (t *Tree[T]) Delete(min, max [2]float64, data T) {
+	defer t.record(Deletes, DeleteNanos, time.Now())
+	t.tr.Delete(min, max, data)
+}
+
+// Replace an item.
+// If the old item does not exist then the new item is not inserted.
+func (t *Tree[T]) Replace(
+	oldMin, oldMax [2]float64, oldData T,
+	newMin, newMax [2]float64, newData T,
+) {
+	defer t.record(Replaces, ReplaceNanos, time.Now())
+	t.tr.Replace(oldMin, oldMax, oldData, newMin, newMax, newData)
+}
+
+// Search for items in tree that intersect the provided rectangle.
+// The time that is spent in the iter function is included in the metrics.
+func (t *Tree[T]) Search(min, max [2]float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	defer t.record(Searches, SearchNanos, time.Now())
+	p := t.tr.SearchProfiled(min, max, iter)
+	var nodes int
+	for _, n := range p.Nodes {
+		nodes += n
+	}
+	t.ints[SearchItems].Add(int64(p.Items))
+	t.ints[SearchDepths].Add(int64(len(p.Nodes)))
+	t.ints[SearchNodes].Add(int64(nodes))
+}
+
+// Nearby returns items nearest to farthest, like RTreeG.Nearby.
+// The time that is spent in the iter function is included in the metrics.
+func (t *Tree[T]) Nearby(
+	dist func(min, max [2]float64, data T, item bool) float64,
+	iter func(min, max [2]float64, data T, dist float64) bool,
+) {
+	defer t.record(Nearbys, NearbyNanos, time.Now())
+	var items int64
+	t.tr.Nearby(dist,
+		func(min, max [2]float64, data T, dist float64) bool {
+			items++
+			return iter(min, max, data, dist)
+		},
+	)
+	t.ints[NearbyItems].Add(items)
+}
+
+// Scan iterates through all data in tree.
+// The time that is spent in the iter function is included in the metrics.
+func (t *Tree[T]) Scan(iter func(min, max [2]float64, data T) bool) {
+	defer t.record(Scans, ScanNanos, time.Now())
+	var items int64
+	t.tr.Scan(func(min, max [2]float64, data T) bool {
+		items++
+		return iter(min, max, data)
+	})
+	t.ints[ScanItems].Add(items)
+}
+
+// Len returns the number of items in tree.
+func (t *Tree[T]) Len() int {
+	return t.tr.Len()
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtreemetrics
+
+import (
+	"expvar"
+	"strings"
+	"testing"
+
+	"github.com/buivuanh/rtree"
+)
+
+func TestTree(t *testing.T) {
+	tr := New[int]("rtree_test")
+	for i := 0; i < 1000; i++ {
+		p := [2]float64{float64(i), float64(i)}
+		tr.Insert(p, p, i)
+	}
+	var found int
+	tr.Search([2]float64{0, 0}, [2]float64{9, 9},
+		func(min, max [2]float64, data int) bool {
+			found++
+			return true
+		},
+	)
+	tr.Delete([2]float64{0, 0}, [2]float64{0, 0}, 0)
+	if tr.Len() != 999 || found != 10 {
+		t.Fatalf("expected %d/%d, got %d/%d", 999, 10, tr.Len(), found)
+	}
+	tr.Replace([2]float64{1, 1}, [2]float64{1, 1}, 1,
+		[2]float64{0, 0}, [2]float64{0, 0}, 1)
+	tr.Nearby(rtree.BoxDist[float64, int]([2]float64{0, 0},
+		[2]float64{0, 0}, nil),
+		func(min, max [2]float64, data int, dist float64) bool {
+			return data < 4
+		},
+	)
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		return true
+	})
+	metrics := make(map[string]int64)
+	tr.Each(func(name string, value int64) {
+		metrics[name] = value
+	})
+	for name, expect := range map[string]int64{
+		Inserts: 1000, Deletes: 1, Replaces: 1, Searches: 1, SearchItems: 10,
+		Nearbys: 1, NearbyItems: 4, Scans: 1, ScanItems: 999,
+	} {
+		if metrics[name] != expect {
+			t.Fatalf("expected %s to be %d, got %d", name, expect,
+				metrics[name])
+		}
+	}
+	if metrics[Splits] == 0 || metrics[NodeAllocs] == 0 ||
+		metrics[InsertNanos] == 0 {
+		t.Fatalf("expected splits, allocs, and time, got %v", metrics)
+	}
+	if metrics[SearchDepths] < 2 ||
+		metrics[SearchNodes] < metrics[SearchDepths] {
+		t.Fatalf("expected depths and nodes, got %v", metrics)
+	}
+	if expvar.Get("rtree_test") != tr.Vars() ||
+		!strings.Contains(tr.Vars().String(), `"inserts": 1000`) {
+		t.Fatalf("unexpected vars %s", tr.Vars())
+	}
+	tr2 := New[int]("")
+	if tr2.Vars() == nil || tr2.Tree().Len() != 0 {
+		t.Fatal("expected empty tree")
+	}
+}
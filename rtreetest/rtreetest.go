@@ -0,0 +1,297 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package rtreetest provides a randomized model-based test for rtree, which
+// applies long sequences of random operations to a tree and to a simple
+// brute-force index, and checks that both always agree.
+//
+// This allows for validating a configuration of a tree, such as a custom
+// splitter or a combination of options, in the tests of another package:
+//
+//	func TestTree(t *testing.T) {
+//		rtreetest.Check(t, func() *rtree.RTreeGN[float64, int] {
+//			return rtree.New(rtree.WithSplitter(mySplitter))
+//		})
+//	}
+package rtreetest
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/buivuanh/rtree"
+)
+
+// model is the brute-force index that the tree is compared with.
+type model struct {
+	items []rtree.Item[float64, int]
+}
+
+func (m *model) delete(i int) {
+	m.items[i] = m.items[len(m.items)-1]
+	m.items = m.items[:len(m.items)-1]
+}
+
+func (m *model) clone() *model {
+	return &model{items: slices.Clone(m.items)}
+}
+
+// checker applies random operations to a tree and its model.
+type checker struct {
+	t      testing.TB
+	seed   int64
+	rng    *rand.Rand
+	tr     *rtree.RTreeGN[float64, int]
+	m      *model
+	nextID int
+	op     int
+	snaps  []snapshot
+}
+
+// snapshot is a copy of the tree, along with the model at the time of the
+// copy, which must not change when the tree is modified afterwards.
+type snapshot struct {
+	tr *rtree.RTreeGN[float64, int]
+	m  *model
+}
+
+// Check runs a randomized model-based test of the trees that are returned by
+// factory. Random inserts, deletes, replaces, bulk loads, copies and clears
+// are applied to a tree and to a brute-force index, and the results of
+// searches, scans, nearest neighbor queries, the bounds and the length of
+// the tree are compared with the index after every operation. The structure
+// of the tree is validated using SanityCheck.
+//
+// The first difference is reported using t.Fatalf, along with the seed and
+// the number of the operation. The sequences are shorter when testing.Short
+// is set.
+func Check(t testing.TB, factory func() *rtree.RTreeGN[float64, int]) {
+	t.Helper()
+	CheckSeed(t, time.Now().UnixNano(), factory)
+}
+
+// CheckSeed is like Check, but uses the provided seed for the random
+// operations, such as for reproducing a failure that was reported by Check.
+func CheckSeed(t testing.TB, seed int64,
+	factory func() *rtree.RTreeGN[float64, int],
+) {
+	t.Helper()
+	rounds, ops := 4, 4000
+	if testing.Short() {
+		rounds, ops = 2, 1000
+	}
+	c := &checker{
+		t:    t,
+		seed: seed,
+		rng:  rand.New(rand.NewSource(seed)),
+	}
+	for r := 0; r < rounds; r++ {
+		c.tr = factory()
+		c.m = new(model)
+		c.snaps = nil
+		for i := 0; i < ops; i++ {
+			c.step()
+			c.op++
+		}
+		c.verify(c.tr, c.m, true)
+		for _, s := range c.snaps {
+			c.verify(s.tr, s.m, true)
+		}
+	}
+}
+
+func (c *checker) fatalf(format string, args ...any) {
+	c.t.Helper()
+	c.t.Fatalf("rtreetest: seed %d, operation %d: %s", c.seed, c.op,
+		fmt.Sprintf(format, args...))
+}
+
+// randRect returns a random point or rectangle. Some rectangles are the same
+// as the rectangles of existing items.
+func (c *checker) randRect() (min, max [2]float64) {
+	if len(c.m.items) > 0 && c.rng.Intn(10) == 0 {
+		item := c.m.items[c.rng.Intn(len(c.m.items))]
+		return item.Min, item.Max
+	}
+	min[0] = c.rng.Float64()*2000 - 1000
+	min[1] = c.rng.Float64()*2000 - 1000
+	max = min
+	if c.rng.Intn(2) == 0 {
+		max[0] += c.rng.Float64() * 50
+		max[1] += c.rng.Float64() * 50
+	}
+	return min, max
+}
+
+func (c *checker) newItem() rtree.Item[float64, int] {
+	min, max := c.randRect()
+	c.nextID++
+	return rtree.Item[float64, int]{Min: min, Max: max, Data: c.nextID}
+}
+
+// step applies one random operation and checks the result.
+func (c *checker) step() {
+	c.t.Helper()
+	switch n := c.rng.Intn(100); {
+	case n < 40:
+		item := c.newItem()
+		c.tr.Insert(item.Min, item.Max, item.Data)
+		c.m.items = append(c.m.items, item)
+	case n < 60:
+		if len(c.m.items) == 0 {
+			return
+		}
+		i := c.rng.Intn(len(c.m.items))
+		item := c.m.items[i]
+		c.tr.Delete(item.Min, item.Max, item.Data)
+		c.m.delete(i)
+	case n < 65:
+		// delete an item that does not exist
+		item := c.newItem()
+		c.tr.Delete(item.Min, item.Max, item.Data)
+	case n < 75:
+		if len(c.m.items) == 0 {
+			return
+		}
+		i := c.rng.Intn(len(c.m.items))
+		old := c.m.items[i]
+		item := c.newItem()
+		c.tr.Replace(old.Min, old.Max, old.Data, item.Min, item.Max,
+			item.Data)
+		c.m.items[i] = item
+	case n < 97:
+		c.checkQueries()
+		return
+	case n < 98:
+		items := make([]rtree.Item[float64, int], c.rng.Intn(500))
+		for i := range items {
+			items[i] = c.newItem()
+		}
+		c.tr.LoadBulk(slices.Clone(items))
+		c.m.items = append(c.m.items, items...)
+	case n < 99:
+		if len(c.snaps) < 4 {
+			c.snaps = append(c.snaps, snapshot{c.tr.Copy(), c.m.clone()})
+		}
+	default:
+		if c.rng.Intn(10) == 0 {
+			c.tr.Clear()
+			c.m.items = nil
+		}
+	}
+	if c.tr.Len() != len(c.m.items) {
+		c.fatalf("expected %d items, got %d", len(c.m.items), c.tr.Len())
+	}
+	if c.op%100 == 0 {
+		c.verify(c.tr, c.m, false)
+	}
+}
+
+// checkQueries compares the results of random queries.
+func (c *checker) checkQueries() {
+	c.t.Helper()
+	qmin, qmax := c.randRect()
+	qmax[0] += c.rng.Float64() * 200
+	qmax[1] += c.rng.Float64() * 200
+	var got []int
+	c.tr.Search(qmin, qmax, func(min, max [2]float64, data int) bool {
+		got = append(got, data)
+		return true
+	})
+	var expect []int
+	for _, item := range c.m.items {
+		if item.Min[0] <= qmax[0] && item.Max[0] >= qmin[0] &&
+			item.Min[1] <= qmax[1] && item.Max[1] >= qmin[1] {
+			expect = append(expect, item.Data)
+		}
+	}
+	slices.Sort(got)
+	slices.Sort(expect)
+	if !slices.Equal(got, expect) {
+		c.fatalf("search %v %v: expected %v, got %v", qmin, qmax, expect,
+			got)
+	}
+
+	// nearest neighbors, compared by their distances, because items at the
+	// same distance may be returned in any order
+	p := qmin
+	k := c.rng.Intn(20) + 1
+	var dists []float64
+	c.tr.Nearby(rtree.BoxDist[float64, int](p, p, nil),
+		func(min, max [2]float64, data int, dist float64) bool {
+			dists = append(dists, dist)
+			return len(dists) < k
+		})
+	expectDists := make([]float64, len(c.m.items))
+	for i, item := range c.m.items {
+		expectDists[i] = boxDist(p, item)
+	}
+	slices.Sort(expectDists)
+	expectDists = expectDists[:min(k, len(expectDists))]
+	if !slices.Equal(dists, expectDists) {
+		c.fatalf("nearby %v: expected %v, got %v", p, expectDists, dists)
+	}
+}
+
+// boxDist returns the squared distance from the point to the item, like
+// rtree.BoxDist.
+func boxDist(p [2]float64, item rtree.Item[float64, int]) float64 {
+	var dist float64
+	for axis := 0; axis < 2; axis++ {
+		d := max(item.Min[axis]-p[axis], p[axis]-item.Max[axis], 0)
+		dist += d * d
+	}
+	return dist
+}
+
+// verify compares all items and the bounds of the tree with the model, and
+// validates the structure of the tree.
+func (c *checker) verify(tr *rtree.RTreeGN[float64, int], m *model,
+	scan bool,
+) {
+	c.t.Helper()
+	if err := tr.SanityCheck(); err != nil {
+		c.fatalf("%v", err)
+	}
+	if tr.Len() != len(m.items) {
+		c.fatalf("expected %d items, got %d", len(m.items), tr.Len())
+	}
+	var emin, emax [2]float64
+	for i, item := range m.items {
+		if i == 0 {
+			emin, emax = item.Min, item.Max
+			continue
+		}
+		for axis := 0; axis < 2; axis++ {
+			emin[axis] = min(emin[axis], item.Min[axis])
+			emax[axis] = max(emax[axis], item.Max[axis])
+		}
+	}
+	if gmin, gmax := tr.Bounds(); gmin != emin || gmax != emax {
+		c.fatalf("expected bounds %v %v, got %v %v", emin, emax, gmin, gmax)
+	}
+	if !scan {
+		return
+	}
+	var got []rtree.Item[float64, int]
+	tr.Scan(func(min, max [2]float64, data int) bool {
+		got = append(got, rtree.Item[float64, int]{Min: min, Max: max,
+			Data: data})
+		return true
+	})
+	expect := slices.Clone(m.items)
+	byData := func(a, b rtree.Item[float64, int]) int {
+		return cmp.Compare(a.Data, b.Data)
+	}
+	slices.SortFunc(got, byData)
+	slices.SortFunc(expect, byData)
+	if !slices.Equal(got, expect) {
+		c.fatalf("scan: expected %d items, got %d different items",
+			len(expect), len(got))
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtreetest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/buivuanh/rtree"
+)
+
+func TestCheck(t *testing.T) {
+	for _, opts := range [][]rtree.Option[float64, int]{
+		nil,
+		{rtree.WithOrdering[float64, int](false)},
+		{rtree.WithMinFill[float64, int](40)},
+		{rtree.WithSplitter(rtree.QuadraticSplitter[float64, int]())},
+		{rtree.WithChooseSubtree[float64, int](rtree.LeastOverlap)},
+		{rtree.WithFanout[float64, int](16, 4)},
+	} {
+		Check(t, func() *rtree.RTreeGN[float64, int] {
+			return rtree.New(opts...)
+		})
+	}
+}
+
+// fakeT records the failure of a test.
+type fakeT struct {
+	testing.TB
+	msg string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.msg = format
+	panic(t)
+}
+
+func TestCheckFails(t *testing.T) {
+	// a tree that loses every 100th insert
+	ft := new(fakeT)
+	func() {
+		defer func() {
+			if r := recover(); r != nil && r != ft {
+				panic(r)
+			}
+		}()
+		CheckSeed(ft, 1, func() *rtree.RTreeGN[float64, int] {
+			tr := rtree.New[float64, int]()
+			var inserts int
+			tr.SetLogger(func(op rtree.Op, min, max [2]float64, data int) {
+				if op == rtree.OpInsert {
+					if inserts++; inserts%100 == 0 {
+						tr.Delete(min, max, data)
+					}
+				}
+			})
+			return tr
+		})
+	}()
+	if !strings.HasPrefix(ft.msg, "rtreetest: seed %d, operation %d") {
+		t.Fatalf("expected a failure, got %q", ft.msg)
+	}
+}
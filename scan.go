@@ -0,0 +1,195 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "errors"
+
+var errModified = errors.New("rtree: tree modified during iteration")
+
+// guard wraps an iterator and panics when the tree is modified by the
+// iterator, unless the iterator also stops the iteration.
+// Modifying the tree while it's being traversed is not supported, because the
+// traversal may skip items, return items twice, or read removed nodes.
+// Use ScanMut or ScanDelete for deleting items while scanning.
+func (tr *RTreeGN[N, T]) guard(iter func(min, max [2]N, data T) bool,
+) func(min, max [2]N, data T) bool {
+	gen := tr.gen
+	return func(min, max [2]N, data T) bool {
+		if !iter(min, max, data) {
+			return false
+		}
+		if tr.gen != gen {
+			panic(errModified)
+		}
+		return true
+	}
+}
+
+// ScanMut scans all items in the tree, allowing for the current item to be
+// deleted by returning true for del. Return false for cont to stop scanning.
+// Deletions are applied to each node once the scan has left it, which makes
+// this much faster than collecting the items and deleting them afterwards.
+// Nodes that are shared with copies of the tree are only copied when an item
+// is deleted from them.
+//
+// The iter function must not otherwise modify the tree.
+func (tr *RTreeGN[N, T]) ScanMut(
+	iter func(min, max [2]N, data T) (del, cont bool),
+) {
+	if tr.frozen {
+		panic(ErrFrozen)
+	}
+	if tr.root == nil {
+		return
+	}
+	if writeGuardEnabled {
+		tr.writes.enter()
+		defer tr.writes.exit()
+	}
+	root, deleted, _ := tr.nodeScanMut(tr.root, tr.gen, iter)
+	if deleted == 0 {
+		return
+	}
+	tr.gen++
+	tr.count -= deleted
+	tr.root = root
+	if tr.count == 0 {
+		tr.release(tr.root)
+		tr.root = nil
+		tr.rect = rect[N]{}
+		return
+	}
+	for !tr.root.leaf() && tr.root.count == 1 {
+		root := tr.root
+		tr.root = tr.root.children()[0]
+		root.count = 0
+		tr.release(root)
+	}
+	tr.rect = tr.root.rect()
+	tr.fixAggs()
+}
+
+// nodeScanMut scans the node and returns the node, which is either the same
+// node or a copy when the node is shared and had items deleted, along with
+// the number of deleted items.
+func (tr *RTreeGN[N, T]) nodeScanMut(n *node[N, T], gen uint64,
+	iter func(min, max [2]N, data T) (del, cont bool),
+) (n2 *node[N, T], deleted int, stop bool) {
+	var dels [maxEntries]bool
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count) && !stop; i++ {
+			r := n.rects.at(i)
+			del, cont := iter(r.min, r.max, items[i])
+			if tr.gen != gen {
+				panic(errModified)
+			}
+			if del {
+				if tr.logging() {
+					tr.log(OpDelete, r.min, r.max, items[i])
+				}
+				dels[i] = true
+				deleted++
+			}
+			stop = !cont
+		}
+		if deleted == 0 {
+			return n, 0, stop
+		}
+		tr.cow(&n)
+		items = n.items()
+		seqs := n.seqs()
+		var j int
+		for i := 0; i < int(n.count); i++ {
+			if dels[i] {
+				continue
+			}
+			n.rects.set(j, n.rects.at(i))
+			items[j] = items[i]
+			if seqs != nil {
+				seqs[j] = seqs[i]
+			}
+			j++
+		}
+		for i := j; i < int(n.count); i++ {
+			items[i] = tr.empty
+			if seqs != nil {
+				seqs[i] = 0
+			}
+		}
+		n.count = int16(j)
+		return n, deleted, stop
+	}
+	for i := 0; i < int(n.count) && !stop; i++ {
+		child, cdeleted, cstop := tr.nodeScanMut(n.children()[i], gen, iter)
+		stop = cstop
+		if cdeleted == 0 {
+			continue
+		}
+		tr.cow(&n)
+		n.children()[i] = child
+		dels[i] = true
+		deleted += cdeleted
+	}
+	if deleted == 0 {
+		return n, 0, stop
+	}
+	// Fix the rects of the changed children and remove the empty ones.
+	children := n.children()
+	var j int
+	for i := 0; i < int(n.count); i++ {
+		if children[i].count == 0 {
+			tr.release(children[i])
+			continue
+		}
+		if dels[i] {
+			n.rects.set(i, children[i].rect())
+		}
+		n.rects.set(j, n.rects.at(i))
+		children[j] = children[i]
+		j++
+	}
+	for i := j; i < int(n.count); i++ {
+		children[i] = nil
+	}
+	n.count = int16(j)
+	if n.ordered() && !n.issorted() {
+		n.sort()
+	}
+	return n, deleted, stop
+}
+
+// ScanDelete scans all items in the tree and deletes each item for which the
+// provided function returns true.
+// Returns the number of deleted items.
+//
+// The function must not modify the tree.
+func (tr *RTreeGN[N, T]) ScanDelete(del func(min, max [2]N, data T) bool) int {
+	var n int
+	tr.ScanMut(func(min, max [2]N, data T) (bool, bool) {
+		if del(min, max, data) {
+			n++
+			return true, true
+		}
+		return false, true
+	})
+	return n
+}
+
+// ScanMut scans all items in the tree, allowing for the current item to be
+// deleted by returning true for del. Return false for cont to stop scanning.
+func (tr *RTreeG[T]) ScanMut(
+	iter func(min, max [2]float64, data T) (del, cont bool),
+) {
+	tr.base.ScanMut(iter)
+}
+
+// ScanDelete scans all items in the tree and deletes each item for which the
+// provided function returns true.
+// Returns the number of deleted items.
+func (tr *RTreeG[T]) ScanDelete(del func(min, max [2]float64, data T) bool,
+) int {
+	return tr.base.ScanDelete(del)
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "slices"
+
+// orderKind is the kind of an Order.
+type orderKind int
+
+const (
+	orderMinX orderKind = iota
+	orderDistance
+	orderCustom
+)
+
+// Order is the order of the results of SearchOrdered, which is one of
+// OrderByMinX, OrderByDistance or OrderByCustom.
+type Order[N numeric, T any] struct {
+	kind  orderKind
+	point rect[N]
+	less  func(a, b Item[N, T]) bool
+}
+
+// OrderByMinX orders the items by the min x of their rectangles, from left to
+// right.
+func OrderByMinX[N numeric, T any]() Order[N, T] {
+	return Order[N, T]{kind: orderMinX}
+}
+
+// OrderByDistance orders the items by their distance to the point, nearest
+// first, like Nearby with BoxDist.
+func OrderByDistance[N numeric, T any](p [2]N) Order[N, T] {
+	return Order[N, T]{kind: orderDistance, point: rect[N]{p, p}}
+}
+
+// OrderByCustom orders the items using the less function, which returns true
+// when a comes before b. Items that are neither less than the other keep the
+// order of the tree.
+func OrderByCustom[N numeric, T any](less func(a, b Item[N, T]) bool,
+) Order[N, T] {
+	return Order[N, T]{kind: orderCustom, less: less}
+}
+
+// key returns the sort key of a rectangle, which is never greater than the
+// keys of the items inside of it.
+func (o *Order[N, T]) key(r *rect[N]) float64 {
+	if o.kind == orderDistance {
+		return float64(o.point.boxDist(r))
+	}
+	return float64(r.min[0])
+}
+
+// SearchOrdered searches for items that intersect the provided rectangle, like
+// Search, and returns them in the provided order.
+// For OrderByMinX and OrderByDistance the nodes are visited in order of the
+// smallest key that their items can have, so the first items are returned
+// without visiting the rest of the tree, and nodes whose children are sorted
+// by min x stop at the first child that is to the right of the rectangle.
+// For OrderByCustom all matching items are collected and sorted before
+// calling iter.
+func (tr *RTreeGN[N, T]) SearchOrdered(min, max [2]N, order Order[N, T],
+	iter func(min, max [2]N, data T) bool,
+) {
+	target := rect[N]{min, max}
+	if tr.root == nil || !target.intersects(&tr.rect) {
+		return
+	}
+	iter = tr.guard(iter)
+	if order.kind == orderCustom {
+		var items []Item[N, T]
+		tr.root.search(target, func(min, max [2]N, data T) bool {
+			items = append(items, Item[N, T]{min, max, data})
+			return true
+		})
+		slices.SortStableFunc(items, func(a, b Item[N, T]) int {
+			if order.less(a, b) {
+				return -1
+			}
+			if order.less(b, a) {
+				return 1
+			}
+			return 0
+		})
+		for _, item := range items {
+			if !iter(item.Min, item.Max, item.Data) {
+				return
+			}
+		}
+		return
+	}
+	var q pqueue[topkElem[N, T]]
+	q.push(order.key(&tr.rect), topkElem[N, T]{rect: tr.rect, node: tr.root})
+	for {
+		_, e, ok := q.pop()
+		if !ok {
+			return
+		}
+		if e.node == nil {
+			if !iter(e.rect.min, e.rect.max, e.data) {
+				return
+			}
+			continue
+		}
+		n := e.node
+		ordered := n.ordered()
+		leaf := n.leaf()
+		var items []T
+		var children []*node[N, T]
+		if leaf {
+			items = n.items()
+		} else {
+			children = n.children()
+		}
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if ordered && r.min[0] > target.max[0] {
+				// the remaining rects are all further to the right
+				break
+			}
+			if !target.intersects(&r) {
+				continue
+			}
+			if leaf {
+				q.push(order.key(&r),
+					topkElem[N, T]{rect: r, data: items[i]})
+			} else {
+				q.push(order.key(&r),
+					topkElem[N, T]{rect: r, node: children[i]})
+			}
+		}
+	}
+}
+
+// SearchOrdered searches for items that intersect the provided rectangle and
+// returns them in the provided order. See RTreeGN.SearchOrdered.
+func (tr *RTreeG[T]) SearchOrdered(min, max [2]float64,
+	order Order[float64, T], iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.SearchOrdered(min, max, order, iter)
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+// clipLine clips the line o+t*d, with t from t0 to t1, to the rectangle
+// using the slab method, and returns the range of t that is inside of the
+// rectangle, or false if the line misses it.
+func clipLine[N numeric](o, d [2]float64, r *rect[N], t0, t1 float64,
+) (float64, float64, bool) {
+	for axis := 0; axis < 2; axis++ {
+		min, max := float64(r.min[axis]), float64(r.max[axis])
+		if d[axis] == 0 {
+			// parallel to the slab
+			if o[axis] < min || o[axis] > max {
+				return t0, t1, false
+			}
+			continue
+		}
+		ta := (min - o[axis]) / d[axis]
+		tb := (max - o[axis]) / d[axis]
+		if ta > tb {
+			ta, tb = tb, ta
+		}
+		if ta > t0 {
+			t0 = ta
+		}
+		if tb < t1 {
+			t1 = tb
+		}
+		if t0 > t1 {
+			return t0, t1, false
+		}
+	}
+	return t0, t1, true
+}
+
+// SearchSegment searches for items in the tree whose rectangle is crossed by
+// the line segment from a to b.
+// Unlike searching the bounding box of the segment, this only visits the
+// nodes that the segment actually passes through, which makes a big
+// difference for long diagonal segments.
+func (tr *RTreeGN[N, T]) SearchSegment(a, b [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	if tr.root == nil {
+		return
+	}
+	o := [2]float64{float64(a[0]), float64(a[1])}
+	d := [2]float64{float64(b[0]) - o[0], float64(b[1]) - o[1]}
+	if _, _, ok := clipLine(o, d, &tr.rect, 0, 1); ok {
+		tr.root.searchSegment(o, d, tr.guard(iter))
+	}
+}
+
+func (n *node[N, T]) searchSegment(o, d [2]float64,
+	iter func(min, max [2]N, data T) bool,
+) bool {
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if _, _, ok := clipLine(o, d, &r, 0, 1); ok {
+				if !iter(r.min, r.max, items[i]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		if _, _, ok := clipLine(o, d, &r, 0, 1); ok {
+			if !children[i].searchSegment(o, d, iter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SearchSegment searches for items in the tree whose rectangle is crossed by
+// the line segment from a to b.
+func (tr *RTreeG[T]) SearchSegment(a, b [2]float64,
+	iter func(min, max [2]float64, data T) bool,
+) {
+	tr.base.SearchSegment(a, b, iter)
+}
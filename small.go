@@ -0,0 +1,188 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"slices"
+)
+
+// defaultSmallThreshold is the number of items that a RTreeSmall keeps in a
+// slice, when no threshold is provided.
+const defaultSmallThreshold = 128
+
+// RTreeSmall is an R-tree for a small number of items, such as an index for
+// each tile of a map, where most trees only have a handful of items.
+// The items are kept in a slice that is sorted by the min x of their
+// rectangles, until the number of items exceeds a threshold, and only then is
+// a tree built from them. This avoids allocating full nodes for trees that
+// only hold a few items, and searching a short sorted slice is faster than
+// following the pointers of a tree.
+//
+// The zero value is an empty tree with a threshold of 128 items.
+type RTreeSmall[N numeric, T any] struct {
+	threshold int
+	items     []Item[N, T]   // sorted by min x, when tr is nil
+	tr        *RTreeGN[N, T] // the tree, once the threshold is exceeded
+}
+
+// NewRTreeSmall returns a new tree that keeps up to threshold items in a
+// slice before building a tree.
+func NewRTreeSmall[N numeric, T any](threshold int) *RTreeSmall[N, T] {
+	return &RTreeSmall[N, T]{threshold: max(threshold, 1)}
+}
+
+// Insert data into the tree.
+func (tr *RTreeSmall[N, T]) Insert(min, max [2]N, data T) {
+	if tr.tr != nil {
+		tr.tr.Insert(min, max, data)
+		return
+	}
+	threshold := tr.threshold
+	if threshold == 0 {
+		threshold = defaultSmallThreshold
+	}
+	if len(tr.items) == threshold {
+		tr.tr = new(RTreeGN[N, T])
+		tr.tr.LoadBulk(tr.items)
+		tr.tr.Insert(min, max, data)
+		tr.items = nil
+		return
+	}
+	i, _ := slices.BinarySearchFunc(tr.items, min[0],
+		func(item Item[N, T], x N) int {
+			return cmp.Compare(item.Min[0], x)
+		})
+	tr.items = slices.Insert(tr.items, i, Item[N, T]{min, max, data})
+}
+
+// delete the item and returns false when the item does not exist.
+func (tr *RTreeSmall[N, T]) delete(min, max [2]N, data T) bool {
+	if tr.tr != nil {
+		n := tr.tr.Len()
+		tr.tr.Delete(min, max, data)
+		return tr.tr.Len() < n
+	}
+	for i := range tr.items {
+		if tr.items[i].Min[0] > min[0] {
+			break
+		}
+		if tr.items[i].Min == min && tr.items[i].Max == max &&
+			compare(tr.items[i].Data, data) {
+			tr.items = slices.Delete(tr.items, i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+// Delete data from the tree.
+func (tr *RTreeSmall[N, T]) Delete(min, max [2]N, data T) {
+	tr.delete(min, max, data)
+}
+
+// Replace an item.
+// If the old item does not exist then the new item is not inserted.
+func (tr *RTreeSmall[N, T]) Replace(
+	oldMin, oldMax [2]N, oldData T,
+	newMin, newMax [2]N, newData T,
+) {
+	if tr.delete(oldMin, oldMax, oldData) {
+		tr.Insert(newMin, newMax, newData)
+	}
+}
+
+// Len returns the number of items in the tree.
+func (tr *RTreeSmall[N, T]) Len() int {
+	if tr.tr != nil {
+		return tr.tr.Len()
+	}
+	return len(tr.items)
+}
+
+// Bounds returns the minimum bounding rect.
+func (tr *RTreeSmall[N, T]) Bounds() (min, max [2]N) {
+	if tr.tr != nil {
+		return tr.tr.Bounds()
+	}
+	var r rect[N]
+	for i := range tr.items {
+		ir := rect[N]{tr.items[i].Min, tr.items[i].Max}
+		if i == 0 {
+			r = ir
+		} else {
+			r.expand(&ir)
+		}
+	}
+	return r.min, r.max
+}
+
+// Search for items that intersect the provided rectangle.
+func (tr *RTreeSmall[N, T]) Search(min, max [2]N,
+	iter func(min, max [2]N, data T) bool,
+) {
+	if tr.tr != nil {
+		tr.tr.Search(min, max, iter)
+		return
+	}
+	target := rect[N]{min, max}
+	for _, item := range tr.items {
+		if item.Min[0] > max[0] {
+			// the remaining items are all further to the right
+			break
+		}
+		ir := rect[N]{item.Min, item.Max}
+		if ir.intersects(&target) && !iter(item.Min, item.Max, item.Data) {
+			return
+		}
+	}
+}
+
+// Scan iterates through all items in the tree.
+func (tr *RTreeSmall[N, T]) Scan(iter func(min, max [2]N, data T) bool) {
+	if tr.tr != nil {
+		tr.tr.Scan(iter)
+		return
+	}
+	for _, item := range tr.items {
+		if !iter(item.Min, item.Max, item.Data) {
+			return
+		}
+	}
+}
+
+// Nearby performs a kNN-type operation on the tree, like RTreeGN.Nearby.
+func (tr *RTreeSmall[N, T]) Nearby(
+	dist func(min, max [2]N, data T, item bool) N,
+	iter func(min, max [2]N, data T, dist N) bool,
+) {
+	if tr.tr != nil {
+		tr.tr.Nearby(dist, iter)
+		return
+	}
+	type nearItem struct {
+		item *Item[N, T]
+		dist N
+	}
+	near := make([]nearItem, len(tr.items))
+	for i := range tr.items {
+		item := &tr.items[i]
+		near[i] = nearItem{item, dist(item.Min, item.Max, item.Data, true)}
+	}
+	slices.SortStableFunc(near, func(a, b nearItem) int {
+		return cmp.Compare(a.dist, b.dist)
+	})
+	for _, n := range near {
+		if !iter(n.item.Min, n.item.Max, n.item.Data, n.dist) {
+			return
+		}
+	}
+}
+
+// Clear will delete all items.
+func (tr *RTreeSmall[N, T]) Clear() {
+	tr.items = nil
+	tr.tr = nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// TimedItem is an item of a SpatioTemporalTree, which has a time range
+// along with its rectangle.
+type TimedItem[T any] struct {
+	Min, Max [2]float64
+	From, To time.Time
+	Data     T
+}
+
+// stItem is the data of an item in the tree, with its time range in unix
+// milliseconds.
+type stItem[T any] struct {
+	from, to int64
+	data     T
+}
+
+// SpatioTemporalTree is an R-tree where each item has a time range, such as
+// the time of an event or the time span of a part of a trajectory, along
+// with its rectangle. Searches are limited to a time window as well as an
+// area.
+//
+// The time range of every node is maintained as an aggregate, so searches
+// skip the nodes that are outside of the time window, like a third
+// dimension. Times are kept with millisecond precision.
+//
+// The zero value is an empty tree that is ready to use.
+type SpatioTemporalTree[T any] struct {
+	base  RTreeGN[float64, stItem[T]]
+	times *aggIndex[float64, stItem[T], [2]int64]
+}
+
+// init registers the aggregator for the time ranges of the nodes.
+func (tr *SpatioTemporalTree[T]) init() {
+	if tr.times != nil {
+		return
+	}
+	tr.times = &aggIndex[float64, stItem[T], [2]int64]{
+		agg: Aggregator[stItem[T], [2]int64]{
+			Item: func(data stItem[T]) [2]int64 {
+				return [2]int64{data.from, data.to}
+			},
+			Merge: func(a, b [2]int64) [2]int64 {
+				return [2]int64{min(a[0], b[0]), max(a[1], b[1])}
+			},
+		},
+	}
+	tr.base.addAggregator(tr.times, &tr.times.idx)
+}
+
+// timeRange returns the time range in unix milliseconds, with from and to
+// swapped when to is before from.
+func timeRange(from, to time.Time) (int64, int64) {
+	a, b := from.UnixMilli(), to.UnixMilli()
+	return min(a, b), max(a, b)
+}
+
+// Insert an item with the provided time range, which is a single instant
+// when from and to are the same.
+func (tr *SpatioTemporalTree[T]) Insert(min, max [2]float64,
+	from, to time.Time, data T,
+) {
+	tr.init()
+	a, b := timeRange(from, to)
+	tr.base.Insert(min, max, stItem[T]{a, b, data})
+}
+
+// Delete an item. The rectangle, time range and data must be the same as
+// when the item was inserted.
+func (tr *SpatioTemporalTree[T]) Delete(min, max [2]float64,
+	from, to time.Time, data T,
+) {
+	a, b := timeRange(from, to)
+	tr.base.Delete(min, max, stItem[T]{a, b, data})
+}
+
+// LoadBulk adds the items to the tree. This is much faster than inserting
+// each item one at a time.
+// The items are sorted by time and cut into slabs of time, and the items of
+// each slab are packed by area like LoadBulk of RTreeGN does. This is the
+// Sort-Tile-Recursive algorithm in three dimensions, which keeps the time
+// ranges of the nodes narrow for searching short time windows.
+func (tr *SpatioTemporalTree[T]) LoadBulk(items []TimedItem[T]) {
+	tr.init()
+	bitems := make([]Item[float64, stItem[T]], len(items))
+	for i := range items {
+		a, b := timeRange(items[i].From, items[i].To)
+		bitems[i] = Item[float64, stItem[T]]{items[i].Min, items[i].Max,
+			stItem[T]{a, b, items[i].Data}}
+	}
+	slices.SortStableFunc(bitems, func(a, b Item[float64, stItem[T]]) int {
+		return cmp.Compare(a.Data.from/2+a.Data.to/2, b.Data.from/2+b.Data.to/2)
+	})
+	fanout := tr.base.fanout(true)
+	nleaves := (len(bitems) + fanout - 1) / fanout
+	nslabs := int(math.Ceil(math.Cbrt(float64(nleaves))))
+	slabSize := (len(bitems) + nslabs - 1) / max(nslabs, 1)
+	for i := 0; i < len(bitems); i += slabSize {
+		tr.base.importChunk(bitems[i:min(i+slabSize, len(bitems))])
+	}
+}
+
+// Len returns the number of items in the tree.
+func (tr *SpatioTemporalTree[T]) Len() int {
+	return tr.base.Len()
+}
+
+// Bounds returns the minimum bounding rect of all items.
+func (tr *SpatioTemporalTree[T]) Bounds() (min, max [2]float64) {
+	return tr.base.Bounds()
+}
+
+// TimeBounds returns the earliest and latest time of all items.
+// Returns false when the tree is empty.
+func (tr *SpatioTemporalTree[T]) TimeBounds() (from, to time.Time, ok bool) {
+	if tr.base.root == nil {
+		return from, to, false
+	}
+	r := tr.base.root.aggs.vals[tr.times.idx].([2]int64)
+	return time.UnixMilli(r[0]), time.UnixMilli(r[1]), true
+}
+
+// SearchWindow searches for items that intersect the provided rectangle and
+// whose time range overlaps the window from from to to, inclusive.
+func (tr *SpatioTemporalTree[T]) SearchWindow(min, max [2]float64,
+	from, to time.Time,
+	iter func(min, max [2]float64, from, to time.Time, data T) bool,
+) {
+	if tr.base.root == nil {
+		return
+	}
+	a, b := timeRange(from, to)
+	target := rect[float64]{min, max}
+	if !target.intersects(&tr.base.rect) {
+		return
+	}
+	tr.searchWindow(tr.base.root, &target, a, b, tr.base.guard(
+		func(min, max [2]float64, item stItem[T]) bool {
+			return iter(min, max, time.UnixMilli(item.from),
+				time.UnixMilli(item.to), item.data)
+		}))
+}
+
+func (tr *SpatioTemporalTree[T]) searchWindow(n *node[float64, stItem[T]],
+	target *rect[float64], from, to int64,
+	iter func(min, max [2]float64, item stItem[T]) bool,
+) bool {
+	if n.leaf() {
+		items := n.items()
+		for i := 0; i < int(n.count); i++ {
+			r := n.rects.at(i)
+			if items[i].to < from || items[i].from > to ||
+				!target.intersects(&r) {
+				continue
+			}
+			if !iter(r.min, r.max, items[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	children := n.children()
+	for i := 0; i < int(n.count); i++ {
+		r := n.rects.at(i)
+		times := children[i].aggs.vals[tr.times.idx].([2]int64)
+		if times[1] < from || times[0] > to || !target.intersects(&r) {
+			continue
+		}
+		if !tr.searchWindow(children[i], target, from, to, iter) {
+			return false
+		}
+	}
+	return true
+}
+
+// Scan iterates through all items in the tree.
+func (tr *SpatioTemporalTree[T]) Scan(
+	iter func(min, max [2]float64, from, to time.Time, data T) bool,
+) {
+	tr.base.Scan(func(min, max [2]float64, item stItem[T]) bool {
+		return iter(min, max, time.UnixMilli(item.from),
+			time.UnixMilli(item.to), item.data)
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import (
+	"slices"
+	"sync"
+)
+
+// subscriptionBuffer is the size of the channel of a subscription.
+const subscriptionBuffer = 256
+
+// ChangeEvent is a change to an item in a tree, as sent to the subscribers of
+// the tree, see Subscribe.
+type ChangeEvent[N numeric, T any] struct {
+	Op       Op
+	Min, Max [2]N
+	Data     T
+	// Missed is the number of events that were dropped before this one,
+	// because the channel of the subscription was full.
+	Missed int
+}
+
+type subscription[N numeric, T any] struct {
+	target rect[N]
+	ch     chan ChangeEvent[N, T]
+	missed int
+}
+
+type subscriptions[N numeric, T any] struct {
+	mu   sync.Mutex
+	subs []*subscription[N, T]
+}
+
+// Subscribe returns a channel that receives an event for every item that is
+// inserted into or deleted from the tree, and that intersects the provided
+// rectangle, such as for updating a live map view. Replace sends the delete
+// of the old item and the insert of the new item, when either intersects the
+// rectangle. Clear sends an OpClear with zero values to every subscriber.
+//
+// Writes to the tree never wait for the subscribers. When the channel is
+// full the event is dropped, and the number of dropped events is reported by
+// the Missed field of the next event that is sent.
+// The cancel function stops the subscription and closes the channel.
+// Subscribe must not be called while the tree is being modified, but cancel
+// may be called by any goroutine at any time.
+// Copies of the tree share the same subscriptions.
+func (tr *RTreeGN[N, T]) Subscribe(min, max [2]N,
+) (events <-chan ChangeEvent[N, T], cancel func()) {
+	if tr.subs == nil {
+		tr.subs = new(subscriptions[N, T])
+	}
+	subs := tr.subs
+	sub := &subscription[N, T]{
+		target: rect[N]{min, max},
+		ch:     make(chan ChangeEvent[N, T], subscriptionBuffer),
+	}
+	subs.mu.Lock()
+	subs.subs = append(subs.subs, sub)
+	subs.mu.Unlock()
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			subs.mu.Lock()
+			subs.subs = slices.DeleteFunc(subs.subs,
+				func(s *subscription[N, T]) bool { return s == sub })
+			subs.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// notify sends the event to the subscriptions whose rectangles intersect the
+// item, or to all subscriptions for an OpClear.
+func (subs *subscriptions[N, T]) notify(op Op, min, max [2]N, data T) {
+	r := rect[N]{min, max}
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	for _, sub := range subs.subs {
+		if op != OpClear && !sub.target.intersects(&r) {
+			continue
+		}
+		select {
+		case sub.ch <- ChangeEvent[N, T]{op, min, max, data, sub.missed}:
+			sub.missed = 0
+		default:
+			sub.missed++
+		}
+	}
+}
+
+// Subscribe returns a channel that receives an event for every item that is
+// inserted into or deleted from the tree, and that intersects the provided
+// rectangle. See RTreeGN.Subscribe.
+func (tr *RTreeG[T]) Subscribe(min, max [2]float64,
+) (events <-chan ChangeEvent[float64, T], cancel func()) {
+	return tr.base.Subscribe(min, max)
+}
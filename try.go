@@ -0,0 +1,84 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtree
+
+import "errors"
+
+// ErrNotFound is returned by TryDelete and TryReplace when the item does not
+// exist in the tree.
+var ErrNotFound = errors.New("rtree: item not found")
+
+// TryInsert is like Insert, but returns an error instead of panicking or
+// inserting an invalid item. Returns ErrFrozen for a frozen tree,
+// ErrInvalidRect for an invalid rectangle, whether or not the tree is in
+// strict mode, and ErrCapacity when the tree is at its maximum number of
+// items or memory.
+func (tr *RTreeGN[N, T]) TryInsert(min, max [2]N, data T) error {
+	if tr.frozen {
+		return ErrFrozen
+	}
+	if !validRect(min, max) {
+		return ErrInvalidRect
+	}
+	if err := tr.checkCapacity(); err != nil {
+		return err
+	}
+	tr.insert(min, max, data, 0)
+	return nil
+}
+
+// TryDelete is like Delete, but returns ErrFrozen for a frozen tree and
+// ErrNotFound when the item does not exist.
+func (tr *RTreeGN[N, T]) TryDelete(min, max [2]N, data T) error {
+	if tr.frozen {
+		return ErrFrozen
+	}
+	if !tr.delete(min, max, data, 0) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TryReplace is like Replace, but returns ErrFrozen for a frozen tree,
+// ErrInvalidRect when the new rectangle is invalid, and ErrNotFound when the
+// old item does not exist. The tree is not modified when an error is
+// returned.
+func (tr *RTreeGN[N, T]) TryReplace(
+	oldMin, oldMax [2]N, oldData T,
+	newMin, newMax [2]N, newData T,
+) error {
+	if tr.frozen {
+		return ErrFrozen
+	}
+	if !validRect(newMin, newMax) {
+		return ErrInvalidRect
+	}
+	if !tr.delete(oldMin, oldMax, oldData, 0) {
+		return ErrNotFound
+	}
+	tr.insert(newMin, newMax, newData, 0)
+	return nil
+}
+
+// TryInsert is like Insert, but returns an error instead of panicking or
+// inserting an invalid item.
+func (tr *RTreeG[T]) TryInsert(min, max [2]float64, data T) error {
+	return tr.base.TryInsert(min, max, data)
+}
+
+// TryDelete is like Delete, but returns an error when the item does not
+// exist or the tree is frozen.
+func (tr *RTreeG[T]) TryDelete(min, max [2]float64, data T) error {
+	return tr.base.TryDelete(min, max, data)
+}
+
+// TryReplace is like Replace, but returns an error instead of panicking or
+// silently doing nothing.
+func (tr *RTreeG[T]) TryReplace(
+	oldMin, oldMax [2]float64, oldData T,
+	newMin, newMax [2]float64, newData T,
+) error {
+	return tr.base.TryReplace(oldMin, oldMax, oldData, newMin, newMax, newData)
+}
@@ -0,0 +1,18 @@
+// Copyright 2021 Joshua J Baker. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !rtreedebug
+
+package rtree
+
+// writeGuardEnabled is false without the rtreedebug tag, so that the writes
+// compile out the calls to the write guard, along with their defers.
+const writeGuardEnabled = false
+
+// writeGuard detects concurrent writes to a tree when built with the
+// rtreedebug tag. Otherwise it's empty and costs nothing.
+type writeGuard struct{}
+
+func (g *writeGuard) enter() {}
+func (g *writeGuard) exit()  {}